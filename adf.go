@@ -0,0 +1,642 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"html"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// Atlassian Document Format（ADF）のMarkdown変換
+//
+// Jira Cloudの /rest/api/3 は説明・コメント・複数行テキストのカスタムフィールドをADF（JSON）で返す。
+// ADFにはWiki記法では表現できないノード（決定事項、タスクリスト、展開ブロック、絵文字等）があるため、
+// Wiki記法（/rest/api/2）を経由せずにADFから直接Markdownに変換する。
+// パネル・ステータス・メンションはWiki記法の変換と同じCSSクラス（panel-*、status-label-*、mention）で出力する。
+// 仕様: https://developer.atlassian.com/cloud/jira/platform/apis/document/structure/
+
+// ADFNode はADFのノード（ドキュメント、ブロック、インライン）
+type ADFNode struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version,omitempty"` // docノードのみ
+	Text    string                 `json:"text,omitempty"`    // textノードのみ
+	Attrs   map[string]interface{} `json:"attrs,omitempty"`
+	Marks   []ADFMark              `json:"marks,omitempty"`
+	Content []*ADFNode             `json:"content,omitempty"`
+}
+
+// ADFMark はtextノードの書式（太字、リンク等）
+type ADFMark struct {
+	Type  string                 `json:"type"`
+	Attrs map[string]interface{} `json:"attrs,omitempty"`
+}
+
+// IssueADF は課題の説明とコメント本文のADF（display.adf = true の場合に /rest/api/3 から取得）
+type IssueADF struct {
+	Description *ADFNode            `json:"description,omitempty"`
+	Comments    map[string]*ADFNode `json:"comments,omitempty"` // コメントID → 本文
+}
+
+// textareaFieldType は複数行テキストのカスタムフィールドのスキーマ（ADFで返るカスタムフィールド）
+const textareaFieldType = "com.atlassian.jira.plugin.system.customfieldtypes:textarea"
+
+// textareaFieldIDs は複数行テキストのカスタムフィールドのIDを返す
+func textareaFieldIDs(fields []cloud.Field) []string {
+	var ids []string
+	for _, field := range fields {
+		if field.Custom && field.Schema.Custom == textareaFieldType {
+			ids = append(ids, field.ID)
+		}
+	}
+	return ids
+}
+
+// parseADF は値がADFのドキュメントの場合にADFNodeとして返す
+// JSONをデコードしたmap、JSON文字列、json.RawMessageを受け付ける
+func parseADF(value interface{}) (*ADFNode, bool) {
+	var data []byte
+	switch v := value.(type) {
+	case *ADFNode:
+		return v, v != nil && v.Type == "doc"
+	case map[string]interface{}:
+		if v["type"] != "doc" {
+			return nil, false
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, false
+		}
+		data = b
+	case string:
+		trimmed := strings.TrimSpace(v)
+		if !strings.HasPrefix(trimmed, "{") || !strings.Contains(trimmed, `"doc"`) {
+			return nil, false
+		}
+		data = []byte(trimmed)
+	case json.RawMessage:
+		data = v
+	default:
+		return nil, false
+	}
+
+	var node ADFNode
+	if err := json.Unmarshal(data, &node); err != nil || node.Type != "doc" {
+		return nil, false
+	}
+	return &node, true
+}
+
+// adfAttr は属性の値を文字列で返す（存在しない場合は空文字列）
+func adfAttr(attrs map[string]interface{}, key string) string {
+	switch v := attrs[key].(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// adfInlineTypes はインラインノードの種類
+var adfInlineTypes = map[string]bool{
+	"text":        true,
+	"hardBreak":   true,
+	"mention":     true,
+	"emoji":       true,
+	"inlineCard":  true,
+	"status":      true,
+	"date":        true,
+	"mediaInline": true,
+	"placeholder": true,
+}
+
+// adfPlainText はノードのテキストを書式なしで連結する（ブロックの区切りは改行）
+func adfPlainText(node *ADFNode) string {
+	var sb strings.Builder
+	var walk func(n *ADFNode)
+	walk = func(n *ADFNode) {
+		switch n.Type {
+		case "text":
+			sb.WriteString(n.Text)
+		case "hardBreak":
+			sb.WriteString("\n")
+		case "mention", "emoji", "status":
+			sb.WriteString(adfAttr(n.Attrs, "text"))
+		case "inlineCard":
+			sb.WriteString(adfAttr(n.Attrs, "url"))
+		}
+		for i, child := range n.Content {
+			if i > 0 && !adfInlineTypes[child.Type] {
+				sb.WriteString("\n")
+			}
+			walk(child)
+		}
+	}
+	walk(node)
+	return sb.String()
+}
+
+// adfStartsWithMention はドキュメントがメンションで始まるかどうかを返す（コメントの返信の判定に使用する）
+func adfStartsWithMention(doc *ADFNode) bool {
+	if len(doc.Content) == 0 || doc.Content[0].Type != "paragraph" || len(doc.Content[0].Content) == 0 {
+		return false
+	}
+	return doc.Content[0].Content[0].Type == "mention"
+}
+
+// adfPanelClassMap はADFのパネルの種類をCSSクラス名にマッピング
+var adfPanelClassMap = map[string]string{
+	"info":    "panel-info",
+	"note":    "panel-note",
+	"warning": "panel-warning",
+	"success": "panel-success",
+	"error":   "panel-error",
+}
+
+// adfStatusColorMap はADFのステータスの色をCSSクラス名にマッピング
+var adfStatusColorMap = map[string]string{
+	"neutral": "status-label-gray",
+	"purple":  "status-label-purple",
+	"blue":    "status-label-teal",
+	"red":     "status-label-danger",
+	"yellow":  "status-label-warning",
+	"green":   "status-label-success",
+}
+
+// adfRenderer はADFをMarkdownに変換する
+type adfRenderer struct {
+	mw            *MarkdownWriter
+	attachmentMap map[string]string
+}
+
+// convertADFToMarkdown はADFのドキュメントをMarkdownに変換する
+// 添付ファイル（media）はattachmentMapで保存されたファイルへのリンクに変換する
+func (mw *MarkdownWriter) convertADFToMarkdown(doc *ADFNode, attachmentMap map[string]string) string {
+	r := &adfRenderer{mw: mw, attachmentMap: attachmentMap}
+	return strings.TrimSpace(r.blocks(doc.Content, "\n\n"))
+}
+
+// blocks はブロックノードを変換してsepで連結する
+func (r *adfRenderer) blocks(nodes []*ADFNode, sep string) string {
+	parts := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		if s := r.block(node); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// block はブロックノードを変換する
+func (r *adfRenderer) block(node *ADFNode) string {
+	switch node.Type {
+	case "paragraph", "caption":
+		return r.inline(node.Content)
+	case "heading":
+		level, _ := strconv.Atoi(adfAttr(node.Attrs, "level"))
+		level = min(max(level, 1), 6)
+		return strings.Repeat("#", level) + " " + r.inline(node.Content)
+	case "bulletList":
+		return r.list(node, false)
+	case "orderedList":
+		return r.list(node, true)
+	case "taskList":
+		return r.taskList(node)
+	case "decisionList":
+		return r.decisionList(node)
+	case "codeBlock":
+		code := adfPlainText(node)
+		fence := codeFence(code)
+		return fmt.Sprintf("%s%s\n%s\n%s", fence, adfAttr(node.Attrs, "language"), code, fence)
+	case "blockquote":
+		return quoteLines(r.blocks(node.Content, "\n\n"))
+	case "rule":
+		return "---"
+	case "panel":
+		return r.panel(node)
+	case "expand", "nestedExpand":
+		title := adfAttr(node.Attrs, "title")
+		return fmt.Sprintf("<details>\n<summary>%s</summary>\n\n%s\n\n</details>", html.EscapeString(title), r.blocks(node.Content, "\n\n"))
+	case "table":
+		return r.table(node)
+	case "mediaSingle", "mediaGroup":
+		return r.blocks(node.Content, "\n")
+	case "media":
+		return r.media(node)
+	case "blockCard", "embedCard":
+		cardURL := adfAttr(node.Attrs, "url")
+		if cardURL == "" {
+			return ""
+		}
+		return fmt.Sprintf("[%s](%s)", cardURL, cardURL)
+	case "extension", "inlineExtension":
+		// Marketplaceアプリのマクロ等は内容を取得できないため出力しない
+		return ""
+	default:
+		if adfInlineTypes[node.Type] {
+			return r.inline([]*ADFNode{node})
+		}
+		// layoutSection、layoutColumn、bodiedExtension、未知のブロックは子要素を出力する
+		return r.blocks(node.Content, "\n\n")
+	}
+}
+
+// inline はインラインノードを変換する
+func (r *adfRenderer) inline(nodes []*ADFNode) string {
+	var sb strings.Builder
+	for i, node := range nodes {
+		switch node.Type {
+		case "text":
+			text := applyADFMarks(node.Text, node.Marks)
+			// 行頭のテキストは見出し・リスト等の記法として解釈されないようにする
+			if i == 0 || nodes[i-1].Type == "hardBreak" {
+				text = escapeMarkdownLineStart(text)
+			}
+			sb.WriteString(text)
+		case "hardBreak":
+			sb.WriteString("  \n")
+		case "mention":
+			sb.WriteString(r.mention(node))
+		case "emoji":
+			if text := adfAttr(node.Attrs, "text"); text != "" {
+				sb.WriteString(escapeMarkdownText(text))
+			} else {
+				sb.WriteString(escapeMarkdownText(adfAttr(node.Attrs, "shortName")))
+			}
+		case "inlineCard":
+			if cardURL := adfAttr(node.Attrs, "url"); cardURL != "" {
+				sb.WriteString(fmt.Sprintf("[%s](%s)", cardURL, cardURL))
+			}
+		case "status":
+			sb.WriteString(adfStatus(node))
+		case "date":
+			sb.WriteString(formatADFDate(adfAttr(node.Attrs, "timestamp")))
+		case "mediaInline":
+			sb.WriteString(r.media(node))
+		case "placeholder":
+			// 入力欄のプレースホルダーは本文ではないため出力しない
+		default:
+			if len(node.Content) > 0 {
+				sb.WriteString(r.inline(node.Content))
+			} else {
+				sb.WriteString(escapeMarkdownText(node.Text))
+			}
+		}
+	}
+	return sb.String()
+}
+
+// applyADFMarks はtextノードの書式をMarkdown（またはHTML）で適用する
+// テキストはMarkdownの記法・HTMLタグとして解釈されないようにエスケープする（コードは除く）
+// 前後の空白は書式の外に出す（**text ** のような閉じられない強調を防ぐ）
+func applyADFMarks(text string, marks []ADFMark) string {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" || len(marks) == 0 {
+		return escapeMarkdownText(text)
+	}
+	leading := text[:strings.Index(text, trimmed)]
+	trailing := text[len(leading)+len(trimmed):]
+
+	s := escapeMarkdownText(trimmed)
+	// コードは最も内側に適用する
+	for _, mark := range marks {
+		if mark.Type == "code" {
+			s = inlineCode(trimmed)
+		}
+	}
+
+	linkHref := ""
+	for _, mark := range marks {
+		switch mark.Type {
+		case "strong":
+			s = "**" + s + "**"
+		case "em":
+			s = "*" + s + "*"
+		case "strike":
+			s = "~~" + s + "~~"
+		case "underline":
+			s = "<u>" + s + "</u>"
+		case "subsup":
+			if adfAttr(mark.Attrs, "type") == "sub" {
+				s = "<sub>" + s + "</sub>"
+			} else {
+				s = "<sup>" + s + "</sup>"
+			}
+		case "textColor":
+			// 不正な色は属性を閉じてしまうため、色を付けずに中身のみ出力する
+			if color := adfAttr(mark.Attrs, "color"); isSafeColor(color) {
+				s = fmt.Sprintf(`<span style="color:%s">%s</span>`, color, s)
+			}
+		case "backgroundColor":
+			if color := adfAttr(mark.Attrs, "color"); isSafeColor(color) {
+				s = fmt.Sprintf(`<span style="background-color:%s">%s</span>`, color, s)
+			}
+		case "link":
+			// javascript: 等のURLはリンクにせずテキストのみ出力する
+			if href := adfAttr(mark.Attrs, "href"); isSafeURL(href) {
+				linkHref = href
+			}
+		}
+	}
+	// リンクは最も外側に適用する
+	if linkHref != "" {
+		s = fmt.Sprintf("[%s](%s)", s, linkHref)
+	}
+	return leading + s + trailing
+}
+
+// inlineCode はインラインコードを返す（バッククォートを含む場合は2個で囲む）
+func inlineCode(s string) string {
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+// codeFence はコードブロックの囲みを返す（中身のバッククォートの連続より1個長くする）
+func codeFence(code string) string {
+	longest, run := 0, 0
+	for _, c := range code {
+		if c == '`' {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return strings.Repeat("`", max(3, longest+1))
+}
+
+// escapeMarkdownLineStart は行頭の見出し・リスト・区切り線の記号をエスケープする
+func escapeMarkdownLineStart(s string) string {
+	trimmed := strings.TrimLeft(s, " ")
+	indent := s[:len(s)-len(trimmed)]
+	if trimmed == "" {
+		return s
+	}
+	switch trimmed[0] {
+	case '#', '+', '-', '=':
+		return indent + `\` + trimmed
+	}
+	// 番号付きリスト（1. や 1)）
+	digits := len(trimmed) - len(strings.TrimLeft(trimmed, "0123456789"))
+	if digits > 0 && digits < len(trimmed) && (trimmed[digits] == '.' || trimmed[digits] == ')') {
+		return indent + trimmed[:digits] + `\` + trimmed[digits:]
+	}
+	return s
+}
+
+// escapeADFTableCell はテーブルのセルの区切り（|）をエスケープする
+// テキストの | はエスケープ済みのため、エスケープされていない | のみをエスケープする
+func escapeADFTableCell(s string) string {
+	var sb strings.Builder
+	backslashes := 0
+	for _, c := range s {
+		if c == '|' && backslashes%2 == 0 {
+			sb.WriteByte('\\')
+		}
+		if c == '\\' {
+			backslashes++
+		} else {
+			backslashes = 0
+		}
+		sb.WriteRune(c)
+	}
+	return tableCellLineBreakPattern.ReplaceAllString(strings.TrimSpace(sb.String()), "<br>")
+}
+
+// mention はメンションを変換する（ユーザーマッピングに表示名がある場合はそれを使用する）
+func (r *adfRenderer) mention(node *ADFNode) string {
+	accountID := adfAttr(node.Attrs, "id")
+	name := strings.TrimPrefix(adfAttr(node.Attrs, "text"), "@")
+	if userName, exists := r.mw.userMapping[accountID]; exists && userName != "" {
+		name = userName
+	}
+	if name == "" {
+		name = accountID
+	}
+	return `<span class="mention">@` + escapeMarkdownText(name) + `</span>`
+}
+
+// adfStatus はステータスをカスタムステータスラベルと同じHTMLスパンに変換する
+func adfStatus(node *ADFNode) string {
+	text := escapeMarkdownText(adfAttr(node.Attrs, "text"))
+	if className, ok := adfStatusColorMap[adfAttr(node.Attrs, "color")]; ok {
+		return fmt.Sprintf(`<span class="status-label %s">%s</span>`, className, text)
+	}
+	return fmt.Sprintf(`<span class="status-label">%s</span>`, text)
+}
+
+// formatADFDate は日付ノードのタイムスタンプ（UNIXミリ秒）を YYYY-MM-DD 形式に変換する
+func formatADFDate(timestamp string) string {
+	ms, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return timestamp
+	}
+	return time.UnixMilli(ms).UTC().Format("2006-01-02")
+}
+
+// media は添付ファイル・外部画像を変換する
+// 添付ファイルはファイル名（alt属性）で保存されたファイルを探す
+func (r *adfRenderer) media(node *ADFNode) string {
+	alt := adfAttr(node.Attrs, "alt")
+	if adfAttr(node.Attrs, "type") == "external" {
+		src := adfAttr(node.Attrs, "url")
+		if src == "" || !isSafeURL(src) {
+			return alt
+		}
+		return fmt.Sprintf("![%s](%s)", alt, markdownURL(src))
+	}
+	if alt == "" {
+		return "📎 " + r.mw.msg("attachment")
+	}
+	if link, ok := attachmentLink(alt, r.attachmentMap); ok {
+		return link
+	}
+	return "📎 " + alt
+}
+
+// panel はパネルをWiki記法の{panel}と同じCSSクラスのdivに変換する
+// 中身をMarkdownとして解釈させるため、divの開始・終了タグと中身の間を空行で区切る
+func (r *adfRenderer) panel(node *ADFNode) string {
+	panelClass, ok := adfPanelClassMap[adfAttr(node.Attrs, "panelType")]
+	if !ok {
+		panelClass = "panel-info"
+		if color := adfAttr(node.Attrs, "panelColor"); color != "" {
+			panelClass = getPanelClass(color)
+		}
+	}
+	return fmt.Sprintf("<div class=\"panel %s\"><div class=\"panel-body\">\n\n%s\n\n</div></div>", panelClass, r.blocks(node.Content, "\n\n"))
+}
+
+// list は箇条書き・番号付きリストを変換する
+func (r *adfRenderer) list(node *ADFNode, ordered bool) string {
+	start := 1
+	if ordered {
+		if order, err := strconv.Atoi(adfAttr(node.Attrs, "order")); err == nil && order >= 0 {
+			start = order
+		}
+	}
+
+	items := make([]string, 0, len(node.Content))
+	for i, item := range node.Content {
+		marker := "- "
+		if ordered {
+			marker = fmt.Sprintf("%d. ", start+i)
+		}
+		items = append(items, marker+indentContinuation(r.listItem(item), len(marker)))
+	}
+	return strings.Join(items, "\n")
+}
+
+// listItem はリスト項目の中身を変換する（入れ子のリストは空行なしで続ける）
+func (r *adfRenderer) listItem(item *ADFNode) string {
+	var sb strings.Builder
+	for i, child := range item.Content {
+		s := r.block(child)
+		if s == "" {
+			continue
+		}
+		if i > 0 {
+			switch child.Type {
+			case "bulletList", "orderedList", "taskList":
+				sb.WriteString("\n")
+			default:
+				sb.WriteString("\n\n")
+			}
+		}
+		sb.WriteString(s)
+	}
+	return sb.String()
+}
+
+// taskList はタスクリストをチェックボックス付きのリストに変換する
+// 入れ子のタスクリストは直前の項目の子として字下げする
+func (r *adfRenderer) taskList(node *ADFNode) string {
+	var items []string
+	for _, child := range node.Content {
+		switch child.Type {
+		case "taskItem":
+			box := "[ ]"
+			if adfAttr(child.Attrs, "state") == "DONE" {
+				box = "[x]"
+			}
+			items = append(items, "- "+box+" "+indentContinuation(r.inline(child.Content), 2))
+		case "taskList":
+			items = append(items, indentLines(r.taskList(child), 2))
+		}
+	}
+	return strings.Join(items, "\n")
+}
+
+// decisionList は決定事項のリストを📌付きのリストに変換する
+func (r *adfRenderer) decisionList(node *ADFNode) string {
+	var items []string
+	for _, child := range node.Content {
+		if child.Type == "decisionItem" {
+			items = append(items, "- 📌 "+indentContinuation(r.inline(child.Content), 2))
+		}
+	}
+	return strings.Join(items, "\n")
+}
+
+// table は表をMarkdownのテーブルに変換する
+// 先頭行がすべて見出しセルの場合は見出し行とし、それ以外は空の見出し行を付ける（Wiki記法の変換と同じ）
+func (r *adfRenderer) table(node *ADFNode) string {
+	var rows [][]string
+	hasHeader := false
+	for _, row := range node.Content {
+		if row.Type != "tableRow" {
+			continue
+		}
+		allHeader := len(row.Content) > 0
+		var cells []string
+		for _, cell := range row.Content {
+			if cell.Type != "tableHeader" {
+				allHeader = false
+			}
+			cells = append(cells, escapeADFTableCell(r.blocks(cell.Content, "\n")))
+			// 結合セルは空のセルで埋める
+			if colspan, err := strconv.Atoi(adfAttr(cell.Attrs, "colspan")); err == nil {
+				for j := 1; j < colspan; j++ {
+					cells = append(cells, "")
+				}
+			}
+		}
+		if len(rows) == 0 {
+			hasHeader = allHeader
+		}
+		rows = append(rows, cells)
+	}
+	if len(rows) == 0 {
+		return ""
+	}
+
+	width := 0
+	for _, cells := range rows {
+		width = max(width, len(cells))
+	}
+	for i := range rows {
+		for len(rows[i]) < width {
+			rows[i] = append(rows[i], "")
+		}
+	}
+
+	header := make([]string, width)
+	for i := range header {
+		header[i] = " "
+	}
+	if hasHeader {
+		header, rows = rows[0], rows[1:]
+	}
+	separators := make([]string, width)
+	for i := range separators {
+		separators[i] = "------"
+	}
+
+	lines := []string{
+		"| " + strings.Join(header, " | ") + " |",
+		"| " + strings.Join(separators, " | ") + " |",
+	}
+	for _, cells := range rows {
+		lines = append(lines, "| "+strings.Join(cells, " | ")+" |")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// quoteLines は各行を引用（> ）にする
+func quoteLines(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			lines[i] = ">"
+		} else {
+			lines[i] = "> " + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentContinuation は2行目以降を字下げする（リスト項目の続きの行）
+func indentContinuation(text string, width int) string {
+	lines := strings.Split(text, "\n")
+	indent := strings.Repeat(" ", width)
+	for i := 1; i < len(lines); i++ {
+		if lines[i] != "" {
+			lines[i] = indent + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// indentLines はすべての行を字下げする
+func indentLines(text string, width int) string {
+	return strings.Repeat(" ", width) + indentContinuation(text, width)
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// adfDoc はテスト用にJSON文字列のブロックノードからADFのドキュメントを作成する
+func adfDoc(t *testing.T, content string) *ADFNode {
+	t.Helper()
+	var doc ADFNode
+	if err := json.Unmarshal([]byte(`{"type":"doc","version":1,"content":[`+content+`]}`), &doc); err != nil {
+		t.Fatalf("ADFのパースに失敗しました: %v", err)
+	}
+	return &doc
+}
+
+func TestConvertADFToMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{
+			name:    "段落と書式",
+			content: `{"type":"paragraph","content":[{"type":"text","text":"太字 ","marks":[{"type":"strong"}]},{"type":"text","text":"斜体","marks":[{"type":"em"}]},{"type":"text","text":" "},{"type":"text","text":"取消","marks":[{"type":"strike"}]},{"type":"text","text":" "},{"type":"text","text":"code","marks":[{"type":"code"}]},{"type":"text","text":" "},{"type":"text","text":"下線","marks":[{"type":"underline"}]},{"type":"text","text":"2","marks":[{"type":"subsup","attrs":{"type":"sub"}}]},{"type":"text","text":"3","marks":[{"type":"subsup","attrs":{"type":"sup"}}]}]}`,
+			want:    "**太字** *斜体* ~~取消~~ `code` <u>下線</u><sub>2</sub><sup>3</sup>",
+		},
+		{
+			name:    "リンクと色",
+			content: `{"type":"paragraph","content":[{"type":"text","text":"Jira","marks":[{"type":"link","attrs":{"href":"https://example.com"}},{"type":"strong"}]},{"type":"text","text":"赤","marks":[{"type":"textColor","attrs":{"color":"#ff5630"}}]},{"type":"text","text":"背景","marks":[{"type":"backgroundColor","attrs":{"color":"#fedec8"}}]}]}`,
+			want:    `[**Jira**](https://example.com)<span style="color:#ff5630">赤</span><span style="background-color:#fedec8">背景</span>`,
+		},
+		{
+			name:    "不正な色とURL",
+			content: `{"type":"paragraph","content":[{"type":"text","text":"色","marks":[{"type":"textColor","attrs":{"color":"red\" onmouseover=\"alert(1)"}}]},{"type":"text","text":"背景","marks":[{"type":"backgroundColor","attrs":{"color":"red;background:url(x)"}}]},{"type":"text","text":"リンク","marks":[{"type":"link","attrs":{"href":"javascript:alert(1)"}}]}]},{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"external","url":"javascript:alert(1)","alt":"画像"}}]}`,
+			want:    "色背景リンク\n\n画像",
+		},
+		{
+			name:    "見出しと改行と区切り線",
+			content: `{"type":"heading","attrs":{"level":2},"content":[{"type":"text","text":"概要"}]},{"type":"paragraph","content":[{"type":"text","text":"1行目"},{"type":"hardBreak"},{"type":"text","text":"2行目"}]},{"type":"rule"}`,
+			want:    "## 概要\n\n1行目  \n2行目\n\n---",
+		},
+		{
+			name:    "入れ子のリスト",
+			content: `{"type":"bulletList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"項目1"}]},{"type":"orderedList","attrs":{"order":3},"content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"手順A"}]}]},{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"手順B"}]}]}]}]},{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"項目2"}]}]}]}`,
+			want:    "- 項目1\n  3. 手順A\n  4. 手順B\n- 項目2",
+		},
+		{
+			name:    "タスクリスト",
+			content: `{"type":"taskList","attrs":{"localId":"1"},"content":[{"type":"taskItem","attrs":{"state":"DONE"},"content":[{"type":"text","text":"完了したタスク"}]},{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"未完了のタスク"}]},{"type":"taskList","content":[{"type":"taskItem","attrs":{"state":"TODO"},"content":[{"type":"text","text":"サブタスク"}]}]}]}`,
+			want:    "- [x] 完了したタスク\n- [ ] 未完了のタスク\n  - [ ] サブタスク",
+		},
+		{
+			name:    "決定事項",
+			content: `{"type":"decisionList","content":[{"type":"decisionItem","attrs":{"state":"DECIDED"},"content":[{"type":"text","text":"v2で対応する"}]}]}`,
+			want:    "- 📌 v2で対応する",
+		},
+		{
+			name:    "コードブロック",
+			content: `{"type":"codeBlock","attrs":{"language":"go"},"content":[{"type":"text","text":"func main() {\n}"}]}`,
+			want:    "```go\nfunc main() {\n}\n```",
+		},
+		{
+			name:    "バッククォートを含むコードブロック",
+			content: "{\"type\":\"codeBlock\",\"content\":[{\"type\":\"text\",\"text\":\"```\\n<script>alert(1)</script>\"}]}",
+			want:    "````\n```\n<script>alert(1)</script>\n````",
+		},
+		{
+			name:    "HTMLとMarkdownの記号のエスケープ",
+			content: `{"type":"paragraph","content":[{"type":"text","text":"<img src=x onerror=alert(1)> *x* _y_ [z]"},{"type":"text","text":" <b>","marks":[{"type":"code"}]}]},{"type":"paragraph","content":[{"type":"text","text":"# 見出しではない"}]},{"type":"paragraph","content":[{"type":"text","text":"1. 番号"},{"type":"hardBreak"},{"type":"text","text":"- 箇条書き","marks":[{"type":"strong"}]},{"type":"hardBreak"},{"type":"text","text":"- 箇条書き"}]}`,
+			want:    "&lt;img src=x onerror=alert(1)&gt; \\*x\\* \\_y\\_ \\[z\\] `<b>`\n\n\\# 見出しではない\n\n1\\. 番号  \n**- 箇条書き**  \n\\- 箇条書き",
+		},
+		{
+			name:    "引用",
+			content: `{"type":"blockquote","content":[{"type":"paragraph","content":[{"type":"text","text":"引用1"}]},{"type":"paragraph","content":[{"type":"text","text":"引用2"}]}]}`,
+			want:    "> 引用1\n>\n> 引用2",
+		},
+		{
+			name:    "パネル",
+			content: `{"type":"panel","attrs":{"panelType":"warning"},"content":[{"type":"paragraph","content":[{"type":"text","text":"注意","marks":[{"type":"strong"}]}]}]},{"type":"panel","attrs":{"panelType":"custom","panelColor":"#e3fcef"},"content":[{"type":"paragraph","content":[{"type":"text","text":"成功"}]}]}`,
+			want:    "<div class=\"panel panel-warning\"><div class=\"panel-body\">\n\n**注意**\n\n</div></div>\n\n<div class=\"panel panel-success\"><div class=\"panel-body\">\n\n成功\n\n</div></div>",
+		},
+		{
+			name:    "展開ブロック",
+			content: `{"type":"expand","attrs":{"title":"詳細 <ログ>"},"content":[{"type":"paragraph","content":[{"type":"text","text":"中身"}]}]}`,
+			want:    "<details>\n<summary>詳細 &lt;ログ&gt;</summary>\n\n中身\n\n</details>",
+		},
+		{
+			name:    "ステータス・絵文字・日付・カード",
+			content: `{"type":"paragraph","content":[{"type":"status","attrs":{"text":"IN PROGRESS","color":"blue"}},{"type":"text","text":" "},{"type":"status","attrs":{"text":"不明","color":"pink"}},{"type":"text","text":" "},{"type":"emoji","attrs":{"shortName":":smile:","text":"😄"}},{"type":"emoji","attrs":{"shortName":":custom:"}},{"type":"text","text":" "},{"type":"date","attrs":{"timestamp":"1736899200000"}},{"type":"text","text":" "},{"type":"inlineCard","attrs":{"url":"https://example.atlassian.net/browse/TEST-1"}}]}`,
+			want:    `<span class="status-label status-label-teal">IN PROGRESS</span> <span class="status-label">不明</span> 😄:custom: 2025-01-15 [https://example.atlassian.net/browse/TEST-1](https://example.atlassian.net/browse/TEST-1)`,
+		},
+		{
+			name:    "メンション",
+			content: `{"type":"paragraph","content":[{"type":"mention","attrs":{"id":"user-1","text":"@Old Name"}},{"type":"text","text":" "},{"type":"mention","attrs":{"id":"user-2","text":"@未登録ユーザー"}}]}`,
+			want:    `<span class="mention">@山田 太郎</span> <span class="mention">@未登録ユーザー</span>`,
+		},
+		{
+			name:    "添付ファイルと外部画像",
+			content: `{"type":"mediaSingle","content":[{"type":"media","attrs":{"id":"uuid-1","type":"file","collection":"","alt":"screen shot.png"}}]},{"type":"mediaGroup","content":[{"type":"media","attrs":{"id":"uuid-2","type":"file","alt":"仕様書.pdf"}},{"type":"media","attrs":{"id":"uuid-3","type":"file","alt":"unknown.zip"}}]},{"type":"mediaSingle","content":[{"type":"media","attrs":{"type":"external","url":"https://example.com/a.png","alt":"外部"}}]}`,
+			want:    "![screen shot.png](/attachments/TEST-1_screen%20shot.png)\n\n[仕様書.pdf](/attachments/TEST-1_%E4%BB%95%E6%A7%98%E6%9B%B8.pdf)\n📎 unknown.zip\n\n![外部](https://example.com/a.png)",
+		},
+		{
+			name:    "ファイル名の無い添付ファイル",
+			content: `{"type":"mediaSingle","content":[{"type":"media","attrs":{"id":"uuid-4","type":"file"}}]}`,
+			want:    "📎 添付ファイル",
+		},
+		{
+			name:    "見出し付きテーブル",
+			content: `{"type":"table","content":[{"type":"tableRow","content":[{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"項目"}]}]},{"type":"tableHeader","content":[{"type":"paragraph","content":[{"type":"text","text":"値"}]}]}]},{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"A|B","marks":[{"type":"strong"}]}]}]},{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"1行目"}]},{"type":"paragraph","content":[{"type":"text","text":"2行目"}]}]}]}]}`,
+			want:    "| 項目 | 値 |\n| ------ | ------ |\n| **A\\|B** | 1行目<br>2行目 |",
+		},
+		{
+			name:    "見出しなし・結合セルのテーブル",
+			content: `{"type":"table","content":[{"type":"tableRow","content":[{"type":"tableCell","attrs":{"colspan":2},"content":[{"type":"paragraph","content":[{"type":"text","text":"結合"}]}]}]},{"type":"tableRow","content":[{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"a"}]}]},{"type":"tableCell","content":[{"type":"paragraph","content":[{"type":"text","text":"b"}]}]}]}]}`,
+			want:    "|   |   |\n| ------ | ------ |\n| 結合 |  |\n| a | b |",
+		},
+		{
+			name:    "レイアウトと未知のノード",
+			content: `{"type":"layoutSection","content":[{"type":"layoutColumn","attrs":{"width":50},"content":[{"type":"paragraph","content":[{"type":"text","text":"左"}]}]},{"type":"layoutColumn","attrs":{"width":50},"content":[{"type":"paragraph","content":[{"type":"text","text":"右"}]}]}]},{"type":"extension","attrs":{"extensionKey":"macro"}},{"type":"futureBlock","content":[{"type":"paragraph","content":[{"type":"text","text":"新しいノード"}]}]}`,
+			want:    "左\n\n右\n\n新しいノード",
+		},
+	}
+
+	mw := NewMarkdownWriter(context.Background(), "", "", UserMapping{"user-1": "山田 太郎"}, createTestConfig())
+	attachmentMap := map[string]string{
+		"screen shot.png": "TEST-1_screen shot.png",
+		"仕様書.pdf":         "TEST-1_仕様書.pdf",
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mw.convertADFToMarkdown(adfDoc(t, tt.content), attachmentMap)
+			if got != tt.want {
+				t.Errorf("convertADFToMarkdown()\n実際: %q\n期待: %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseADF(t *testing.T) {
+	docJSON := `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"本文"}]}]}`
+	var docMap map[string]interface{}
+	if err := json.Unmarshal([]byte(docJSON), &docMap); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		value interface{}
+		want  bool
+	}{
+		{"map", docMap, true},
+		{"JSON文字列", docJSON, true},
+		{"json.RawMessage", json.RawMessage(docJSON), true},
+		{"Wiki記法", "h1. 見出し\n*太字*", false},
+		{"doc以外のmap", map[string]interface{}{"type": "paragraph"}, false},
+		{"JSON以外の{で始まる文字列", "{code}x{code} \"doc\"", false},
+		{"nil", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, ok := parseADF(tt.value)
+			if ok != tt.want {
+				t.Fatalf("parseADF() ok = %v, want %v", ok, tt.want)
+			}
+			if ok && adfPlainText(doc) != "本文" {
+				t.Errorf("adfPlainText() = %q", adfPlainText(doc))
+			}
+		})
+	}
+}
+
+// TestGenerateMarkdown_ADF は説明・コメント・カスタムフィールドがADFの場合にADFから変換されることを確認する
+func TestGenerateMarkdown_ADF(t *testing.T) {
+	description := adfDoc(t, `{"type":"panel","attrs":{"panelType":"info"},"content":[{"type":"paragraph","content":[{"type":"text","text":"ADFの説明"}]}]}`)
+	reply := adfDoc(t, `{"type":"paragraph","content":[{"type":"mention","attrs":{"id":"user-1","text":"@山田"}},{"type":"text","text":" 確認しました"}]}`)
+	var textarea map[string]interface{}
+	json.Unmarshal([]byte(`{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"再現手順"}]},{"type":"orderedList","content":[{"type":"listItem","content":[{"type":"paragraph","content":[{"type":"text","text":"開く"}]}]}]}]}`), &textarea)
+
+	issue := &cloud.Issue{
+		Key: "TEST-1",
+		Fields: &cloud.IssueFields{
+			Type:        cloud.IssueType{Name: "バグ"},
+			Status:      &cloud.Status{Name: "未着手"},
+			Summary:     "ADFの課題",
+			Description: "h1. Wiki記法の説明",
+			Created:     cloud.Time(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+			Updated:     cloud.Time(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)),
+			Project:     cloud.Project{Key: "TEST", Name: "テストプロジェクト"},
+			Unknowns:    map[string]interface{}{"customfield_10100": textarea},
+		},
+	}
+	data := &IssueData{
+		Issue: issue,
+		Comments: []IssueComment{
+			{Comment: cloud.Comment{ID: "100", Body: "[~accountid:user-1] 確認しました", Author: &cloud.User{DisplayName: "佐藤"}, Created: "2025-01-15T10:00:00.000+0900"}},
+			{Comment: cloud.Comment{ID: "101", Body: `{"type":"doc","version":1,"content":[{"type":"paragraph","content":[{"type":"text","text":"ADF文字列","marks":[{"type":"strong"}]}]}]}`, Author: &cloud.User{DisplayName: "鈴木"}, Created: "2025-01-16T10:00:00.000+0900"}},
+		},
+		ADF: &IssueADF{
+			Description: description,
+			Comments:    map[string]*ADFNode{"100": reply},
+		},
+	}
+
+	mw := NewMarkdownWriter(context.Background(), "", "", UserMapping{"user-1": "山田 太郎"}, createTestConfig())
+	got, err := mw.generateMarkdown(data, nil, FieldNameCache{"customfield_10100": "再現手順"})
+	if err != nil {
+		t.Fatalf("generateMarkdown() error = %v", err)
+	}
+
+	for _, want := range []string{
+		"## 説明\n\n<div class=\"panel panel-info\"><div class=\"panel-body\">\n\nADFの説明\n\n</div></div>\n\n",
+		"↩️ 佐藤",
+		"<span class=\"mention\">@山田 太郎</span> 確認しました",
+		"**ADF文字列**",
+		"- **再現手順**: 再現手順\n\n  1. 開く\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("出力に %q が含まれていません\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "Wiki記法の説明") {
+		t.Errorf("ADFがある場合にWiki記法の説明が出力されています")
+	}
+}
@@ -0,0 +1,312 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Authenticator はJIRA APIリクエストに認証情報を設定する
+// JIRAClientとDownloaderで共通に使用する
+type Authenticator interface {
+	Authorize(req *http.Request) error
+}
+
+// BasicAuthenticator はメールアドレス（Data Centerではユーザー名）とAPIトークンのBasic認証
+type BasicAuthenticator struct {
+	Email    string
+	APIToken string
+}
+
+// Authorize はBasic認証ヘッダーを設定する
+func (a *BasicAuthenticator) Authorize(req *http.Request) error {
+	req.SetBasicAuth(a.Email, a.APIToken)
+	return nil
+}
+
+// BearerAuthenticator はトークンのBearer認証（Data Centerのパーソナルアクセストークン等）
+type BearerAuthenticator struct {
+	Token string
+}
+
+// Authorize はBearer認証ヘッダーを設定する
+func (a *BearerAuthenticator) Authorize(req *http.Request) error {
+	req.Header.Set("Authorization", "Bearer "+a.Token)
+	return nil
+}
+
+// NewAuthenticator は設定（jira.auth）に応じたAuthenticatorを作成する
+// httpClientはOAuth 2.0のトークン取得・更新に使用する
+// jira.authが未設定の場合はCloudではBasic認証、Data CenterではBearer認証とする
+func NewAuthenticator(config *JIRAConfig, httpClient *http.Client) Authenticator {
+	switch {
+	case config.Auth == "oauth2":
+		return NewOAuth2Authenticator(config.OAuth2, httpClient)
+	case config.Auth == "bearer", config.Auth == "" && config.IsDataCenter():
+		return &BearerAuthenticator{Token: config.APIToken}
+	default:
+		return &BasicAuthenticator{Email: config.Email, APIToken: config.APIToken}
+	}
+}
+
+// AuthTransport はリクエストごとにAuthenticatorで認証情報を設定するhttp.RoundTripper
+type AuthTransport struct {
+	Auth      Authenticator
+	Transport http.RoundTripper // nilの場合はhttp.DefaultTransport
+}
+
+// RoundTrip は認証情報を設定したリクエストのコピーを送信する
+func (t *AuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req2 := req.Clone(req.Context())
+	if err := t.Auth.Authorize(req2); err != nil {
+		return nil, fmt.Errorf("認証情報の設定に失敗しました: %w", err)
+	}
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return transport.RoundTrip(req2)
+}
+
+// OAuth2Token はOAuth 2.0のトークン（トークンキャッシュの保存形式）
+type OAuth2Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// oauth2TokenResponse はトークンエンドポイントのレスポンス
+type oauth2TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+	Error        string `json:"error"`
+	ErrorDesc    string `json:"error_description"`
+}
+
+// oauth2ExpiryDelta はアクセストークンの有効期限前に更新を行う余裕時間
+const oauth2ExpiryDelta = time.Minute
+
+// OAuth2Authenticator はOAuth 2.0（3LO、認可コードフロー）のアクセストークンによるBearer認証
+//
+// トークンはtoken_cacheのファイルに保存し、有効期限が近づいたらリフレッシュトークンで更新する。
+// Atlassianはリフレッシュトークンをローテーションするため、更新のたびにキャッシュを書き換える。
+// 複数のワーカーから並行に呼び出されても更新は1回だけ行う。
+type OAuth2Authenticator struct {
+	config     OAuth2Config
+	httpClient *http.Client
+
+	mu    sync.Mutex
+	token *OAuth2Token
+	now   func() time.Time // テスト用に差し替え可能な現在時刻
+}
+
+// NewOAuth2Authenticator は新しいOAuth2Authenticatorを作成する
+func NewOAuth2Authenticator(config OAuth2Config, httpClient *http.Client) *OAuth2Authenticator {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &OAuth2Authenticator{
+		config:     config,
+		httpClient: httpClient,
+		now:        time.Now,
+	}
+}
+
+// Authorize は有効なアクセストークンでBearer認証ヘッダーを設定する
+func (a *OAuth2Authenticator) Authorize(req *http.Request) error {
+	token, err := a.validToken()
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return nil
+}
+
+// validToken は有効なアクセストークンを返す（必要に応じてキャッシュの読み込みと更新を行う）
+func (a *OAuth2Authenticator) validToken() (*OAuth2Token, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.token == nil {
+		token, err := a.loadToken()
+		if err != nil {
+			return nil, err
+		}
+		a.token = token
+	}
+
+	if a.now().Add(oauth2ExpiryDelta).Before(a.token.Expiry) {
+		return a.token, nil
+	}
+
+	// 有効期限切れ（または間近）のためリフレッシュトークンで更新する
+	if a.token.RefreshToken == "" {
+		return nil, fmt.Errorf("アクセストークンの有効期限が切れています。リフレッシュトークンがないため `migJira auth` で再認可してください")
+	}
+	slog.Debug("OAuth 2.0 アクセストークンを更新", "expiry", a.token.Expiry)
+	token, err := a.requestToken(map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": a.token.RefreshToken,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("アクセストークンの更新に失敗しました: %w", err)
+	}
+	// リフレッシュトークンが返らない場合は従来のものを使い続ける
+	if token.RefreshToken == "" {
+		token.RefreshToken = a.token.RefreshToken
+	}
+	a.token = token
+	if err := a.saveToken(token); err != nil {
+		return nil, err
+	}
+	return a.token, nil
+}
+
+// AuthCodeURL はユーザーがブラウザで開く認可URLを返す
+func (a *OAuth2Authenticator) AuthCodeURL(state string) string {
+	params := url.Values{}
+	params.Set("audience", "api.atlassian.com")
+	params.Set("client_id", a.config.ClientID)
+	params.Set("scope", strings.Join(a.config.Scopes, " "))
+	params.Set("redirect_uri", a.config.RedirectURL)
+	params.Set("state", state)
+	params.Set("response_type", "code")
+	params.Set("prompt", "consent")
+	return a.config.AuthURL + "?" + params.Encode()
+}
+
+// newOAuth2State は認可リクエストのstateパラメータ（推測できない乱数）を生成する
+func newOAuth2State() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("stateの生成に失敗しました: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// parseOAuth2Redirect はリダイレクト先URLから認可コードを取り出す
+// stateが認可リクエストのstateと一致しない場合（別の認可リクエストの応答、CSRF）はエラーを返す
+func parseOAuth2Redirect(redirectURL, state string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(redirectURL))
+	if err != nil {
+		return "", fmt.Errorf("リダイレクト先URLの解析に失敗しました: %w", err)
+	}
+	query := u.Query()
+	if errCode := query.Get("error"); errCode != "" {
+		return "", fmt.Errorf("認可が拒否されました: %s %s", errCode, query.Get("error_description"))
+	}
+	if query.Get("state") != state {
+		return "", fmt.Errorf("リダイレクト先URLのstateが認可リクエストと一致しません")
+	}
+	code := query.Get("code")
+	if code == "" {
+		return "", fmt.Errorf("リダイレクト先URLに認可コード（code）が含まれていません")
+	}
+	return code, nil
+}
+
+// Exchange は認可コードをトークンに交換し、トークンキャッシュに保存する
+func (a *OAuth2Authenticator) Exchange(code string) error {
+	token, err := a.requestToken(map[string]string{
+		"grant_type":   "authorization_code",
+		"code":         code,
+		"redirect_uri": a.config.RedirectURL,
+	})
+	if err != nil {
+		return fmt.Errorf("認可コードの交換に失敗しました: %w", err)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.token = token
+	return a.saveToken(token)
+}
+
+// requestToken はトークンエンドポイントにリクエストを送信する（クライアント認証情報を含む）
+func (a *OAuth2Authenticator) requestToken(params map[string]string) (*OAuth2Token, error) {
+	params["client_id"] = a.config.ClientID
+	params["client_secret"] = a.config.ClientSecret
+	body, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("リクエストの作成に失敗しました: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, a.config.TokenURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの実行に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("レスポンスボディの読み取りに失敗しました: %w", err)
+	}
+
+	var tokenResp oauth2TokenResponse
+	if err := json.Unmarshal(respBody, &tokenResp); err != nil {
+		return nil, fmt.Errorf("ステータスコード: %d, レスポンス: %s", resp.StatusCode, string(respBody))
+	}
+	if resp.StatusCode != http.StatusOK || tokenResp.AccessToken == "" {
+		return nil, fmt.Errorf("ステータスコード: %d, エラー: %s %s", resp.StatusCode, tokenResp.Error, tokenResp.ErrorDesc)
+	}
+
+	return &OAuth2Token{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		TokenType:    tokenResp.TokenType,
+		Expiry:       a.now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// loadToken はトークンキャッシュからトークンを読み込む
+func (a *OAuth2Authenticator) loadToken() (*OAuth2Token, error) {
+	data, err := os.ReadFile(a.config.TokenCache)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("OAuth 2.0のトークンがありません。`migJira auth` で認可してください（トークンキャッシュ: %s）", a.config.TokenCache)
+		}
+		return nil, fmt.Errorf("トークンキャッシュの読み込みに失敗しました: %w", err)
+	}
+
+	var token OAuth2Token
+	if err := json.Unmarshal(data, &token); err != nil {
+		return nil, fmt.Errorf("トークンキャッシュのパースに失敗しました: %w", err)
+	}
+	return &token, nil
+}
+
+// saveToken はトークンをトークンキャッシュに保存する（所有者のみ読み書き可能）
+func (a *OAuth2Authenticator) saveToken(token *OAuth2Token) error {
+	if err := os.MkdirAll(filepath.Dir(a.config.TokenCache), 0700); err != nil {
+		return fmt.Errorf("トークンキャッシュのディレクトリ作成に失敗しました: %w", err)
+	}
+	data, err := json.MarshalIndent(token, "", "  ")
+	if err != nil {
+		return fmt.Errorf("トークンのJSON変換に失敗しました: %w", err)
+	}
+	if err := os.WriteFile(a.config.TokenCache, data, 0600); err != nil {
+		return fmt.Errorf("トークンキャッシュの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
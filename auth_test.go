@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeAuthServer はテスト用のOAuth 2.0認可サーバー
+// 発行するたびにアクセストークン・リフレッシュトークンをローテーションする
+type fakeAuthServer struct {
+	server   *httptest.Server
+	requests atomic.Int32
+	mu       sync.Mutex
+	refresh  string // 現在有効なリフレッシュトークン
+}
+
+func newFakeAuthServer(t *testing.T) *fakeAuthServer {
+	t.Helper()
+	fs := &fakeAuthServer{}
+	fs.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if params["client_id"] != "client-id" || params["client_secret"] != "client-secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			fmt.Fprint(w, `{"error":"access_denied","error_description":"Unauthorized"}`)
+			return
+		}
+
+		fs.mu.Lock()
+		defer fs.mu.Unlock()
+		switch params["grant_type"] {
+		case "authorization_code":
+			if params["code"] != "auth-code" {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"error":"invalid_grant"}`)
+				return
+			}
+		case "refresh_token":
+			if params["refresh_token"] != fs.refresh {
+				w.WriteHeader(http.StatusForbidden)
+				fmt.Fprint(w, `{"error":"invalid_grant","error_description":"Unknown or invalid refresh token."}`)
+				return
+			}
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		n := fs.requests.Add(1)
+		fs.refresh = fmt.Sprintf("refresh-%d", n)
+		fmt.Fprintf(w, `{"access_token":"access-%d","refresh_token":"%s","token_type":"Bearer","expires_in":3600}`, n, fs.refresh)
+	}))
+	t.Cleanup(fs.server.Close)
+	return fs
+}
+
+func newTestOAuth2Authenticator(fs *fakeAuthServer, tokenCache string) *OAuth2Authenticator {
+	return NewOAuth2Authenticator(OAuth2Config{
+		ClientID:     "client-id",
+		ClientSecret: "client-secret",
+		RedirectURL:  "http://localhost:8080/callback",
+		Scopes:       []string{"read:jira-work", "offline_access"},
+		AuthURL:      "https://auth.example.com/authorize",
+		TokenURL:     fs.server.URL,
+		TokenCache:   tokenCache,
+	}, fs.server.Client())
+}
+
+// TestOAuth2Authenticator はOAuth 2.0の認可コード交換とトークン更新のテスト
+func TestOAuth2Authenticator(t *testing.T) {
+	fs := newFakeAuthServer(t)
+	tokenCache := filepath.Join(t.TempDir(), "auth", "token.json")
+	auth := newTestOAuth2Authenticator(fs, tokenCache)
+
+	// 認可URL
+	authURL := auth.AuthCodeURL("state-1")
+	for _, want := range []string{"audience=api.atlassian.com", "client_id=client-id", "state=state-1", "scope=read%3Ajira-work+offline_access"} {
+		if !strings.Contains(authURL, want) {
+			t.Errorf("AuthCodeURL() に %q が含まれていません: %s", want, authURL)
+		}
+	}
+
+	// キャッシュがない状態では認可を求めるエラー
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+	if err := auth.Authorize(req); err == nil || !strings.Contains(err.Error(), "migJira auth") {
+		t.Fatalf("トークン未取得時のエラーが期待と異なります: %v", err)
+	}
+
+	// 認可コードの交換
+	if err := auth.Exchange("auth-code"); err != nil {
+		t.Fatalf("Exchange() error = %v", err)
+	}
+	info, err := os.Stat(tokenCache)
+	if err != nil {
+		t.Fatalf("トークンキャッシュが作成されていません: %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("トークンキャッシュのパーミッション = %v, want 0600", info.Mode().Perm())
+	}
+
+	if err := auth.Authorize(req); err != nil {
+		t.Fatalf("Authorize() error = %v", err)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer access-1" {
+		t.Errorf("Authorization = %q, want %q", got, "Bearer access-1")
+	}
+
+	// 有効期限切れ: 別のインスタンスでキャッシュを読み込み、リフレッシュトークンで更新する
+	auth2 := newTestOAuth2Authenticator(fs, tokenCache)
+	auth2.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	// 並行に呼び出されても更新は1回だけ
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r, _ := http.NewRequest(http.MethodGet, "https://example.com", nil)
+			if err := auth2.Authorize(r); err != nil {
+				t.Errorf("Authorize() error = %v", err)
+				return
+			}
+			if got := r.Header.Get("Authorization"); got != "Bearer access-2" {
+				t.Errorf("Authorization = %q, want %q", got, "Bearer access-2")
+			}
+		}()
+	}
+	wg.Wait()
+	if got := fs.requests.Load(); got != 2 {
+		t.Errorf("トークンエンドポイントへのリクエスト数 = %d, want 2", got)
+	}
+
+	// ローテーションされたリフレッシュトークンがキャッシュに保存されている
+	data, err := os.ReadFile(tokenCache)
+	if err != nil {
+		t.Fatalf("トークンキャッシュの読み込みに失敗しました: %v", err)
+	}
+	var cached OAuth2Token
+	if err := json.Unmarshal(data, &cached); err != nil {
+		t.Fatalf("トークンキャッシュのパースに失敗しました: %v", err)
+	}
+	if cached.AccessToken != "access-2" || cached.RefreshToken != "refresh-2" {
+		t.Errorf("キャッシュされたトークン = %+v", cached)
+	}
+
+	// 古いリフレッシュトークンは無効になっている
+	auth.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if err := auth.Authorize(req); err == nil || !strings.Contains(err.Error(), "invalid_grant") {
+		t.Errorf("古いリフレッシュトークンでのエラーが期待と異なります: %v", err)
+	}
+}
+
+// TestAuthTransport はAuthTransportと各認証方式のテスト
+func TestAuthTransport(t *testing.T) {
+	tests := []struct {
+		name   string
+		auth   Authenticator
+		expect func(r *http.Request) bool
+	}{
+		{
+			name: "Basic認証",
+			auth: &BasicAuthenticator{Email: "test@example.com", APIToken: "token"},
+			expect: func(r *http.Request) bool {
+				user, pass, ok := r.BasicAuth()
+				return ok && user == "test@example.com" && pass == "token"
+			},
+		},
+		{
+			name: "Bearer認証",
+			auth: &BearerAuthenticator{Token: "scoped-token"},
+			expect: func(r *http.Request) bool {
+				return r.Header.Get("Authorization") == "Bearer scoped-token"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if !tt.expect(r) {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: &AuthTransport{Auth: tt.auth, Transport: server.Client().Transport}}
+			req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+			resp, err := client.Do(req)
+			if err != nil {
+				t.Fatalf("リクエストに失敗しました: %v", err)
+			}
+			resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("StatusCode = %d, want 200", resp.StatusCode)
+			}
+			// 元のリクエストは変更しない
+			if req.Header.Get("Authorization") != "" {
+				t.Errorf("元のリクエストに認証ヘッダーが設定されています")
+			}
+		})
+	}
+}
+
+// TestParseOAuth2Redirect はリダイレクト先URLからの認可コードの取り出しとstateの確認をテストする
+func TestParseOAuth2Redirect(t *testing.T) {
+	state, err := newOAuth2State()
+	if err != nil {
+		t.Fatalf("newOAuth2State() error = %v", err)
+	}
+	if other, _ := newOAuth2State(); other == state || len(state) < 40 {
+		t.Errorf("stateが推測できない乱数ではありません: %q, %q", state, other)
+	}
+
+	tests := []struct {
+		name        string
+		redirectURL string
+		want        string
+		errContains string
+	}{
+		{"正常系", "http://localhost:8080/callback?code=abc123&state=" + state + "\n", "abc123", ""},
+		{"stateが異なる", "http://localhost:8080/callback?code=abc123&state=other", "", "stateが認可リクエストと一致しません"},
+		{"stateなし", "http://localhost:8080/callback?code=abc123", "", "stateが認可リクエストと一致しません"},
+		{"codeなし", "http://localhost:8080/callback?state=" + state, "", "認可コード（code）が含まれていません"},
+		{"認可の拒否", "http://localhost:8080/callback?error=access_denied&state=" + state, "", "access_denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseOAuth2Redirect(tt.redirectURL, state)
+			if tt.errContains != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("parseOAuth2Redirect() error = %v, %q を含むエラーを期待", err, tt.errContains)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("parseOAuth2Redirect() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Checkpoint は出力が完了した課題キーを記録するチェックポイントファイル
+//
+// ファイルはJSON Lines形式で、1行目に検索条件、2行目以降に完了した課題キーを1件ずつ追記する。
+// 中断された場合でも完了した課題までは記録が残るため、`--resume` で続きから再開できる。
+// 追記の途中で強制終了した場合の不完全な行は読み込み時に無視する。
+type Checkpoint struct {
+	path  string
+	query string
+
+	mu        sync.Mutex
+	file      *os.File
+	completed map[string]bool
+}
+
+// checkpointHeader はチェックポイントファイルの1行目（検索条件）
+type checkpointHeader struct {
+	Query     string `json:"query"`
+	StartedAt string `json:"started_at"`
+}
+
+// checkpointEntry はチェックポイントファイルの2行目以降（完了した課題）
+type checkpointEntry struct {
+	Key         string `json:"key"`
+	CompletedAt string `json:"completed_at"`
+}
+
+// OpenCheckpoint はチェックポイントファイルを開く
+// resumeがtrueの場合は既存のファイルから完了済みの課題キーを読み込んで追記する
+// （検索条件が異なる場合はエラー）。falseの場合は新しく作成し直す
+func OpenCheckpoint(path string, query string, resume bool) (*Checkpoint, error) {
+	cp := &Checkpoint{
+		path:      path,
+		query:     query,
+		completed: make(map[string]bool),
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("チェックポイントのディレクトリ作成に失敗しました: %w", err)
+	}
+
+	if resume {
+		found, err := cp.load()
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+			if err != nil {
+				return nil, fmt.Errorf("チェックポイントファイルを開けませんでした: %w", err)
+			}
+			cp.file = file
+			if err := cp.terminatePartialLine(); err != nil {
+				file.Close()
+				return nil, err
+			}
+			return cp, nil
+		}
+		fmt.Printf("チェックポイントがないため最初から処理します: %s\n", path)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("チェックポイントファイルの作成に失敗しました: %w", err)
+	}
+	cp.file = file
+	if err := cp.writeLine(checkpointHeader{Query: query, StartedAt: time.Now().Format(time.RFC3339)}); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return cp, nil
+}
+
+// load は既存のチェックポイントファイルから完了済みの課題キーを読み込む
+// ファイルが存在しない場合はfalseを返す
+func (cp *Checkpoint) load() (bool, error) {
+	file, err := os.Open(cp.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("チェックポイントファイルの読み込みに失敗しました: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	if !scanner.Scan() {
+		return false, nil
+	}
+	var header checkpointHeader
+	if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+		return false, fmt.Errorf("チェックポイントファイルの形式が不正です: %s", cp.path)
+	}
+	if header.Query != cp.query {
+		return false, fmt.Errorf("チェックポイントの検索条件が異なるため再開できません（チェックポイント: %q、今回: %q）。--resume を付けずに実行してください", header.Query, cp.query)
+	}
+
+	for scanner.Scan() {
+		var entry checkpointEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil || entry.Key == "" {
+			continue // 強制終了で途中まで書かれた行は無視する
+		}
+		cp.completed[entry.Key] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return false, fmt.Errorf("チェックポイントファイルの読み込みに失敗しました: %w", err)
+	}
+	return true, nil
+}
+
+// terminatePartialLine はファイルが改行で終わっていない場合に改行を追記する
+// 強制終了で途中まで書かれた行に続けて追記すると、次の完了した課題の行まで読めなくなるため
+func (cp *Checkpoint) terminatePartialLine() error {
+	file, err := os.Open(cp.path)
+	if err != nil {
+		return fmt.Errorf("チェックポイントファイルの読み込みに失敗しました: %w", err)
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("チェックポイントファイルの読み込みに失敗しました: %w", err)
+	}
+	if info.Size() == 0 {
+		return nil
+	}
+	last := make([]byte, 1)
+	if _, err := file.ReadAt(last, info.Size()-1); err != nil {
+		return fmt.Errorf("チェックポイントファイルの読み込みに失敗しました: %w", err)
+	}
+	if last[0] == '\n' {
+		return nil
+	}
+	if _, err := cp.file.Write([]byte{'\n'}); err != nil {
+		return fmt.Errorf("チェックポイントの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Completed は課題の出力が完了済みかどうかを返す（nilの場合は常にfalse）
+func (cp *Checkpoint) Completed(key string) bool {
+	if cp == nil {
+		return false
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return cp.completed[key]
+}
+
+// CompletedCount は完了済みの課題数を返す
+func (cp *Checkpoint) CompletedCount() int {
+	if cp == nil {
+		return 0
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	return len(cp.completed)
+}
+
+// Record は課題の出力完了を記録する（nilの場合は何もしない）
+func (cp *Checkpoint) Record(key string) error {
+	if cp == nil {
+		return nil
+	}
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	if cp.completed[key] {
+		return nil
+	}
+	cp.completed[key] = true
+	return cp.writeLine(checkpointEntry{Key: key, CompletedAt: time.Now().Format(time.RFC3339)})
+}
+
+// writeLine は1行分のJSONを追記する
+func (cp *Checkpoint) writeLine(v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("チェックポイントのJSON変換に失敗しました: %w", err)
+	}
+	if _, err := cp.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("チェックポイントの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// Close はチェックポイントファイルを閉じる
+func (cp *Checkpoint) Close() error {
+	if cp == nil || cp.file == nil {
+		return nil
+	}
+	return cp.file.Close()
+}
+
+// Remove はチェックポイントファイルを閉じて削除する（すべての課題の出力が完了した場合に使用）
+func (cp *Checkpoint) Remove() error {
+	if cp == nil {
+		return nil
+	}
+	cp.Close()
+	if err := os.Remove(cp.path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("チェックポイントファイルの削除に失敗しました: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCheckpoint はチェックポイントの記録・再開・検索条件の照合のテスト
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "checkpoint.jsonl")
+	const query = "project = TEST"
+
+	// 新規作成して記録
+	cp, err := OpenCheckpoint(path, query, false)
+	if err != nil {
+		t.Fatalf("OpenCheckpoint() error = %v", err)
+	}
+	for _, key := range []string{"TEST-1", "TEST-2", "TEST-1"} {
+		if err := cp.Record(key); err != nil {
+			t.Fatalf("Record(%s) error = %v", key, err)
+		}
+	}
+	cp.Close()
+
+	// 強制終了で途中まで書かれた行を追加
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"key":"TEST-3","compl`)
+	f.Close()
+
+	// 再開: 記録済みの課題を読み込む（不完全な行は無視）
+	cp, err = OpenCheckpoint(path, query, true)
+	if err != nil {
+		t.Fatalf("OpenCheckpoint(resume) error = %v", err)
+	}
+	if got := cp.CompletedCount(); got != 2 {
+		t.Errorf("CompletedCount() = %d, want 2", got)
+	}
+	if !cp.Completed("TEST-2") || cp.Completed("TEST-3") {
+		t.Errorf("Completed() の結果が期待と異なります")
+	}
+	cp.Close()
+
+	// 検索条件が異なる場合は再開できない
+	if _, err := OpenCheckpoint(path, "project = OTHER", true); err == nil || !strings.Contains(err.Error(), "検索条件が異なる") {
+		t.Errorf("検索条件が異なる場合のエラーが期待と異なります: %v", err)
+	}
+
+	// --resumeなしの場合は作り直す
+	cp, err = OpenCheckpoint(path, "project = OTHER", false)
+	if err != nil {
+		t.Fatalf("OpenCheckpoint() error = %v", err)
+	}
+	if got := cp.CompletedCount(); got != 0 {
+		t.Errorf("CompletedCount() = %d, want 0", got)
+	}
+
+	// 完了後は削除
+	if err := cp.Remove(); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("チェックポイントファイルが削除されていません")
+	}
+
+	// チェックポイントがない状態での再開は最初から
+	cp, err = OpenCheckpoint(path, query, true)
+	if err != nil {
+		t.Fatalf("OpenCheckpoint(resume) error = %v", err)
+	}
+	defer cp.Close()
+	if got := cp.CompletedCount(); got != 0 {
+		t.Errorf("CompletedCount() = %d, want 0", got)
+	}
+
+	// nilのチェックポイントは何もしない
+	var nilCP *Checkpoint
+	if nilCP.Completed("TEST-1") || nilCP.Record("TEST-1") != nil {
+		t.Errorf("nilのチェックポイントの動作が期待と異なります")
+	}
+}
+
+// TestCheckpoint_ResumeAfterPartialLine は途中まで書かれた行があるファイルから再開した後の記録が失われないことを確認する
+func TestCheckpoint_ResumeAfterPartialLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.jsonl")
+	const query = "project = TEST"
+
+	cp, err := OpenCheckpoint(path, query, false)
+	if err != nil {
+		t.Fatalf("OpenCheckpoint() error = %v", err)
+	}
+	cp.Record("TEST-1")
+	cp.Close()
+
+	// 強制終了で途中まで書かれた行を追加
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString(`{"key":"TEST-2","compl`)
+	f.Close()
+
+	// 再開して記録した課題は次の再開でも完了済みとして読み込む
+	cp, err = OpenCheckpoint(path, query, true)
+	if err != nil {
+		t.Fatalf("OpenCheckpoint(resume) error = %v", err)
+	}
+	if err := cp.Record("TEST-3"); err != nil {
+		t.Fatalf("Record(TEST-3) error = %v", err)
+	}
+	cp.Close()
+
+	cp, err = OpenCheckpoint(path, query, true)
+	if err != nil {
+		t.Fatalf("OpenCheckpoint(resume) error = %v", err)
+	}
+	defer cp.Close()
+	if !cp.Completed("TEST-1") || cp.Completed("TEST-2") || !cp.Completed("TEST-3") {
+		t.Errorf("Completed() の結果が期待と異なります（TEST-1: %v, TEST-2: %v, TEST-3: %v）",
+			cp.Completed("TEST-1"), cp.Completed("TEST-2"), cp.Completed("TEST-3"))
+	}
+}
@@ -3,18 +3,27 @@ package main
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/BurntSushi/toml"
 )
 
 // Config はアプリケーション設定を表す構造体
 type Config struct {
-	JIRA         JIRAConfig        `toml:"jira"`
-	Output       OutputConfig      `toml:"output"`
-	Search       SearchConfig      `toml:"search"`
-	Development  DevelopmentConfig `toml:"development"`
-	Display      DisplayConfig     `toml:"display"`
-	DeletedUsers map[string]string `toml:"deletedUsers"` // 削除済みユーザーのマッピング（accountId -> displayName）
+	JIRA         JIRAConfig         `toml:"jira"`
+	Output       OutputConfig       `toml:"output"`
+	Search       SearchConfig       `toml:"search"`
+	Development  DevelopmentConfig  `toml:"development"`
+	Display      DisplayConfig      `toml:"display"`
+	Performance  PerformanceConfig  `toml:"performance"`
+	Agile        AgileConfig        `toml:"agile"`
+	ReleaseNotes ReleaseNotesConfig `toml:"release_notes"`
+	Prune        PruneConfig        `toml:"prune"`
+	FrontMatter  FrontMatterConfig  `toml:"front_matter"`
+	DeletedUsers map[string]string  `toml:"deletedUsers"` // 削除済みユーザーのマッピング（accountId -> displayName）
 }
 
 // SearchConfig は検索設定を表す構造体
@@ -24,9 +33,51 @@ type SearchConfig struct {
 
 // JIRAConfig はJIRA接続情報を表す構造体
 type JIRAConfig struct {
-	URL      string `toml:"url"`       // JIRA Cloud URL (例: https://your-domain.atlassian.net)
-	Email    string `toml:"email"`     // JIRAユーザーのメールアドレス
-	APIToken string `toml:"api_token"` // JIRA API Token
+	URL             string       `toml:"url"`               // JIRA Cloud URL (例: https://your-domain.atlassian.net)
+	Email           string       `toml:"email"`             // JIRAユーザーのメールアドレス（Basic認証のみ）
+	APIToken        string       `toml:"api_token"`         // JIRA API Token（Data Centerの場合はパーソナルアクセストークン）
+	APITokenFile    string       `toml:"api_token_file"`    // API Tokenを記載したファイルのパス（api_tokenが未設定の場合に使用）
+	APITokenCommand string       `toml:"api_token_command"` // 標準出力にAPI Tokenを出力するコマンド（api_token・api_token_fileが未設定の場合に使用）
+	Deployment      string       `toml:"deployment"`        // "cloud" または "datacenter"（デフォルト: "cloud"）
+	Auth            string       `toml:"auth"`              // 認証方式: "basic", "bearer", "oauth2"（デフォルト: Cloudは"basic"、Data Centerは"bearer"）
+	CloudID         string       `toml:"cloud_id"`          // api.atlassian.comのゲートウェイ経由で接続する場合のクラウドID（OAuth 2.0・スコープ付きAPIトークン）
+	OAuth2          OAuth2Config `toml:"oauth2"`            // OAuth 2.0（3LO）の設定
+	Retry           RetryConfig  `toml:"retry"`             // レート制限・一時エラー時のリトライ設定
+}
+
+// OAuth2Config はOAuth 2.0（3LO、認可コードフロー）の設定を表す構造体
+type OAuth2Config struct {
+	ClientID     string   `toml:"client_id"`     // OAuth 2.0アプリのクライアントID
+	ClientSecret string   `toml:"client_secret"` // OAuth 2.0アプリのシークレット
+	RedirectURL  string   `toml:"redirect_url"`  // コールバックURL（デフォルト: http://localhost:8080/callback）
+	Scopes       []string `toml:"scopes"`        // スコープ（デフォルト: read:jira-work read:jira-user offline_access）
+	AuthURL      string   `toml:"auth_url"`      // 認可エンドポイント（デフォルト: https://auth.atlassian.com/authorize）
+	TokenURL     string   `toml:"token_url"`     // トークンエンドポイント（デフォルト: https://auth.atlassian.com/oauth/token）
+	TokenCache   string   `toml:"token_cache"`   // トークンキャッシュのファイルパス（デフォルト: .migjira/oauth2_token.json）
+}
+
+// atlassianGatewayURL はOAuth 2.0・スコープ付きAPIトークンで使用するAPIゲートウェイのURL
+const atlassianGatewayURL = "https://api.atlassian.com/ex/jira/"
+
+// IsDataCenter はJira Data Center / Serverに接続する設定かどうかを返す
+func (c *JIRAConfig) IsDataCenter() bool {
+	return c.Deployment == "datacenter"
+}
+
+// APIBaseURL はREST APIのベースURLを返す
+// cloud_idが設定されている場合はapi.atlassian.comのゲートウェイ、それ以外はjira.url
+func (c *JIRAConfig) APIBaseURL() string {
+	if c.CloudID != "" && !c.IsDataCenter() {
+		return atlassianGatewayURL + c.CloudID
+	}
+	return c.URL
+}
+
+// RetryConfig はAPIリクエストのリトライ設定を表す構造体
+type RetryConfig struct {
+	MaxRetries       int `toml:"max_retries"`        // 最大リトライ回数（デフォルト: 5、-1でリトライ無効）
+	InitialBackoffMs int `toml:"initial_backoff_ms"` // 初回のバックオフ時間（ミリ秒、デフォルト: 1000）
+	MaxBackoffMs     int `toml:"max_backoff_ms"`     // バックオフ時間の上限（ミリ秒、デフォルト: 60000）
 }
 
 // OutputConfig は出力設定を表す構造体
@@ -34,6 +85,10 @@ type OutputConfig struct {
 	MarkdownDir    string `toml:"markdown_dir"`    // Markdown出力ディレクトリ
 	AttachmentsDir string `toml:"attachments_dir"` // 添付ファイル保存ディレクトリ
 	JSONDir        string `toml:"json_dir"`        // JSON出力ディレクトリ（空の場合はJSON保存しない）
+	CheckpointFile string `toml:"checkpoint_file"` // search/projectコマンドのチェックポイントファイル（デフォルト: .migjira/checkpoint.jsonl）
+	SyncStateFile  string `toml:"sync_state_file"` // syncコマンドの状態ファイル（デフォルト: json_dirと同じ階層の sync_state.json）
+	Template       string `toml:"template"`        // 課題ページのテンプレートファイル（text/template、未設定の場合は組み込みのデフォルトテンプレート）
+	FrontMatter    string `toml:"front_matter"`    // フロントマターの形式: "toml"（+++）、"yaml"（---）、"json"（デフォルト: "toml"）
 }
 
 // DevelopmentConfig は開発情報取得の設定を表す構造体
@@ -47,10 +102,76 @@ type DevelopmentConfig struct {
 type DisplayConfig struct {
 	HiddenCustomFields []string `toml:"hidden_custom_fields"` // 基本情報セクションで非表示にするカスタムフィールドIDのリスト
 	RankFieldId        string   `toml:"rank_field_id"`        // RankフィールドのカスタムフィールドID（デフォルト: customfield_10019）
+	RestrictedComments string   `toml:"restricted_comments"`  // 公開範囲が制限されたコメントの扱い: "show", "mark", "omit"（デフォルト: "mark"）
+	WorklogFrontMatter bool     `toml:"worklog_front_matter"` // 作業ログの合計時間をフロントマター（worklog_total）に出力する（デフォルト: false）
+	SprintFieldId      string   `toml:"sprint_field_id"`      // SprintフィールドのカスタムフィールドID（デフォルト: customfield_10020）
+	ADF                bool     `toml:"adf"`                  // 説明・コメント・複数行テキストのカスタムフィールドをADF（/rest/api/3）で取得して変換する（Cloudのみ、オプトインでデフォルト: false）
+	Source             string   `toml:"source"`               // 説明・コメントの変換元: "wiki"（Wiki記法）、"rendered"（renderedFieldsのHTML）、"auto"（Wiki記法で変換できない課題のみHTML）（デフォルト: "wiki"）
+	Language           string   `toml:"language"`             // 見出し・ラベルの言語: "ja", "en"（デフォルト: "ja"、messagesを指定した場合は任意の言語）
+	Messages           string   `toml:"messages"`             // 見出し・ラベルの文言を置き換える文言ファイル（TOML）のパス
+
+	FieldFormats map[string]FieldFormatConfig `toml:"field_formats"` // フィールドIDまたはフィールド名 → 数値・日付の形式
+	Icons        IconsConfig                  `toml:"icons"`         // 課題タイプ・優先度・ステータス・プロジェクトのアイコン
+}
+
+// IconsConfig はページに出力するアイコンの設定を表す構造体
+// アイコンは絵文字等の文字列、画像のパス・URL、またはJiraのアイコン（"jira"）で指定する
+// 名前が "*" の項目は、設定に無い名前のアイコンになる
+type IconsConfig struct {
+	Project    string            `toml:"project"`     // プロジェクトのアイコン（デフォルト: 📦）
+	IssueTypes map[string]string `toml:"issue_types"` // 課題タイプ名 → アイコン（設定に無い課題タイプは組み込みのアイコン）
+	Priorities map[string]string `toml:"priorities"`  // 優先度名 → アイコン（設定に無い優先度はアイコンなし）
+	Statuses   map[string]string `toml:"statuses"`    // ステータス名 → アイコン（設定に無いステータスはアイコンなし）
+}
+
+// FieldFormatConfig はフィールドの値の形式の設定を表す構造体
+type FieldFormatConfig struct {
+	Number string `toml:"number"` // 数値の形式（fmtの書式、例: "%.1f"）
+	Date   string `toml:"date"`   // 日付・日時の形式（Goのレイアウト、例: "2006/01/02"）
+}
+
+// PerformanceConfig は並行処理の設定を表す構造体
+type PerformanceConfig struct {
+	Workers int `toml:"workers"` // 課題を並行に取得するワーカー数（デフォルト: 1）
+}
+
+// AgileConfig はボード・スプリント情報（/rest/agile/1.0）の取得設定を表す構造体
+type AgileConfig struct {
+	Enabled bool `toml:"enabled"` // プロジェクトのスプリントページを生成する（デフォルト: false）
+}
+
+// ReleaseNotesConfig はバージョンごとのリリースノート生成の設定を表す構造体
+type ReleaseNotesConfig struct {
+	Enabled bool `toml:"enabled"` // プロジェクトのバージョンごとにリリースノートを生成する（デフォルト: false）
+}
+
+// FrontMatterConfig は課題のフロントマターに出力する項目の設定を表す構造体
+type FrontMatterConfig struct {
+	Fields map[string]FrontMatterFieldConfig `toml:"fields"` // フィールドIDまたはフィールド名 → フロントマターの項目
+}
+
+// FrontMatterFieldConfig はフィールドの値を出力するフロントマターの項目の設定を表す構造体
+type FrontMatterFieldConfig struct {
+	Key  string `toml:"key"`  // フロントマターのキー
+	Type string `toml:"type"` // 値の型: "string", "number", "date", "list", "user"（デフォルト: "string"）
+}
+
+// PruneConfig は削除・移動された課題の出力ファイルの整理（--prune）の設定を表す構造体
+type PruneConfig struct {
+	Action     string `toml:"action"`      // 出力ファイルの扱い: "archive"（アーカイブ先へ移動）、"delete"（削除）（デフォルト: "archive"）
+	ArchiveDir string `toml:"archive_dir"` // アーカイブ先ディレクトリ（デフォルト: markdown_dirと同じ階層の archive）
 }
 
 // LoadConfig は指定されたパスからTOML設定ファイルを読み込む
+// jiraセクションの設定は環境変数（MIGJIRA_*）で上書きできる
 func LoadConfig(path string) (*Config, error) {
+	return LoadConfigWithOverrides(path, nil)
+}
+
+// LoadConfigWithOverrides はTOML設定ファイルを読み込み、jiraセクションの設定を上書きする
+// 優先順位は flagValues（CLIフラグ） > 環境変数（MIGJIRA_*） > 設定ファイル
+// flagValuesのキーはjiraセクション内のTOMLのキー（例: "api_token", "retry.max_retries"）
+func LoadConfigWithOverrides(path string, flagValues map[string]string) (*Config, error) {
 	var config Config
 
 	// ファイルの存在確認
@@ -63,6 +184,16 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
 	}
 
+	// 環境変数・CLIフラグによる上書き
+	if err := config.JIRA.ApplyOverrides(os.LookupEnv, flagValues); err != nil {
+		return nil, fmt.Errorf("設定の上書きに失敗しました: %w", err)
+	}
+
+	// API Tokenをファイル・コマンドから取得
+	if err := config.JIRA.ResolveAPIToken(); err != nil {
+		return nil, err
+	}
+
 	// バリデーション
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("設定ファイルのバリデーションエラー: %w", err)
@@ -71,16 +202,84 @@ func LoadConfig(path string) (*Config, error) {
 	return &config, nil
 }
 
+// validateAuth は認証設定の妥当性をチェックし、デフォルト値を設定する
+func (c *JIRAConfig) validateAuth() error {
+	switch c.Auth {
+	case "":
+		// Data Centerはパーソナルアクセストークンのみで認証するためメールアドレスは不要
+		if c.IsDataCenter() {
+			c.Auth = "bearer"
+		} else {
+			c.Auth = "basic"
+		}
+	case "basic", "bearer", "oauth2":
+	default:
+		return fmt.Errorf("jira.authには \"basic\"、\"bearer\"、\"oauth2\" のいずれかを指定してください: %s", c.Auth)
+	}
+
+	if c.Auth == "basic" && c.Email == "" {
+		return fmt.Errorf("jira.emailが設定されていません")
+	}
+	if c.Auth != "oauth2" {
+		if c.APIToken == "" {
+			return fmt.Errorf("jira.api_tokenが設定されていません（api_token_file、api_token_command、環境変数 %s でも指定できます）", SettingEnvName("api_token"))
+		}
+		return nil
+	}
+
+	// OAuth 2.0の設定
+	if c.IsDataCenter() {
+		return fmt.Errorf("jira.auth = \"oauth2\" はJira Cloudでのみ使用できます")
+	}
+	if c.CloudID == "" {
+		return fmt.Errorf("jira.auth = \"oauth2\" の場合はjira.cloud_idを設定してください")
+	}
+	if c.OAuth2.ClientID == "" || c.OAuth2.ClientSecret == "" {
+		return fmt.Errorf("jira.oauth2.client_idとjira.oauth2.client_secretが設定されていません")
+	}
+	if c.OAuth2.RedirectURL == "" {
+		c.OAuth2.RedirectURL = "http://localhost:8080/callback"
+	}
+	if len(c.OAuth2.Scopes) == 0 {
+		c.OAuth2.Scopes = []string{"read:jira-work", "read:jira-user", "offline_access"}
+	}
+	if c.OAuth2.AuthURL == "" {
+		c.OAuth2.AuthURL = "https://auth.atlassian.com/authorize"
+	}
+	if c.OAuth2.TokenURL == "" {
+		c.OAuth2.TokenURL = "https://auth.atlassian.com/oauth/token"
+	}
+	if c.OAuth2.TokenCache == "" {
+		c.OAuth2.TokenCache = filepath.Join(".migjira", "oauth2_token.json")
+	}
+	return nil
+}
+
 // Validate は設定値の妥当性をチェックする
 func (c *Config) Validate() error {
 	if c.JIRA.URL == "" {
 		return fmt.Errorf("jira.urlが設定されていません")
 	}
-	if c.JIRA.Email == "" {
-		return fmt.Errorf("jira.emailが設定されていません")
+	switch c.JIRA.Deployment {
+	case "":
+		c.JIRA.Deployment = "cloud" // デフォルトはJira Cloud
+	case "cloud", "datacenter":
+	default:
+		return fmt.Errorf("jira.deploymentには \"cloud\"、\"datacenter\" のいずれかを指定してください: %s", c.JIRA.Deployment)
+	}
+	if err := c.JIRA.validateAuth(); err != nil {
+		return err
+	}
+
+	// リトライ設定のデフォルト値
+	if c.JIRA.Retry.MaxRetries == 0 {
+		c.JIRA.Retry.MaxRetries = 5
 	}
-	if c.JIRA.APIToken == "" {
-		return fmt.Errorf("jira.api_tokenが設定されていません")
+	if c.JIRA.Retry.InitialBackoffMs == 0 {
+		c.JIRA.Retry.InitialBackoffMs = 1000
+	}
+	if c.JIRA.Retry.MaxBackoffMs == 0 {
+		c.JIRA.Retry.MaxBackoffMs = 60000
 	}
 
 	// デフォルト値の設定
@@ -90,6 +289,43 @@ func (c *Config) Validate() error {
 	if c.Output.AttachmentsDir == "" {
 		c.Output.AttachmentsDir = "output/attachments"
 	}
+	if c.Output.CheckpointFile == "" {
+		c.Output.CheckpointFile = filepath.Join(".migjira", "checkpoint.jsonl")
+	}
+	if c.Output.SyncStateFile == "" {
+		// JSONスナップショットと同期状態がずれないよう、json_dirと同じ階層に置く
+		// （json_dir内に置くとconvertコマンドが課題のJSONとして読み込んでしまう）
+		baseDir := c.Output.JSONDir
+		if baseDir == "" {
+			baseDir = c.Output.MarkdownDir
+		}
+		c.Output.SyncStateFile = filepath.Join(filepath.Dir(filepath.Clean(baseDir)), "sync_state.json")
+	}
+
+	switch c.Output.FrontMatter {
+	case "":
+		c.Output.FrontMatter = "toml" // デフォルトはHugoのTOML形式
+	case "toml", "yaml", "json":
+	default:
+		return fmt.Errorf("output.front_matterには \"toml\"、\"yaml\"、\"json\" のいずれかを指定してください: %s", c.Output.FrontMatter)
+	}
+	if c.Output.Template != "" {
+		if _, err := loadIssueTemplate(c.Output.Template, nil); err != nil {
+			return fmt.Errorf("output.templateが不正です: %w", err)
+		}
+	}
+
+	// Prune設定のデフォルト値
+	switch c.Prune.Action {
+	case "":
+		c.Prune.Action = "archive" // デフォルトはアーカイブ（誤判定時に復元できるように）
+	case "archive", "delete":
+	default:
+		return fmt.Errorf("prune.actionには \"archive\"、\"delete\" のいずれかを指定してください: %s", c.Prune.Action)
+	}
+	if c.Prune.ArchiveDir == "" {
+		c.Prune.ArchiveDir = filepath.Join(filepath.Dir(filepath.Clean(c.Output.MarkdownDir)), "archive")
+	}
 
 	// Development設定のデフォルト値
 	if c.Development.ApplicationType == "" {
@@ -98,11 +334,101 @@ func (c *Config) Validate() error {
 	if c.Development.APIType == "" {
 		c.Development.APIType = "rest" // デフォルトはREST API
 	}
+	if c.JIRA.IsDataCenter() && c.Development.APIType == "graphql" {
+		return fmt.Errorf("jira.deployment = \"datacenter\" では development.api_type = \"graphql\" は使用できません")
+	}
+	if c.JIRA.IsDataCenter() && c.Display.ADF {
+		return fmt.Errorf("jira.deployment = \"datacenter\" では display.adf は使用できません")
+	}
 
 	// Display設定のデフォルト値
 	if c.Display.RankFieldId == "" {
 		c.Display.RankFieldId = "customfield_10019" // デフォルトはcustomfield_10019
 	}
+	if c.Display.SprintFieldId == "" {
+		c.Display.SprintFieldId = "customfield_10020" // Jira Cloudの標準のSprintフィールド
+	}
+	switch c.Display.RestrictedComments {
+	case "":
+		c.Display.RestrictedComments = "mark" // デフォルトは印を付けて表示
+	case "show", "mark", "omit":
+	default:
+		return fmt.Errorf("display.restricted_commentsには \"show\"、\"mark\"、\"omit\" のいずれかを指定してください: %s", c.Display.RestrictedComments)
+	}
+	switch c.Display.Source {
+	case "":
+		c.Display.Source = "wiki" // デフォルトはWiki記法から変換
+	case "wiki", "rendered", "auto":
+	default:
+		return fmt.Errorf("display.sourceには \"wiki\"、\"rendered\"、\"auto\" のいずれかを指定してください: %s", c.Display.Source)
+	}
+
+	// 見出し・ラベルの言語と文言ファイル
+	if c.Display.Language == "" {
+		c.Display.Language = "ja" // デフォルトは日本語
+	}
+	if _, err := loadMessages(c.Display.Language, c.Display.Messages); err != nil {
+		return fmt.Errorf("display.language・display.messagesが不正です: %w", err)
+	}
+
+	// フィールドの値の形式
+	for field, format := range c.Display.FieldFormats {
+		if format.Number != "" && strings.Contains(fmt.Sprintf(format.Number, 1.5), "%!") {
+			return fmt.Errorf("display.field_formats の %s のnumberが数値の書式ではありません: %s", field, format.Number)
+		}
+		// レイアウトの要素を含まない場合は日時に関係なく同じ文字列になる
+		if format.Date != "" && time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).Format(format.Date) == format.Date {
+			return fmt.Errorf("display.field_formats の %s のdateが日付のレイアウトではありません: %s", field, format.Date)
+		}
+	}
+
+	// アイコン（Jiraにプロジェクトのアイコンの一覧は無いため、"jira" は課題タイプ・優先度・ステータスのみ）
+	if c.Display.Icons.Project == "" {
+		c.Display.Icons.Project = defaultProjectIcon
+	}
+	if c.Display.Icons.Project == jiraIconValue {
+		return fmt.Errorf("display.icons.projectには \"%s\" を指定できません", jiraIconValue)
+	}
 
+	// フロントマターに出力するフィールドの設定
+	if err := c.FrontMatter.validate(); err != nil {
+		return err
+	}
+
+	// Performance設定のデフォルト値
+	if c.Performance.Workers < 1 {
+		c.Performance.Workers = 1
+	}
+
+	return nil
+}
+
+// validate はフロントマターに出力するフィールドの設定の妥当性をチェックし、デフォルト値を設定する
+func (c *FrontMatterConfig) validate() error {
+	fields := make([]string, 0, len(c.Fields))
+	for field := range c.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	keys := make(map[string]string)
+	for _, field := range fields {
+		mapping := c.Fields[field]
+		if mapping.Key == "" {
+			return fmt.Errorf("front_matter.fields の %s にkeyが設定されていません", field)
+		}
+		switch mapping.Type {
+		case "":
+			mapping.Type = "string" // デフォルトは文字列
+			c.Fields[field] = mapping
+		case "string", "number", "date", "list", "user":
+		default:
+			return fmt.Errorf("front_matter.fields の %s のtypeには \"string\"、\"number\"、\"date\"、\"list\"、\"user\" のいずれかを指定してください: %s", field, mapping.Type)
+		}
+		if other, exists := keys[mapping.Key]; exists {
+			return fmt.Errorf("front_matter.fields の %s と %s に同じkeyが設定されています: %s", other, field, mapping.Key)
+		}
+		keys[mapping.Key] = field
+	}
 	return nil
 }
@@ -255,6 +255,304 @@ func TestValidate(t *testing.T) {
 			wantErr:     true,
 			errContains: "jira.api_tokenが設定されていません",
 		},
+		{
+			name: "異常系: display.restricted_commentsが不正",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				Display: DisplayConfig{
+					RestrictedComments: "hide",
+				},
+			},
+			wantErr:     true,
+			errContains: "display.restricted_comments",
+		},
+		{
+			name: "異常系: display.sourceが不正",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				Display: DisplayConfig{
+					Source: "html",
+				},
+			},
+			wantErr:     true,
+			errContains: "display.source",
+		},
+		{
+			name: "異常系: output.templateのファイルが存在しない",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				Output: OutputConfig{
+					Template: "testdata/no-such-template.md.tmpl",
+				},
+			},
+			wantErr:     true,
+			errContains: "output.template",
+		},
+		{
+			name: "異常系: output.front_matterが不正",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				Output: OutputConfig{
+					FrontMatter: "xml",
+				},
+			},
+			wantErr:     true,
+			errContains: "output.front_matter",
+		},
+		{
+			name: "異常系: display.icons.projectにjiraを指定",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				Display: DisplayConfig{
+					Icons: IconsConfig{Project: "jira"},
+				},
+			},
+			wantErr:     true,
+			errContains: "display.icons.project",
+		},
+		{
+			name: "異常系: display.languageが組み込みに無い言語",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				Display: DisplayConfig{
+					Language: "fr",
+				},
+			},
+			wantErr:     true,
+			errContains: "display.language",
+		},
+		{
+			name: "異常系: display.field_formatsのnumberが不正",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				Display: DisplayConfig{
+					FieldFormats: map[string]FieldFormatConfig{
+						"customfield_10016": {Number: "%d"},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "display.field_formats",
+		},
+		{
+			name: "異常系: display.field_formatsのdateが不正",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				Display: DisplayConfig{
+					FieldFormats: map[string]FieldFormatConfig{
+						"Start date": {Date: "YYYY/MM/DD"},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "display.field_formats",
+		},
+		{
+			name: "異常系: front_matter.fieldsのtypeが不正",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				FrontMatter: FrontMatterConfig{
+					Fields: map[string]FrontMatterFieldConfig{
+						"customfield_10016": {Key: "story_points", Type: "float"},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "front_matter.fields",
+		},
+		{
+			name: "異常系: front_matter.fieldsのkeyが重複",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				FrontMatter: FrontMatterConfig{
+					Fields: map[string]FrontMatterFieldConfig{
+						"customfield_10016": {Key: "points"},
+						"Story Points":      {Key: "points", Type: "number"},
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "同じkey",
+		},
+		{
+			name: "異常系: prune.actionが不正",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+				},
+				Prune: PruneConfig{
+					Action: "move",
+				},
+			},
+			wantErr:     true,
+			errContains: "prune.action",
+		},
+		{
+			name: "正常系: Data Centerではjira.emailは不要",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:        "https://jira.example.com",
+					APIToken:   "test-pat",
+					Deployment: "datacenter",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "異常系: jira.deploymentが不正",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:        "https://test.atlassian.net",
+					Email:      "test@example.com",
+					APIToken:   "test-token-123",
+					Deployment: "server",
+				},
+			},
+			wantErr:     true,
+			errContains: "jira.deployment",
+		},
+		{
+			name: "異常系: Data CenterでGraphQL APIを指定",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:        "https://jira.example.com",
+					APIToken:   "test-pat",
+					Deployment: "datacenter",
+				},
+				Development: DevelopmentConfig{
+					APIType: "graphql",
+				},
+			},
+			wantErr:     true,
+			errContains: "development.api_type",
+		},
+		{
+			name: "異常系: Data CenterでADFの取得を指定",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:        "https://jira.example.com",
+					APIToken:   "test-pat",
+					Deployment: "datacenter",
+				},
+				Display: DisplayConfig{
+					ADF: true,
+				},
+			},
+			wantErr:     true,
+			errContains: "display.adf",
+		},
+		{
+			name: "異常系: jira.authが不正",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					Email:    "test@example.com",
+					APIToken: "test-token-123",
+					Auth:     "digest",
+				},
+			},
+			wantErr:     true,
+			errContains: "jira.auth",
+		},
+		{
+			name: "正常系: bearer認証ではjira.emailは不要",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:      "https://test.atlassian.net",
+					APIToken: "scoped-token",
+					Auth:     "bearer",
+					CloudID:  "cloud-123",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "正常系: OAuth 2.0ではjira.api_tokenは不要",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:     "https://test.atlassian.net",
+					Auth:    "oauth2",
+					CloudID: "cloud-123",
+					OAuth2: OAuth2Config{
+						ClientID:     "client-id",
+						ClientSecret: "client-secret",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "異常系: OAuth 2.0でjira.cloud_idが空",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:  "https://test.atlassian.net",
+					Auth: "oauth2",
+					OAuth2: OAuth2Config{
+						ClientID:     "client-id",
+						ClientSecret: "client-secret",
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "jira.cloud_id",
+		},
+		{
+			name: "異常系: OAuth 2.0でclient_secretが空",
+			config: Config{
+				JIRA: JIRAConfig{
+					URL:     "https://test.atlassian.net",
+					Auth:    "oauth2",
+					CloudID: "cloud-123",
+					OAuth2: OAuth2Config{
+						ClientID: "client-id",
+					},
+				},
+			},
+			wantErr:     true,
+			errContains: "jira.oauth2.client_secret",
+		},
 		{
 			name: "正常系: デフォルト値が設定される",
 			config: Config{
@@ -295,6 +593,19 @@ func TestValidate(t *testing.T) {
 				return
 			}
 
+			// OAuth 2.0のデフォルト値のテスト
+			if tt.config.JIRA.Auth == "oauth2" {
+				if tt.config.JIRA.OAuth2.TokenURL != "https://auth.atlassian.com/oauth/token" {
+					t.Errorf("OAuth2.TokenURLのデフォルト値が期待と異なります: %q", tt.config.JIRA.OAuth2.TokenURL)
+				}
+				if len(tt.config.JIRA.OAuth2.Scopes) == 0 {
+					t.Errorf("OAuth2.Scopesのデフォルト値が設定されていません")
+				}
+				if got := tt.config.JIRA.APIBaseURL(); got != "https://api.atlassian.com/ex/jira/cloud-123" {
+					t.Errorf("APIBaseURL() = %q", got)
+				}
+			}
+
 			// デフォルト値のテスト
 			if contains(tt.name, "デフォルト値") {
 				if tt.config.Output.MarkdownDir != "output/markdown" {
@@ -303,9 +614,45 @@ func TestValidate(t *testing.T) {
 				if tt.config.Output.AttachmentsDir != "output/attachments" {
 					t.Errorf("AttachmentsDirのデフォルト値が期待と異なります: %q", tt.config.Output.AttachmentsDir)
 				}
+				if tt.config.Output.CheckpointFile != filepath.Join(".migjira", "checkpoint.jsonl") {
+					t.Errorf("CheckpointFileのデフォルト値が期待と異なります: %q", tt.config.Output.CheckpointFile)
+				}
+				if tt.config.Output.SyncStateFile != filepath.Join("output", "sync_state.json") {
+					t.Errorf("SyncStateFileのデフォルト値が期待と異なります: %q", tt.config.Output.SyncStateFile)
+				}
 				if tt.config.Development.ApplicationType != "bitbucket" {
 					t.Errorf("ApplicationTypeのデフォルト値が期待と異なります: %q", tt.config.Development.ApplicationType)
 				}
+				if tt.config.JIRA.Retry.MaxRetries != 5 {
+					t.Errorf("Retry.MaxRetriesのデフォルト値が期待と異なります: %d", tt.config.JIRA.Retry.MaxRetries)
+				}
+				if tt.config.JIRA.Retry.InitialBackoffMs != 1000 {
+					t.Errorf("Retry.InitialBackoffMsのデフォルト値が期待と異なります: %d", tt.config.JIRA.Retry.InitialBackoffMs)
+				}
+				if tt.config.JIRA.Retry.MaxBackoffMs != 60000 {
+					t.Errorf("Retry.MaxBackoffMsのデフォルト値が期待と異なります: %d", tt.config.JIRA.Retry.MaxBackoffMs)
+				}
+				if tt.config.Display.RestrictedComments != "mark" {
+					t.Errorf("Display.RestrictedCommentsのデフォルト値が期待と異なります: %q", tt.config.Display.RestrictedComments)
+				}
+				if tt.config.JIRA.Deployment != "cloud" {
+					t.Errorf("JIRA.Deploymentのデフォルト値が期待と異なります: %q", tt.config.JIRA.Deployment)
+				}
+				if tt.config.JIRA.Auth != "basic" {
+					t.Errorf("JIRA.Authのデフォルト値が期待と異なります: %q", tt.config.JIRA.Auth)
+				}
+				if tt.config.Display.SprintFieldId != "customfield_10020" {
+					t.Errorf("Display.SprintFieldIdのデフォルト値が期待と異なります: %q", tt.config.Display.SprintFieldId)
+				}
+				if tt.config.Prune.Action != "archive" {
+					t.Errorf("Prune.Actionのデフォルト値が期待と異なります: %q", tt.config.Prune.Action)
+				}
+				if tt.config.Prune.ArchiveDir != filepath.Join("output", "archive") {
+					t.Errorf("Prune.ArchiveDirのデフォルト値が期待と異なります: %q", tt.config.Prune.ArchiveDir)
+				}
+				if tt.config.Performance.Workers != 1 {
+					t.Errorf("Performance.Workersのデフォルト値が期待と異なります: %d", tt.config.Performance.Workers)
+				}
 			}
 		})
 	}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jiraセクションの設定の上書きと、API Tokenの取得元の解決
+//
+// jiraセクションのすべての項目は環境変数・CLIフラグで上書きできる。
+// 名前はTOMLのキーから機械的に決まる（例: retry.max_retries）
+//   - 環境変数: MIGJIRA_RETRY_MAX_RETRIES
+//   - CLIフラグ: --jira-retry-max-retries
+//
+// 優先順位は CLIフラグ > 環境変数 > 設定ファイル。
+// API Tokenの取得元（api_token、api_token_file、api_token_command）は1つの設定として扱い、
+// 上位の階層でいずれかを指定した場合は下位の階層の取得元をすべて無視する。
+// 同じ階層で複数を指定した場合は api_token > api_token_file > api_token_command の順に使用する。
+
+// envPrefix はjiraセクションの設定を上書きする環境変数の接頭辞
+const envPrefix = "MIGJIRA_"
+
+// apiTokenCommandTimeout はapi_token_commandの実行のタイムアウト
+const apiTokenCommandTimeout = 30 * time.Second
+
+// apiTokenSourceKeys はAPI Tokenの取得元のキー（優先順）
+var apiTokenSourceKeys = []string{"api_token", "api_token_file", "api_token_command"}
+
+// JIRASettingKeys はjiraセクションの設定項目のキー（TOMLのキーを"."で連結したもの）を返す
+func JIRASettingKeys() []string {
+	var keys []string
+	collectSettingKeys(reflect.TypeOf(JIRAConfig{}), "", &keys)
+	return keys
+}
+
+// collectSettingKeys は構造体のtomlタグからキーを再帰的に収集する
+func collectSettingKeys(t reflect.Type, prefix string, keys *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("toml")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		if field.Type.Kind() == reflect.Struct {
+			collectSettingKeys(field.Type, prefix+tag+".", keys)
+			continue
+		}
+		*keys = append(*keys, prefix+tag)
+	}
+}
+
+// SettingEnvName は設定項目を上書きする環境変数名を返す（例: api_token → MIGJIRA_API_TOKEN）
+func SettingEnvName(key string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+// SettingFlagName は設定項目を上書きするCLIフラグ名を返す（例: api_token → jira-api-token）
+func SettingFlagName(key string) string {
+	return "jira-" + strings.NewReplacer(".", "-", "_", "-").Replace(key)
+}
+
+// Set はキーで指定した設定項目に文字列の値を設定する
+// 数値はintに変換し、リスト（scopes等）はカンマ区切りで指定する
+func (c *JIRAConfig) Set(key, value string) error {
+	v := reflect.ValueOf(c).Elem()
+	for _, name := range strings.Split(key, ".") {
+		field, ok := fieldByTOMLTag(v, name)
+		if !ok {
+			return fmt.Errorf("jira.%s は設定項目ではありません", key)
+		}
+		v = field
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(value)
+	case reflect.Int:
+		n, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			return fmt.Errorf("jira.%s には整数を指定してください: %s", key, value)
+		}
+		v.SetInt(int64(n))
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("jira.%s は上書きできない設定項目です", key)
+	}
+	return nil
+}
+
+// fieldByTOMLTag はtomlタグが一致する構造体のフィールドを返す
+func fieldByTOMLTag(v reflect.Value, tag string) (reflect.Value, bool) {
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}, false
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).Tag.Get("toml") == tag {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// ApplyOverrides は環境変数・CLIフラグの値で設定を上書きする（CLIフラグが優先）
+// lookupEnvにはos.LookupEnv（テストでは差し替え）、flagValuesにはキーごとのCLIフラグの値を渡す
+func (c *JIRAConfig) ApplyOverrides(lookupEnv func(string) (string, bool), flagValues map[string]string) error {
+	envValues := make(map[string]string)
+	if lookupEnv != nil {
+		for _, key := range JIRASettingKeys() {
+			if value, ok := lookupEnv(SettingEnvName(key)); ok {
+				envValues[key] = value
+			}
+		}
+	}
+
+	for _, layer := range []struct {
+		name   string
+		values map[string]string
+	}{
+		{"環境変数", envValues},
+		{"CLIフラグ", flagValues},
+	} {
+		if len(layer.values) == 0 {
+			continue
+		}
+
+		// API Tokenの取得元を指定した場合は下位の階層の取得元を無視する
+		for _, key := range apiTokenSourceKeys {
+			if _, ok := layer.values[key]; ok {
+				c.APIToken, c.APITokenFile, c.APITokenCommand = "", "", ""
+				break
+			}
+		}
+
+		keys := make([]string, 0, len(layer.values))
+		for key := range layer.values {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if err := c.Set(key, layer.values[key]); err != nil {
+				return fmt.Errorf("%s: %w", layer.name, err)
+			}
+			slog.Debug("設定を上書き", "source", layer.name, "key", "jira."+key)
+		}
+	}
+	return nil
+}
+
+// ResolveAPIToken はapi_tokenが未設定の場合にapi_token_file・api_token_commandからAPI Tokenを取得する
+func (c *JIRAConfig) ResolveAPIToken() error {
+	switch {
+	case c.APIToken != "":
+		return nil
+	case c.APITokenFile != "":
+		data, err := os.ReadFile(c.APITokenFile)
+		if err != nil {
+			return fmt.Errorf("jira.api_token_fileの読み込みに失敗しました: %w", err)
+		}
+		c.APIToken = strings.TrimSpace(string(data))
+		if c.APIToken == "" {
+			return fmt.Errorf("jira.api_token_fileが空です: %s", c.APITokenFile)
+		}
+	case c.APITokenCommand != "":
+		token, err := runTokenCommand(c.APITokenCommand)
+		if err != nil {
+			return fmt.Errorf("jira.api_token_commandの実行に失敗しました: %w", err)
+		}
+		c.APIToken = token
+	}
+	return nil
+}
+
+// runTokenCommand はシェルでコマンドを実行し、標準出力（前後の空白を除く）を返す
+func runTokenCommand(command string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), apiTokenCommandTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	cmd.Stdin = os.Stdin
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	token := strings.TrimSpace(stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("コマンドの出力が空です")
+	}
+	return token, nil
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+// TestApplyOverrides は環境変数・CLIフラグによるjiraセクションの上書きと優先順位のテスト
+func TestApplyOverrides(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     JIRAConfig
+		env        map[string]string
+		flagValues map[string]string
+		want       JIRAConfig
+		wantErr    bool
+	}{
+		{
+			name:   "環境変数で上書き",
+			config: JIRAConfig{URL: "https://file.atlassian.net", Email: "file@example.com"},
+			env: map[string]string{
+				"MIGJIRA_API_TOKEN":         "env-token",
+				"MIGJIRA_RETRY_MAX_RETRIES": "3",
+				"MIGJIRA_OAUTH2_SCOPES":     "read:jira-work, offline_access",
+			},
+			want: JIRAConfig{
+				URL:      "https://file.atlassian.net",
+				Email:    "file@example.com",
+				APIToken: "env-token",
+				OAuth2:   OAuth2Config{Scopes: []string{"read:jira-work", "offline_access"}},
+				Retry:    RetryConfig{MaxRetries: 3},
+			},
+		},
+		{
+			name:       "CLIフラグは環境変数より優先",
+			config:     JIRAConfig{URL: "https://file.atlassian.net"},
+			env:        map[string]string{"MIGJIRA_URL": "https://env.atlassian.net", "MIGJIRA_EMAIL": "env@example.com"},
+			flagValues: map[string]string{"url": "https://flag.atlassian.net"},
+			want:       JIRAConfig{URL: "https://flag.atlassian.net", Email: "env@example.com"},
+		},
+		{
+			name:   "上位の階層でAPI Tokenの取得元を指定すると下位の取得元は無視",
+			config: JIRAConfig{APIToken: "file-token"},
+			env:    map[string]string{"MIGJIRA_API_TOKEN_COMMAND": "echo env"},
+			want:   JIRAConfig{APITokenCommand: "echo env"},
+		},
+		{
+			name:       "CLIフラグのAPI Tokenは環境変数のトークンファイルより優先",
+			config:     JIRAConfig{APITokenFile: "/path/to/file"},
+			env:        map[string]string{"MIGJIRA_API_TOKEN_FILE": "/path/to/env"},
+			flagValues: map[string]string{"api_token": "flag-token"},
+			want:       JIRAConfig{APIToken: "flag-token"},
+		},
+		{
+			name:    "整数の形式が不正",
+			env:     map[string]string{"MIGJIRA_RETRY_MAX_BACKOFF_MS": "1m"},
+			wantErr: true,
+		},
+		{
+			name:       "存在しないキー",
+			flagValues: map[string]string{"password": "x"},
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookupEnv := func(name string) (string, bool) {
+				v, ok := tt.env[name]
+				return v, ok
+			}
+			err := tt.config.ApplyOverrides(lookupEnv, tt.flagValues)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("エラーが期待されましたが、nilが返されました")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("予期しないエラー: %v", err)
+			}
+			if !reflect.DeepEqual(tt.config, tt.want) {
+				t.Errorf("上書き結果が期待と異なります\n期待: %+v\n実際: %+v", tt.want, tt.config)
+			}
+		})
+	}
+}
+
+// TestSettingNames は設定項目のキーから環境変数名・CLIフラグ名を生成するテスト
+func TestSettingNames(t *testing.T) {
+	if got := SettingEnvName("oauth2.client_secret"); got != "MIGJIRA_OAUTH2_CLIENT_SECRET" {
+		t.Errorf("SettingEnvName() = %q", got)
+	}
+	if got := SettingFlagName("retry.max_retries"); got != "jira-retry-max-retries" {
+		t.Errorf("SettingFlagName() = %q", got)
+	}
+
+	// すべての設定項目を上書きできる
+	for _, key := range JIRASettingKeys() {
+		var c JIRAConfig
+		if err := c.Set(key, "1"); err != nil {
+			t.Errorf("jira.%s を設定できません: %v", key, err)
+		}
+	}
+}
+
+// TestResolveAPIToken はapi_token_file・api_token_commandからのAPI Token取得のテスト
+func TestResolveAPIToken(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenFile, []byte("file-token\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		config  JIRAConfig
+		want    string
+		wantErr bool
+		unix    bool // シェルコマンドを使用するテスト
+	}{
+		{
+			name:   "api_tokenが優先",
+			config: JIRAConfig{APIToken: "plain", APITokenFile: tokenFile},
+			want:   "plain",
+		},
+		{
+			name:   "api_token_fileから読み込み（末尾の改行を除去）",
+			config: JIRAConfig{APITokenFile: tokenFile, APITokenCommand: "echo cmd"},
+			want:   "file-token",
+		},
+		{
+			name:    "api_token_fileが存在しない",
+			config:  JIRAConfig{APITokenFile: filepath.Join(t.TempDir(), "missing")},
+			wantErr: true,
+		},
+		{
+			name:   "api_token_commandの標準出力",
+			config: JIRAConfig{APITokenCommand: "printf '  cmd-token\\n'"},
+			want:   "cmd-token",
+			unix:   true,
+		},
+		{
+			name:    "api_token_commandが失敗",
+			config:  JIRAConfig{APITokenCommand: "echo denied >&2; exit 1"},
+			wantErr: true,
+			unix:    true,
+		},
+		{
+			name:   "取得元がない場合は何もしない",
+			config: JIRAConfig{},
+			want:   "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.unix && runtime.GOOS == "windows" {
+				t.Skip("シェルコマンドのテストはWindowsではスキップ")
+			}
+			err := tt.config.ResolveAPIToken()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("エラーが期待されましたが、nilが返されました")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("予期しないエラー: %v", err)
+			}
+			if tt.config.APIToken != tt.want {
+				t.Errorf("APIToken = %q, want %q", tt.config.APIToken, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,15 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
+	"math"
 	"reflect"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/andygrunwald/go-jira/v2/cloud"
 )
@@ -28,6 +33,23 @@ func GetAllCustomFields(issue *cloud.Issue) map[string]interface{} {
 	return customFields
 }
 
+// GetAllFieldValues は課題の全てのフィールド（システムフィールドとカスタムフィールド）の値をフィールドIDで取得する
+// 値はAPIのレスポンスと同じ形式（JSONをデコードした値）になる
+func GetAllFieldValues(issue *cloud.Issue) map[string]interface{} {
+	values := make(map[string]interface{})
+	if issue == nil || issue.Fields == nil {
+		return values
+	}
+	data, err := json.Marshal(issue.Fields)
+	if err != nil {
+		return values
+	}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return make(map[string]interface{})
+	}
+	return values
+}
+
 // GetSortedCustomFieldKeys はカスタムフィールドのキーをソート済みで返す
 func GetSortedCustomFieldKeys(customFields map[string]interface{}) []string {
 	keys := make([]string, 0, len(customFields))
@@ -53,19 +75,29 @@ func BuildFieldNameCache(fields []cloud.Field) FieldNameCache {
 // GetFieldName はキャッシュからフィールド名を取得する
 // 見つからない場合はフォールバック名を返す
 func (cache FieldNameCache) GetFieldName(fieldID string) string {
+	return cache.fieldName(fieldID, nil)
+}
+
+// fieldName はキャッシュからフィールド名を取得する（フォールバック名はmessagesの文言、nilの場合は既定の文言）
+func (cache FieldNameCache) fieldName(fieldID string, messages Messages) string {
 	if name, exists := cache[fieldID]; exists && name != "" {
 		return name
 	}
 	// フォールバック: 従来の形式
-	return FormatCustomFieldName(fieldID)
+	return formatCustomFieldName(fieldID, messages)
 }
 
 // FormatCustomFieldName はカスタムフィールドIDを読みやすい名前に変換する（フォールバック用）
 func FormatCustomFieldName(fieldID string) string {
+	return formatCustomFieldName(fieldID, nil)
+}
+
+// formatCustomFieldName はカスタムフィールドIDをmessagesの文言で読みやすい名前に変換する
+func formatCustomFieldName(fieldID string, messages Messages) string {
 	// "customfield_10001" -> "カスタムフィールド 10001"
 	if strings.HasPrefix(fieldID, "customfield_") {
 		id := strings.TrimPrefix(fieldID, "customfield_")
-		return fmt.Sprintf("カスタムフィールド %s", id)
+		return messages.Text("custom_field_name", id)
 	}
 	return fieldID
 }
@@ -154,14 +186,19 @@ func formatDevelopmentField(v map[string]interface{}) string {
 
 // FormatCustomFieldValue はカスタムフィールドの値を文字列に変換する
 func FormatCustomFieldValue(value interface{}) string {
+	return formatCustomFieldValue(value, nil)
+}
+
+// formatCustomFieldValue はカスタムフィールドの値を文字列に変換する（値が無い場合等はmessagesの文言、nilの場合は既定の文言）
+func formatCustomFieldValue(value interface{}, messages Messages) string {
 	if value == nil {
-		return "未設定"
+		return messages.Text("not_set")
 	}
 
 	switch v := value.(type) {
 	case string:
 		if v == "" {
-			return "未設定"
+			return messages.Text("not_set")
 		}
 		// 開発フィールド（Bitbucket、GitHub等）の文字列表現を検出
 		// JIRAから既に文字列化されている場合の対処
@@ -174,21 +211,22 @@ func FormatCustomFieldValue(value interface{}) string {
 		return v
 
 	case float64:
-		return fmt.Sprintf("%.2f", v)
+		// 整数の値（ストーリーポイント等）は小数点以下を出力しない
+		return strconv.FormatFloat(v, 'f', -1, 64)
 
 	case int, int64:
 		return fmt.Sprintf("%d", v)
 
 	case bool:
 		if v {
-			return "はい"
+			return messages.Text("yes")
 		}
-		return "いいえ"
+		return messages.Text("no")
 
 	case []interface{}:
 		// 配列の場合は各要素を抽出
 		if len(v) == 0 {
-			return "未設定"
+			return messages.Text("not_set")
 		}
 		parts := make([]string, 0, len(v))
 		for _, item := range v {
@@ -213,6 +251,11 @@ func FormatCustomFieldValue(value interface{}) string {
 			return ""
 		}
 
+		// 複数行テキスト（ADF）の場合はテキストのみを返す
+		if doc, ok := parseADF(v); ok {
+			return adfPlainText(doc)
+		}
+
 		// 開発統合フィールド（Bitbucket、GitHub等）の特別処理
 		if isDevelopmentField(v) {
 			return formatDevelopmentField(v)
@@ -262,6 +305,100 @@ func FormatCustomFieldValue(value interface{}) string {
 	}
 }
 
+// CustomFieldList はフィールドの値を文字列のリストに変換する（配列は要素ごと、単一の値は1要素のリスト）
+// 各要素はFormatCustomFieldValueと同じ規則でmessagesの文言（nilの場合は既定の文言）を使って文字列にする
+// 空の要素（nil・空文字列）は含めない
+func CustomFieldList(value interface{}, messages Messages) []string {
+	if IsCustomFieldEmpty(value) {
+		return nil
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if IsCustomFieldEmpty(item) {
+			continue
+		}
+		if s := formatCustomFieldValue(item, messages); s != "" {
+			list = append(list, s)
+		}
+	}
+	return list
+}
+
+// CustomFieldNumber はフィールドの値を数値に変換する（整数の場合はint、それ以外はfloat64）
+func CustomFieldNumber(value interface{}) (interface{}, bool) {
+	var number float64
+	switch v := value.(type) {
+	case nil:
+		return nil, false
+	case float64:
+		number = v
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	default:
+		// 文字列や選択肢の値（"value"・"name"）は文字列にしてから数値に変換する
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(FormatCustomFieldValue(v)), 64)
+		if err != nil {
+			return nil, false
+		}
+		number = parsed
+	}
+	if number == math.Trunc(number) && math.Abs(number) < 1<<53 {
+		return int(number), true
+	}
+	return number, true
+}
+
+// CustomFieldDate はフィールドの値を日付（YYYY-MM-DD の文字列）または日時（time.Time、秒未満は切り捨て）に変換する
+func CustomFieldDate(value interface{}) (interface{}, bool) {
+	if IsCustomFieldEmpty(value) {
+		return nil, false
+	}
+	s := strings.TrimSpace(FormatCustomFieldValue(value))
+	if t, err := time.Parse("2006-01-02", s); err == nil {
+		return t.Format("2006-01-02"), true
+	}
+	// JIRAの日時形式（2026-01-22T00:43:07.025+0900）とRFC3339
+	for _, layout := range []string{"2006-01-02T15:04:05.000-0700", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Truncate(time.Second), true
+		}
+	}
+	return nil, false
+}
+
+// CustomFieldUsers はユーザー（ユーザーピッカー等）のフィールドの値からユーザーのリストを取得する
+func CustomFieldUsers(value interface{}) []*cloud.User {
+	items, ok := value.([]interface{})
+	if !ok {
+		items = []interface{}{value}
+	}
+	var users []*cloud.User
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		user := &cloud.User{}
+		user.AccountID, _ = obj["accountId"].(string)
+		user.AccountType, _ = obj["accountType"].(string)
+		user.DisplayName, _ = obj["displayName"].(string)
+		if user.DisplayName == "" {
+			// Data Centerのユーザーはname（ユーザー名）のみの場合がある
+			user.DisplayName, _ = obj["name"].(string)
+		}
+		if user.DisplayName != "" || user.AccountID != "" {
+			users = append(users, user)
+		}
+	}
+	return users
+}
+
 // UserMapping はアカウントID→表示名のマッピング
 type UserMapping map[string]string
 
@@ -381,3 +518,77 @@ func extractDevelopmentSummary(v map[string]interface{}) struct{ Count int; Stat
 
 	return result
 }
+
+// legacySprintAttrPattern は文字列形式のスプリント値（"com.atlassian.greenhopper.service.sprint.Sprint@xxx[id=1,...]"）の属性名にマッチする
+// 名前やゴールにカンマが含まれても分割されないよう、既知の属性名のみを区切りとして扱う
+var legacySprintAttrPattern = regexp.MustCompile(`(?:^|,)(id|rapidViewId|state|name|goal|startDate|endDate|completeDate|activatedDate|sequence|synced|autoStartStop|incompleteIssuesDestinationId)=`)
+
+// ParseSprintField はスプリントフィールドの値をスプリント情報のリストに変換する
+// Jira Cloudのオブジェクト形式と、古いJIRAの文字列形式の両方に対応する
+func ParseSprintField(value interface{}) []SprintInfo {
+	items, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	sprints := make([]SprintInfo, 0, len(items))
+	for _, item := range items {
+		switch v := item.(type) {
+		case map[string]interface{}:
+			data, err := json.Marshal(v)
+			if err != nil {
+				continue
+			}
+			var sprint SprintInfo
+			if err := json.Unmarshal(data, &sprint); err != nil {
+				continue
+			}
+			sprints = append(sprints, sprint)
+		case string:
+			if sprint, ok := parseLegacySprint(v); ok {
+				sprints = append(sprints, sprint)
+			}
+		}
+	}
+	return sprints
+}
+
+// parseLegacySprint は文字列形式のスプリント値を解析する
+func parseLegacySprint(s string) (SprintInfo, bool) {
+	start := strings.Index(s, "[")
+	end := strings.LastIndex(s, "]")
+	if start < 0 || end <= start {
+		return SprintInfo{}, false
+	}
+	body := s[start+1 : end]
+
+	attrs := make(map[string]string)
+	matches := legacySprintAttrPattern.FindAllStringSubmatchIndex(body, -1)
+	for i, m := range matches {
+		valueEnd := len(body)
+		if i+1 < len(matches) {
+			valueEnd = matches[i+1][0]
+		}
+		value := body[m[1]:valueEnd]
+		if value == "<null>" {
+			value = ""
+		}
+		attrs[body[m[2]:m[3]]] = value
+	}
+	if attrs["name"] == "" {
+		return SprintInfo{}, false
+	}
+
+	id, _ := strconv.Atoi(attrs["id"])
+	boardID, _ := strconv.Atoi(attrs["rapidViewId"])
+	return SprintInfo{
+		ID:           id,
+		Name:         attrs["name"],
+		State:        strings.ToLower(attrs["state"]),
+		BoardID:      boardID,
+		Goal:         attrs["goal"],
+		StartDate:    attrs["startDate"],
+		EndDate:      attrs["endDate"],
+		CompleteDate: attrs["completeDate"],
+	}, true
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/andygrunwald/go-jira/v2/cloud"
 )
@@ -328,6 +329,60 @@ func TestFormatCustomFieldValue(t *testing.T) {
 	}
 }
 
+// TestCustomFieldTypedValues はフロントマター用にフィールドの値を型に変換するテスト
+func TestCustomFieldTypedValues(t *testing.T) {
+	numberTests := []struct {
+		value    interface{}
+		expected interface{}
+		ok       bool
+	}{
+		{float64(5), 5, true},
+		{2.5, 2.5, true},
+		{"13", 13, true},
+		{map[string]interface{}{"value": "8"}, 8, true},
+		{"見積もり不可", nil, false},
+		{nil, nil, false},
+	}
+	for _, tt := range numberTests {
+		result, ok := CustomFieldNumber(tt.value)
+		if ok != tt.ok || !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("CustomFieldNumber(%v) = %v, %v, expected %v, %v", tt.value, result, ok, tt.expected, tt.ok)
+		}
+	}
+
+	if result, ok := CustomFieldDate("2025-01-10"); !ok || result != "2025-01-10" {
+		t.Errorf("CustomFieldDate(日付) = %v, %v", result, ok)
+	}
+	result, ok := CustomFieldDate("2026-01-22T00:43:07.025+0900")
+	if tm, isTime := result.(time.Time); !ok || !isTime || tm.Format(time.RFC3339) != "2026-01-22T00:43:07+09:00" {
+		t.Errorf("CustomFieldDate(日時) = %v, %v", result, ok)
+	}
+	if _, ok := CustomFieldDate("来週"); ok {
+		t.Error("CustomFieldDate(日付以外) が変換されました")
+	}
+
+	list := CustomFieldList([]interface{}{map[string]interface{}{"value": "A"}, map[string]interface{}{"value": "B"}}, nil)
+	if !reflect.DeepEqual(list, []string{"A", "B"}) {
+		t.Errorf("CustomFieldList(配列) = %v", list)
+	}
+	if list := CustomFieldList("単一", nil); !reflect.DeepEqual(list, []string{"単一"}) {
+		t.Errorf("CustomFieldList(単一) = %v", list)
+	}
+	// 空の要素は含めず、真偽値はmessagesの文言にする
+	if list := CustomFieldList([]interface{}{nil, "", true, "C"}, builtinMessages["en"]); !reflect.DeepEqual(list, []string{"Yes", "C"}) {
+		t.Errorf("CustomFieldList(空の要素・英語) = %v", list)
+	}
+
+	users := CustomFieldUsers([]interface{}{
+		map[string]interface{}{"accountId": "a1", "displayName": "ユーザー1"},
+		map[string]interface{}{"name": "user2"},
+		"不正な値",
+	})
+	if len(users) != 2 || users[0].AccountID != "a1" || users[0].DisplayName != "ユーザー1" || users[1].DisplayName != "user2" {
+		t.Errorf("CustomFieldUsers() = %v", users)
+	}
+}
+
 // TestBuildUserMappingFromIssue は課題からユーザーマッピングを構築するテスト
 func TestBuildUserMappingFromIssue(t *testing.T) {
 	tests := []struct {
@@ -591,3 +646,53 @@ func TestFormatDevelopmentField(t *testing.T) {
 		})
 	}
 }
+
+// TestParseSprintField はSprintフィールドのオブジェクト形式と文字列形式の解析を確認する
+func TestParseSprintField(t *testing.T) {
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []SprintInfo
+	}{
+		{
+			name: "オブジェクト形式（Jira Cloud）",
+			value: []interface{}{
+				map[string]interface{}{
+					"id": float64(1), "name": "Sprint 1", "state": "closed", "boardId": float64(3),
+					"goal": "ログイン機能", "startDate": "2025-01-06T00:00:00.000Z", "endDate": "2025-01-19T00:00:00.000Z",
+					"completeDate": "2025-01-20T01:00:00.000Z",
+				},
+				map[string]interface{}{"id": float64(2), "name": "Sprint 2", "state": "active", "boardId": float64(3)},
+			},
+			want: []SprintInfo{
+				{ID: 1, Name: "Sprint 1", State: "closed", BoardID: 3, Goal: "ログイン機能",
+					StartDate: "2025-01-06T00:00:00.000Z", EndDate: "2025-01-19T00:00:00.000Z", CompleteDate: "2025-01-20T01:00:00.000Z"},
+				{ID: 2, Name: "Sprint 2", State: "active", BoardID: 3},
+			},
+		},
+		{
+			name: "文字列形式（名前にカンマを含む）",
+			value: []interface{}{
+				"com.atlassian.greenhopper.service.sprint.Sprint@1a2b[id=5,rapidViewId=2,state=ACTIVE,name=Sprint 5, 後半,startDate=2025-02-03T09:00:00.000+09:00,endDate=2025-02-14T18:00:00.000+09:00,completeDate=<null>,sequence=5,goal=]",
+			},
+			want: []SprintInfo{
+				{ID: 5, Name: "Sprint 5, 後半", State: "active", BoardID: 2,
+					StartDate: "2025-02-03T09:00:00.000+09:00", EndDate: "2025-02-14T18:00:00.000+09:00"},
+			},
+		},
+		{
+			name:  "配列以外",
+			value: "Sprint 1",
+			want:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseSprintField(tt.value)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSprintField() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/andygrunwald/go-jira/v2/onpremise"
+)
+
+// Jira Data Center / Server 向けの処理
+//
+// Data CenterのREST API v2はCloudのv2と同じJSON形式のため、onpremiseクライアントで取得した
+// レスポンスをcloudパッケージの型に直接デコードする。これによりIssueData・MarkdownWriter・convertは
+// デプロイ種別によらず共通になる。
+// Cloud専用のAPIは使用せず、以下で代替する
+//   - JQL検索: /rest/api/3/search/jql → /rest/api/2/search（startAtによるページング）
+//   - 変更履歴: /rest/api/3/issue/{key}/changelog → 課題取得時のexpand=changelog（Data Centerでは全件返る）
+//   - 開発情報: jsw2/graphql は使用できないため /rest/dev-status/1.0 のみ
+
+// newDataCenterClient はData Center / Server用のJIRAクライアントを作成する
+// 認証はjira.authに従う（デフォルトはパーソナルアクセストークンのBearer認証）
+func newDataCenterClient(ctx context.Context, config *JIRAConfig, auth Authenticator, authClient *http.Client, httpClient *http.Client) (*JIRAClient, error) {
+	client, err := onpremise.NewClient(config.URL, authClient)
+	if err != nil {
+		return nil, fmt.Errorf("JIRAクライアントの作成に失敗しました: %w", err)
+	}
+
+	return &JIRAClient{
+		onpremise:  client,
+		ctx:        ctx,
+		httpClient: httpClient,
+		baseURL:    config.URL,
+		siteURL:    config.URL,
+		email:      config.Email,
+		apiToken:   config.APIToken,
+		auth:       auth,
+	}, nil
+}
+
+// isDataCenter はData Center / Serverモードのクライアントかどうかを返す
+func (jc *JIRAClient) isDataCenter() bool {
+	return jc.onpremise != nil
+}
+
+// getOnPremise はonpremiseクライアントでGETリクエストを送信し、レスポンスをvにデコードする
+// apiEndpointはベースURLからの相対パス（例: rest/api/2/issue/KEY-1）
+func (jc *JIRAClient) getOnPremise(apiEndpoint string, v interface{}) (*onpremise.Response, error) {
+	req, err := jc.onpremise.NewRequest(jc.ctx, http.MethodGet, apiEndpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %w", err)
+	}
+
+	slog.Debug("APIリクエスト", "method", "GET", "url", req.URL.String())
+
+	resp, err := jc.onpremise.Do(req, v)
+	if err != nil {
+		return resp, onpremise.NewJiraError(resp, err)
+	}
+	return resp, nil
+}
+
+// getIssueDataCenter は課題を取得する（Data Center）
+// 変更履歴はData Centerでは切り詰められないため、課題と同時に取得する
+func (jc *JIRAClient) getIssueDataCenter(issueKey string) (*cloud.Issue, error) {
+	var issue cloud.Issue
+	apiEndpoint := fmt.Sprintf("rest/api/2/issue/%s?expand=%s", url.PathEscape(issueKey), url.QueryEscape("renderedFields,changelog"))
+	if _, err := jc.getOnPremise(apiEndpoint, &issue); err != nil {
+		slog.Error("課題取得エラー",
+			"issueKey", issueKey,
+			"error", err)
+		return nil, fmt.Errorf("課題 %s の取得に失敗しました: %w", issueKey, err)
+	}
+
+	slog.Info("課題取得成功",
+		"issueKey", issue.Key,
+		"deployment", "datacenter")
+
+	return &issue, nil
+}
+
+// getChangelogDataCenter は課題の変更履歴をすべて取得する（Data Center）
+// Data Centerには変更履歴のページングAPIがないが、expand=changelogで全件が返る
+func (jc *JIRAClient) getChangelogDataCenter(issueKey string) ([]cloud.ChangelogHistory, error) {
+	var issue cloud.Issue
+	apiEndpoint := fmt.Sprintf("rest/api/2/issue/%s?fields=summary&expand=changelog", url.PathEscape(issueKey))
+	if _, err := jc.getOnPremise(apiEndpoint, &issue); err != nil {
+		return nil, fmt.Errorf("課題 %s の変更履歴の取得に失敗しました: %w", issueKey, err)
+	}
+	if issue.Changelog == nil {
+		return []cloud.ChangelogHistory{}, nil
+	}
+	return issue.Changelog.Histories, nil
+}
+
+// SearchPage は /rest/api/2/search（Data Center）のレスポンス構造体（1ページ分）
+type SearchPage struct {
+	StartAt    int           `json:"startAt"`
+	MaxResults int           `json:"maxResults"`
+	Total      int           `json:"total"`
+	Issues     []cloud.Issue `json:"issues"`
+}
+
+// searchJQLPagesDataCenter はJQL検索をstartAtでページングしながら実行し、ページごとの課題をhandleに渡す（Data Center）
+// opts.apiPathは使用せず、常に /rest/api/2/search を使用する
+func (jc *JIRAClient) searchJQLPagesDataCenter(jql string, opts jqlSearchOptions, handle func(issues []cloud.Issue) error) error {
+	startAt := 0
+	totalIssues := 0
+
+	for page := 0; opts.maxPages <= 0 || page < opts.maxPages; page++ {
+		apiEndpoint := fmt.Sprintf("rest/api/2/search?jql=%s&startAt=%d&maxResults=%d&fields=%s",
+			url.QueryEscape(jql), startAt, opts.maxResults, url.QueryEscape(opts.fields))
+		if opts.expand != "" {
+			apiEndpoint += fmt.Sprintf("&expand=%s", url.QueryEscape(opts.expand))
+		}
+
+		var searchResp SearchPage
+		if _, err := jc.getOnPremise(apiEndpoint, &searchResp); err != nil {
+			return fmt.Errorf("JQL検索に失敗しました: %w", err)
+		}
+
+		totalIssues += len(searchResp.Issues)
+		slog.Info("JQL検索レスポンス",
+			"count", len(searchResp.Issues),
+			"startAt", searchResp.StartAt,
+			"total", searchResp.Total,
+			"totalIssues", totalIssues)
+
+		if err := handle(searchResp.Issues); err != nil {
+			return err
+		}
+
+		startAt += len(searchResp.Issues)
+		if len(searchResp.Issues) == 0 || startAt >= searchResp.Total {
+			break
+		}
+	}
+
+	return nil
+}
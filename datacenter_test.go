@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// newTestDataCenterServer はData CenterのREST API v2を模したテスト用サーバーを作成する
+// Bearer認証以外のリクエストには401を返し、Cloud専用のAPIには404を返す
+func newTestDataCenterServer(t *testing.T, issueCount int) *httptest.Server {
+	t.Helper()
+
+	newIssue := func(i int) cloud.Issue {
+		return cloud.Issue{
+			Key: fmt.Sprintf("DC-%d", i),
+			Fields: &cloud.IssueFields{
+				Summary: fmt.Sprintf("課題 %d", i),
+				Project: cloud.Project{Key: "DC"},
+			},
+		}
+	}
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test-pat" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case "/rest/api/2/search":
+			var startAt, maxResults int
+			fmt.Sscanf(r.URL.Query().Get("startAt"), "%d", &startAt)
+			fmt.Sscanf(r.URL.Query().Get("maxResults"), "%d", &maxResults)
+			page := SearchPage{StartAt: startAt, MaxResults: maxResults, Total: issueCount, Issues: []cloud.Issue{}}
+			for i := startAt; i < min(startAt+maxResults, issueCount); i++ {
+				page.Issues = append(page.Issues, newIssue(i+1))
+			}
+			json.NewEncoder(w).Encode(page)
+		case "/rest/api/2/issue/DC-1":
+			issue := newIssue(1)
+			if r.URL.Query().Get("expand") != "" {
+				// Data Centerではexpand=changelogで変更履歴が全件返る
+				issue.Changelog = &cloud.Changelog{}
+				for i := 0; i < 150; i++ {
+					issue.Changelog.Histories = append(issue.Changelog.Histories, cloud.ChangelogHistory{Id: fmt.Sprintf("%d", i)})
+				}
+			}
+			json.NewEncoder(w).Encode(issue)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// TestDataCenterClient はData Centerモードのクライアントがcloud型で結果を返すことを確認する
+func TestDataCenterClient(t *testing.T) {
+	server := newTestDataCenterServer(t, 120)
+	defer server.Close()
+
+	client, err := NewJIRAClient(context.Background(), &JIRAConfig{
+		URL:        server.URL,
+		APIToken:   "test-pat",
+		Deployment: "datacenter",
+		Retry:      RetryConfig{MaxRetries: -1},
+	})
+	if err != nil {
+		t.Fatalf("クライアントの作成に失敗: %v", err)
+	}
+
+	t.Run("課題の取得", func(t *testing.T) {
+		issue, err := client.GetIssue("DC-1")
+		if err != nil {
+			t.Fatalf("予期しないエラー: %v", err)
+		}
+		if issue.Key != "DC-1" || issue.Fields.Summary != "課題 1" {
+			t.Errorf("課題 = %s %q, want DC-1 %q", issue.Key, issue.Fields.Summary, "課題 1")
+		}
+		if issue.Changelog == nil || len(issue.Changelog.Histories) != 150 {
+			t.Errorf("変更履歴が課題と同時に取得されていません")
+		}
+	})
+
+	t.Run("変更履歴の取得", func(t *testing.T) {
+		histories, err := client.GetChangelog("DC-1")
+		if err != nil {
+			t.Fatalf("予期しないエラー: %v", err)
+		}
+		if len(histories) != 150 {
+			t.Errorf("変更履歴数 = %d, want 150", len(histories))
+		}
+	})
+
+	t.Run("startAtによるJQL検索のページング", func(t *testing.T) {
+		keys, err := client.GetAllIssuesByJQL("project = DC", 50)
+		if err != nil {
+			t.Fatalf("予期しないエラー: %v", err)
+		}
+		if len(keys) != 120 {
+			t.Errorf("課題数 = %d, want 120", len(keys))
+		}
+		if keys[0] != "DC-1" || keys[len(keys)-1] != "DC-120" {
+			t.Errorf("課題キー = %s ... %s, want DC-1 ... DC-120", keys[0], keys[len(keys)-1])
+		}
+	})
+
+	t.Run("GraphQL APIは使用できない", func(t *testing.T) {
+		if _, err := client.GetDevStatusGraphQL("10001"); err == nil {
+			t.Error("エラーが期待されましたが、nilが返されました")
+		}
+	})
+}
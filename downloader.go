@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,19 +14,22 @@ import (
 
 // Downloader は添付ファイルのダウンロードを管理する
 type Downloader struct {
+	ctx            context.Context
 	client         *http.Client
 	attachmentsDir string
-	email          string
-	apiToken       string
+	auth           Authenticator
 }
 
 // NewDownloader は新しいDownloaderを作成する
-func NewDownloader(attachmentsDir, email, apiToken string) *Downloader {
+// レート制限や一時エラーの場合はretryの設定に従ってリトライする
+// 認証にはJIRAClientと同じAuthenticatorを使用する
+// ctxをキャンセルすると処理中のダウンロードを中断する
+func NewDownloader(ctx context.Context, attachmentsDir string, auth Authenticator, retry RetryConfig) *Downloader {
 	return &Downloader{
-		client:         &http.Client{},
+		ctx:            ctx,
+		client:         &http.Client{Transport: NewRetryTransport(http.DefaultTransport, retry)},
 		attachmentsDir: attachmentsDir,
-		email:          email,
-		apiToken:       apiToken,
+		auth:           auth,
 	}
 }
 
@@ -53,6 +57,28 @@ func (d *Downloader) DownloadAttachments(issue *cloud.Issue) ([]string, error) {
 	return downloadedFiles, nil
 }
 
+// DownloadIcon はJiraのアイコン（課題タイプ・優先度・ステータスのiconUrl）を添付ファイルディレクトリのiconsにダウンロードする
+// 拡張子はURLまたはContent-Typeから決め、保存したファイル名（baseName + 拡張子）を返す
+// アイコンはJira以外のホスト（CDN等）にある場合があるため、authorizeがfalseの場合は認証情報を付けずに取得する
+func (d *Downloader) DownloadIcon(iconURL, baseName string, authorize bool) (string, error) {
+	dir := filepath.Join(d.attachmentsDir, iconsDirName)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("アイコンのディレクトリの作成に失敗しました: %w", err)
+	}
+
+	resp, err := d.get(iconURL, authorize)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	filename := baseName + iconExtension(iconURL, resp.Header.Get("Content-Type"))
+	if err := saveFile(filepath.Join(dir, filename), resp.Body); err != nil {
+		return "", err
+	}
+	return filename, nil
+}
+
 // downloadFile は単一の添付ファイルをダウンロードする
 func (d *Downloader) downloadFile(attachment *cloud.Attachment, issueKey string) (string, error) {
 	// ファイル名の衝突を避けるため、課題キーをプレフィックスとして追加
@@ -65,38 +91,70 @@ func (d *Downloader) downloadFile(attachment *cloud.Attachment, issueKey string)
 		return filename, nil
 	}
 
+	resp, err := d.get(attachment.Content, true)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if err := saveFile(filepath, resp.Body); err != nil {
+		return "", err
+	}
+
+	return filename, nil
+}
+
+// get はURLを取得する（authorizeの場合は認証ヘッダーを付ける、ステータスコードが200以外の場合はエラー）
+func (d *Downloader) get(rawURL string, authorize bool) (*http.Response, error) {
 	// HTTPリクエストの作成
-	req, err := http.NewRequest("GET", attachment.Content, nil)
+	req, err := http.NewRequestWithContext(d.ctx, "GET", rawURL, nil)
 	if err != nil {
-		return "", fmt.Errorf("HTTPリクエストの作成に失敗しました: %w", err)
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %w", err)
 	}
 
-	// Basic認証ヘッダーの設定
-	req.SetBasicAuth(d.email, d.apiToken)
+	// 認証ヘッダーの設定
+	if authorize {
+		if err := d.auth.Authorize(req); err != nil {
+			return nil, fmt.Errorf("認証情報の設定に失敗しました: %w", err)
+		}
+	}
 
 	// ファイルのダウンロード
 	resp, err := d.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("HTTPリクエストに失敗しました: %w", err)
+		return nil, fmt.Errorf("HTTPリクエストに失敗しました: %w", err)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ダウンロードに失敗しました。ステータスコード: %d", resp.StatusCode)
+		resp.Body.Close()
+		return nil, fmt.Errorf("ダウンロードに失敗しました。ステータスコード: %d", resp.StatusCode)
 	}
+	return resp, nil
+}
 
-	// ファイルの保存
-	outFile, err := os.Create(filepath)
+// saveFile はファイルの内容を保存する
+// 一時ファイルに書き込んでからリネームする（中断時に不完全なファイルが残って次回スキップされないように）
+func saveFile(path string, body io.Reader) error {
+	partPath := path + ".part"
+	outFile, err := os.Create(partPath)
 	if err != nil {
-		return "", fmt.Errorf("ファイルの作成に失敗しました: %w", err)
+		return fmt.Errorf("ファイルの作成に失敗しました: %w", err)
 	}
-	defer outFile.Close()
 
-	if _, err := io.Copy(outFile, resp.Body); err != nil {
-		return "", fmt.Errorf("ファイルの書き込みに失敗しました: %w", err)
+	if _, err := io.Copy(outFile, body); err != nil {
+		outFile.Close()
+		os.Remove(partPath)
+		return fmt.Errorf("ファイルの書き込みに失敗しました: %w", err)
 	}
-
-	return filename, nil
+	if err := outFile.Close(); err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("ファイルの書き込みに失敗しました: %w", err)
+	}
+	if err := os.Rename(partPath, path); err != nil {
+		os.Remove(partPath)
+		return fmt.Errorf("ファイルの保存に失敗しました: %w", err)
+	}
+	return nil
 }
 
 // sanitizeFilename はファイル名を安全な形式にサニタイズする
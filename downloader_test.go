@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -128,7 +129,7 @@ func TestDownloadAttachments(t *testing.T) {
 			}
 
 			// Downloaderの作成
-			downloader := NewDownloader(tmpDir, "test@example.com", "test-token")
+			downloader := NewDownloader(context.Background(), tmpDir, &BasicAuthenticator{Email: "test@example.com", APIToken: "test-token"}, RetryConfig{})
 
 			// ダウンロードの実行
 			files, err := downloader.DownloadAttachments(tt.issue)
@@ -165,7 +166,7 @@ func TestDownloadAttachments(t *testing.T) {
 
 // TestSanitizeFilename はsanitizeFilenameメソッドのテスト
 func TestSanitizeFilename(t *testing.T) {
-	downloader := NewDownloader("", "", "")
+	downloader := NewDownloader(context.Background(), "", &BasicAuthenticator{}, RetryConfig{})
 
 	tests := []struct {
 		name     string
@@ -317,7 +318,7 @@ func TestDownloadAttachmentsDirectoryCreation(t *testing.T) {
 	defer server.Close()
 
 	// Downloaderの作成
-	downloader := NewDownloader(attachmentsDir, "test@example.com", "test-token")
+	downloader := NewDownloader(context.Background(), attachmentsDir, &BasicAuthenticator{Email: "test@example.com", APIToken: "test-token"}, RetryConfig{})
 
 	// テスト用のissue
 	issue := &cloud.Issue{
@@ -365,7 +366,7 @@ func TestDownloadAttachmentsFileExists(t *testing.T) {
 	defer server.Close()
 
 	// Downloaderの作成
-	downloader := NewDownloader(tmpDir, "test@example.com", "test-token")
+	downloader := NewDownloader(context.Background(), tmpDir, &BasicAuthenticator{Email: "test@example.com", APIToken: "test-token"}, RetryConfig{})
 
 	// テスト用のissue
 	issue := &cloud.Issue{
@@ -0,0 +1,727 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// IssueExporter は課題1件ごとの取得・変換・出力処理を管理する（search/projectコマンド共通）
+//
+// 処理は「取得」と「書き込み」の2段階に分かれる。
+// 取得（fetchIssue）はAPIアクセスのみを行い、複数のワーカーから並行に呼び出せる。
+// 書き込み（writeIssue）は単一のゴルーチンから課題キーの順に呼び出されるため、
+// userMappingはこの段階でのみ更新・参照され、ワーカー数によらず同じ出力になる。
+//
+// ExportIssues・ExportIssuesByJQLに渡すctxがキャンセルされると新しい課題の取得を止め、
+// 取得中の課題の出力を終えてから戻る（APIリクエスト自体はJIRAClientのコンテキストで中断する）。
+type IssueExporter struct {
+	config         *Config
+	jiraClient     *JIRAClient
+	downloader     *Downloader
+	mdWriter       *MarkdownWriter
+	fields         []cloud.Field
+	fieldNameCache FieldNameCache
+	userMapping    UserMapping // 書き込み段階でのみ使用する
+	checkpoint     *Checkpoint // 出力が完了した課題キーの記録（nilの場合は記録しない）
+
+	// mu は以下のキャッシュを保護する
+	mu sync.Mutex
+	// 親課題情報のキャッシュ
+	parentInfoCache map[string]*ParentIssueInfo
+	// 子課題キャッシュ
+	childIssuesCache map[string][]ChildIssueInfo
+	// プロジェクトごとの_index.md生成済みフラグ（重複防止）
+	generatedProjects map[string]bool
+}
+
+// exportedIssue は取得段階で集めた課題1件分のデータ
+type exportedIssue struct {
+	issue           *cloud.Issue
+	attachmentFiles []string
+	devStatus       *DevStatusDetail
+	parentInfo      *ParentIssueInfo
+	childIssues     []ChildIssueInfo
+	remoteLinks     []cloud.RemoteLink
+	comments        []IssueComment
+	worklogs        []cloud.WorklogRecord
+	adf             *IssueADF
+}
+
+// NewIssueExporter は新しいIssueExporterを作成する
+// フィールドリストの取得に失敗した場合は警告を出してフィールド名なしで継続する
+// [display.icons] で "jira" を指定したアイコンはここでダウンロードする
+// 添付ファイルのダウンロードとMarkdownの書き込みはJIRAクライアントと同じコンテキストで中断する
+func NewIssueExporter(config *Config, jiraClient *JIRAClient) *IssueExporter {
+	// フィールドリストを取得してキャッシュを作成
+	fields, err := jiraClient.GetFieldList()
+	if err != nil {
+		fmt.Printf("警告: フィールドリストの取得に失敗しました: %v\n", err)
+		fields = nil
+	}
+
+	// ユーザーマッピングの初期化
+	userMapping := make(UserMapping)
+
+	ex := &IssueExporter{
+		config:            config,
+		jiraClient:        jiraClient,
+		downloader:        NewDownloader(jiraClient.ctx, config.Output.AttachmentsDir, jiraClient.Authenticator(), config.JIRA.Retry),
+		mdWriter:          NewMarkdownWriter(jiraClient.ctx, config.Output.MarkdownDir, config.Output.AttachmentsDir, userMapping, config),
+		fields:            fields,
+		fieldNameCache:    BuildFieldNameCache(fields),
+		userMapping:       userMapping,
+		parentInfoCache:   make(map[string]*ParentIssueInfo),
+		childIssuesCache:  make(map[string][]ChildIssueInfo),
+		generatedProjects: make(map[string]bool),
+	}
+	ex.mdWriter.DownloadJiraIcons(jiraClient, ex.downloader)
+	return ex
+}
+
+// UseCheckpoint は出力が完了した課題をチェックポイントに記録し、記録済みの課題をスキップするようにする
+func (ex *IssueExporter) UseCheckpoint(checkpoint *Checkpoint) {
+	ex.checkpoint = checkpoint
+}
+
+// WriteProjectIndex はプロジェクトの_index.mdを生成し、生成済みとして記録する
+func (ex *IssueExporter) WriteProjectIndex(project *cloud.Project) error {
+	ex.mu.Lock()
+	ex.generatedProjects[project.Key] = true
+	ex.mu.Unlock()
+	if err := ex.mdWriter.WriteProjectIndex(project); err != nil {
+		return err
+	}
+	ex.writeSprintPages(project.Key)
+	ex.writeReleaseNotes(project.Key)
+	return nil
+}
+
+// ExportIssue は課題を取得し、添付ファイル・Markdown・JSONを出力する
+// 付随情報（開発情報、親子課題、リモートリンク等）の取得失敗は警告にとどめて継続する
+func (ex *IssueExporter) ExportIssue(issueKey string) error {
+	exported, err := ex.fetchIssue(exportJob{key: issueKey})
+	if err != nil {
+		return err
+	}
+	ex.writeIssue(exported)
+	return nil
+}
+
+// exportJob は出力対象の課題1件を表す
+type exportJob struct {
+	key   string
+	issue *cloud.Issue // 検索で取得済みの課題（nilの場合はGetIssueで取得する）
+}
+
+// ExportIssues は課題キーのリストを処理し、取得に失敗した課題キーを返す
+// workersが2以上の場合は取得段階を並行に実行するが、書き込みは課題キーの順に行うため
+// 出力内容と進捗表示の順序はワーカー数によらず同じになる
+// チェックポイントに記録済みの課題はスキップする
+func (ex *IssueExporter) ExportIssues(ctx context.Context, issueKeys []string, workers int) []string {
+	pendingKeys := make([]string, 0, len(issueKeys))
+	for _, issueKey := range issueKeys {
+		if !ex.checkpoint.Completed(issueKey) {
+			pendingKeys = append(pendingKeys, issueKey)
+		}
+	}
+	if skipped := len(issueKeys) - len(pendingKeys); skipped > 0 {
+		fmt.Printf("チェックポイントにより %d 件の課題をスキップします\n", skipped)
+	}
+
+	jobs := make(chan exportJob)
+	go func() {
+		defer close(jobs)
+		for _, issueKey := range pendingKeys {
+			select {
+			case jobs <- exportJob{key: issueKey}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ex.exportJobs(ctx, jobs, len(pendingKeys), workers)
+}
+
+// ExportIssuesByJQL はJQL検索で全フィールドを含む課題をページ単位で取得し、そのまま出力する
+// 課題ごとのGetIssue呼び出しが不要なため、ExportIssuesよりAPI呼び出し回数が少ない
+// 検索が途中で失敗した場合は、それまでに取得した課題を出力したうえでエラーを返す
+// チェックポイントに記録済みの課題はスキップする
+func (ex *IssueExporter) ExportIssuesByJQL(ctx context.Context, jql string, pageSize int, maxPages int, workers int) ([]string, error) {
+	jobs := make(chan exportJob)
+	var searchErr error
+	go func() {
+		defer close(jobs)
+		skipped := 0
+		searchErr = ex.jiraClient.SearchIssuesJQL(jql, pageSize, maxPages, func(issues []cloud.Issue) error {
+			for i := range issues {
+				issue := &issues[i]
+				ex.cacheParentInfo(issue)
+				if ex.checkpoint.Completed(issue.Key) {
+					skipped++
+					continue
+				}
+				select {
+				case jobs <- exportJob{key: issue.Key, issue: issue}:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return nil
+		})
+		if skipped > 0 {
+			fmt.Printf("チェックポイントにより %d 件の課題をスキップしました\n", skipped)
+		}
+		// 中断による検索の打ち切りはエラーとして扱わない
+		if ctx.Err() != nil && errors.Is(searchErr, ctx.Err()) {
+			searchErr = nil
+		}
+	}()
+
+	failedKeys := ex.exportJobs(ctx, jobs, 0, workers)
+	// jobsがクローズされた時点で検索ゴルーチンは終了している
+	return failedKeys, searchErr
+}
+
+// exportJobs はジョブを並行に取得し、受け取った順に書き込む
+// totalが0の場合（件数が事前に分からない場合）は進捗表示に総数を含めない
+// ctxがキャンセルされた場合は新しいジョブの取得を止め、取得中のジョブを出力してから戻る
+// （jobsは送信側がctxのキャンセルを受けてクローズするまで読み捨てる）
+func (ex *IssueExporter) exportJobs(ctx context.Context, jobs <-chan exportJob, total int, workers int) []string {
+	if workers < 1 {
+		workers = 1
+	}
+
+	type fetchResult struct {
+		exported *exportedIssue
+		err      error
+	}
+	type pendingJob struct {
+		key      string
+		resultCh chan fetchResult
+	}
+
+	// ジョブの順に結果を受け取るためのチャネル列
+	// バッファサイズで先行取得する件数を制限する
+	ordered := make(chan pendingJob, workers*2)
+	go func() {
+		defer close(ordered)
+		sem := make(chan struct{}, workers)
+		for job := range jobs {
+			// 取得枠の空きを待つ（中断された場合は新しいジョブを開始しない）
+			select {
+			case sem <- struct{}{}:
+			case <-ctx.Done():
+			}
+			if ctx.Err() != nil {
+				for range jobs {
+				}
+				return
+			}
+			pending := pendingJob{key: job.key, resultCh: make(chan fetchResult, 1)}
+			ordered <- pending
+			go func(job exportJob) {
+				defer func() { <-sem }()
+				exported, err := ex.fetchIssue(job)
+				pending.resultCh <- fetchResult{exported: exported, err: err}
+			}(job)
+		}
+	}()
+
+	var failedKeys []string
+	i := 0
+	for pending := range ordered {
+		i++
+		if total > 0 {
+			fmt.Printf("[%d/%d] 処理中: %s\n", i, total, pending.key)
+		} else {
+			fmt.Printf("[%d] 処理中: %s\n", i, pending.key)
+		}
+
+		result := <-pending.resultCh
+		if result.err != nil {
+			fmt.Printf("警告: 課題 %s の取得に失敗しました: %v\n", pending.key, result.err)
+			failedKeys = append(failedKeys, pending.key)
+			continue
+		}
+		if err := ex.writeIssue(result.exported); err != nil {
+			continue
+		}
+		if err := ex.checkpoint.Record(pending.key); err != nil {
+			slog.Warn("チェックポイントの記録に失敗", "issueKey", pending.key, "error", err)
+		}
+	}
+	if ctx.Err() != nil {
+		fmt.Printf("\n中断しました（%d 件を処理）\n", i)
+	}
+	return failedKeys
+}
+
+// fetchIssue は課題とその付随情報をAPIから取得する（複数のワーカーから並行に呼び出し可能）
+func (ex *IssueExporter) fetchIssue(job exportJob) (*exportedIssue, error) {
+	issue := job.issue
+	if issue == nil {
+		// 課題の詳細情報を取得（descriptionを含む完全な情報）
+		var err error
+		issue, err = ex.jiraClient.GetIssue(job.key)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 変更履歴の取得（課題に含まれる変更履歴は切り詰められているため、全件で置き換える）
+	ex.fetchChangelog(issue)
+
+	// 添付ファイルのダウンロード
+	attachmentFiles, err := ex.downloader.DownloadAttachments(issue)
+	if err != nil {
+		fmt.Printf("  警告: 添付ファイルのダウンロードに失敗しました（課題: %s）: %v\n", issue.Key, err)
+		attachmentFiles = []string{}
+	}
+
+	exported := &exportedIssue{
+		issue:           issue,
+		attachmentFiles: attachmentFiles,
+		devStatus:       ex.fetchDevStatus(issue),
+		parentInfo:      ex.fetchParentInfo(issue),
+		childIssues:     ex.fetchChildIssues(issue),
+		comments:        ex.fetchComments(issue),
+		worklogs:        ex.fetchWorklogs(issue),
+	}
+
+	// 説明・コメント本文のADF（設定で有効な場合のみ）
+	exported.adf = ex.fetchADF(issue)
+
+	// リモートリンク（Confluenceコンテンツなど）の取得
+	remoteLinksResult, err := ex.jiraClient.GetRemoteLinks(issue.Key)
+	if err != nil {
+		slog.Debug("リモートリンク取得エラー",
+			"issueKey", issue.Key,
+			"error", err)
+		exported.remoteLinks = []cloud.RemoteLink{}
+	} else {
+		exported.remoteLinks = remoteLinksResult
+	}
+
+	return exported, nil
+}
+
+// writeIssue は取得済みの課題をJSON・Markdownとして出力する（課題キーの順に単一のゴルーチンから呼び出す）
+// Markdownの出力に失敗した場合は警告を出してエラーを返す（チェックポイントには記録しない）
+func (ex *IssueExporter) writeIssue(exported *exportedIssue) error {
+	issue := exported.issue
+
+	fmt.Printf("  取得完了: %s - %s\n", issue.Key, issue.Fields.Summary)
+
+	// プロジェクトの_index.md生成（初回のみ）
+	ex.ensureProjectIndex(issue.Fields.Project.Key)
+
+	// ユーザーマッピングに追加
+	BuildUserMappingFromIssue(issue, ex.userMapping)
+
+	// デバッグ用: 取得した課題データをJSON形式でログ出力
+	if issueJSON, err := json.MarshalIndent(issue, "", "  "); err == nil {
+		slog.Debug("JIRA課題データ (JSON)",
+			"issueKey", issue.Key,
+			"json", string(issueJSON))
+	} else {
+		slog.Warn("JSON変換に失敗しました", "issueKey", issue.Key, "error", err)
+	}
+
+	issueData := &IssueData{
+		Issue:       issue,
+		DevStatus:   exported.devStatus,
+		ParentInfo:  exported.parentInfo,
+		ChildIssues: exported.childIssues,
+		RemoteLinks: exported.remoteLinks,
+		Comments:    exported.comments,
+		Worklogs:    exported.worklogs,
+		ADF:         exported.adf,
+		Fields:      ex.fields,
+		SavedAt:     time.Now().Format(time.RFC3339),
+	}
+
+	// JSON保存（設定されている場合）
+	if ex.config.Output.JSONDir != "" {
+		jsonSaver := NewJSONSaver(ex.config.Output.JSONDir)
+		jsonPath, err := jsonSaver.SaveIssue(issueData)
+		if err != nil {
+			slog.Warn("JSON保存エラー", "issueKey", issue.Key, "error", err)
+		} else {
+			fmt.Printf("  JSON出力: %s\n", jsonPath)
+		}
+	}
+
+	// Markdown出力
+	if err := ex.mdWriter.WriteIssue(issueData, exported.attachmentFiles, ex.fieldNameCache); err != nil {
+		fmt.Printf("  警告: Markdownファイルの出力に失敗しました: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// ensureProjectIndex はプロジェクトの_index.mdが未生成の場合に生成する
+func (ex *IssueExporter) ensureProjectIndex(projectKey string) {
+	ex.mu.Lock()
+	if ex.generatedProjects[projectKey] {
+		ex.mu.Unlock()
+		return
+	}
+	ex.generatedProjects[projectKey] = true
+	ex.mu.Unlock()
+
+	project, err := ex.jiraClient.GetProject(projectKey)
+	if err != nil {
+		slog.Warn("プロジェクト取得に失敗",
+			"project", projectKey,
+			"error", err)
+		return
+	}
+	if err := ex.mdWriter.WriteProjectIndex(project); err != nil {
+		slog.Warn("_index.md生成に失敗",
+			"project", projectKey,
+			"error", err)
+		return
+	}
+	fmt.Printf("_index.mdを生成しました: %s\n", projectKey)
+	ex.writeSprintPages(projectKey)
+	ex.writeReleaseNotes(projectKey)
+}
+
+// writeReleaseNotes はプロジェクトのバージョンと修正バージョンごとの課題を取得し、リリースノートを生成する
+// release_notes.enabledが無効の場合は何もしない。取得に失敗した場合は警告を出して継続する
+func (ex *IssueExporter) writeReleaseNotes(projectKey string) {
+	if !ex.config.ReleaseNotes.Enabled {
+		return
+	}
+
+	versions, err := ex.jiraClient.GetProjectVersions(projectKey)
+	if err != nil {
+		fmt.Printf("警告: バージョンの取得に失敗しました（プロジェクト: %s）: %v\n", projectKey, err)
+		return
+	}
+
+	notes := make([]ReleaseNote, 0, len(versions))
+	for _, version := range versions {
+		issues, err := ex.jiraClient.GetVersionIssueSummaries(projectKey, version.ID)
+		if err != nil {
+			fmt.Printf("警告: バージョンの課題の取得に失敗しました（バージョン: %s）: %v\n", version.Name, err)
+			continue
+		}
+		notes = append(notes, ReleaseNote{Version: version, Issues: issues})
+	}
+
+	if err := ex.mdWriter.WriteReleaseNotes(projectKey, notes); err != nil {
+		slog.Warn("リリースノート生成に失敗",
+			"project", projectKey,
+			"error", err)
+		return
+	}
+	fmt.Printf("リリースノートを生成しました: %s（%d件）\n", projectKey, len(notes))
+}
+
+// writeSprintPages はプロジェクトのスクラムボードからスプリントと課題を取得し、スプリントページを生成する
+// agile.enabledが無効の場合は何もしない。取得に失敗した場合は警告を出して継続する
+func (ex *IssueExporter) writeSprintPages(projectKey string) {
+	if !ex.config.Agile.Enabled {
+		return
+	}
+
+	boards, err := ex.jiraClient.GetBoards(projectKey)
+	if err != nil {
+		fmt.Printf("警告: ボードの取得に失敗しました（プロジェクト: %s）: %v\n", projectKey, err)
+		return
+	}
+
+	// 複数のボードに表示されるスプリントは最初のボードでのみ出力する
+	seen := make(map[int]bool)
+	var reports []SprintReport
+	for _, board := range boards {
+		sprints, err := ex.jiraClient.GetBoardSprints(board.ID)
+		if err != nil {
+			fmt.Printf("警告: スプリントの取得に失敗しました（ボード: %s）: %v\n", board.Name, err)
+			continue
+		}
+		for _, sprint := range sprints {
+			if seen[sprint.ID] {
+				continue
+			}
+			seen[sprint.ID] = true
+
+			issues, err := ex.jiraClient.GetSprintIssues(sprint.ID)
+			if err != nil {
+				fmt.Printf("警告: スプリントの課題の取得に失敗しました（スプリント: %s）: %v\n", sprint.Name, err)
+				continue
+			}
+			reports = append(reports, buildSprintReport(board.Name, sprint, issues))
+		}
+	}
+
+	if err := ex.mdWriter.WriteSprintPages(projectKey, reports); err != nil {
+		slog.Warn("スプリントページ生成に失敗",
+			"project", projectKey,
+			"error", err)
+		return
+	}
+	fmt.Printf("スプリントページを生成しました: %s（%d件）\n", projectKey, len(reports))
+}
+
+// buildSprintReport はスプリントの課題一覧からスプリントページの出力内容を作成する
+// 完了済みのスプリントでは、スプリントの完了日より後に解決された課題を完了に含めない
+// （次のスプリントに持ち越されて完了した課題）
+func buildSprintReport(boardName string, sprint SprintInfo, issues []cloud.Issue) SprintReport {
+	var completeDate time.Time
+	if sprint.State == "closed" && sprint.CompleteDate != "" {
+		completeDate, _ = time.Parse(time.RFC3339, sprint.CompleteDate)
+	}
+
+	report := SprintReport{Sprint: sprint, BoardName: boardName}
+	for _, issue := range issues {
+		if issue.Fields == nil {
+			continue
+		}
+		info := SprintIssueInfo{
+			Key:     issue.Key,
+			Project: issue.Fields.Project.Key,
+			Summary: issue.Fields.Summary,
+			Type:    issue.Fields.Type.Name,
+		}
+		if issue.Fields.Status != nil {
+			info.Status = issue.Fields.Status.Name
+			info.Completed = issue.Fields.Status.StatusCategory.Key == "done"
+		}
+		resolved := time.Time(issue.Fields.Resolutiondate)
+		if info.Completed && !completeDate.IsZero() && !resolved.IsZero() && resolved.After(completeDate) {
+			info.Completed = false
+		}
+		report.Issues = append(report.Issues, info)
+	}
+	return report
+}
+
+// fetchChangelog は変更履歴を全件取得し、課題の変更履歴を置き換える
+// 取得に失敗した場合は警告を出し、課題に含まれる変更履歴のまま継続する
+func (ex *IssueExporter) fetchChangelog(issue *cloud.Issue) {
+	histories, err := ex.jiraClient.GetChangelog(issue.Key)
+	if err != nil {
+		fmt.Printf("  警告: 変更履歴の取得に失敗しました（課題: %s）: %v\n", issue.Key, err)
+		return
+	}
+	issue.Changelog = &cloud.Changelog{Histories: histories}
+}
+
+// fetchComments はコメントを全件取得する
+// 取得に失敗した場合は警告を出してnilを返す（課題に含まれるコメントで出力する）
+func (ex *IssueExporter) fetchComments(issue *cloud.Issue) []IssueComment {
+	comments, err := ex.jiraClient.GetComments(issue.Key)
+	if err != nil {
+		fmt.Printf("  警告: コメントの取得に失敗しました（課題: %s）: %v\n", issue.Key, err)
+		return nil
+	}
+	return comments
+}
+
+// fetchADF は説明・コメント本文・複数行テキストのカスタムフィールドをADF形式で取得する（display.adfが有効な場合のみ）
+// 取得に失敗した場合は警告を出してnilを返す（Wiki記法の値で出力する）
+func (ex *IssueExporter) fetchADF(issue *cloud.Issue) *IssueADF {
+	if !ex.config.Display.ADF {
+		return nil
+	}
+	adf, err := ex.jiraClient.GetIssueADF(issue, textareaFieldIDs(ex.fields))
+	if err != nil {
+		fmt.Printf("  警告: ADFの取得に失敗しました（課題: %s）: %v\n", issue.Key, err)
+		return nil
+	}
+	return adf
+}
+
+// fetchWorklogs は作業ログを全件取得する
+// 課題に含まれる作業ログが全件そろっている場合はAPIを呼び出さずにそれを使用する
+// 取得に失敗した場合は警告を出して課題に含まれる作業ログを返す
+func (ex *IssueExporter) fetchWorklogs(issue *cloud.Issue) []cloud.WorklogRecord {
+	embedded := issue.Fields.Worklog
+	if embedded != nil && len(embedded.Worklogs) >= embedded.Total {
+		return embedded.Worklogs
+	}
+
+	worklogs, err := ex.jiraClient.GetWorklogs(issue.Key)
+	if err != nil {
+		fmt.Printf("  警告: 作業ログの取得に失敗しました（課題: %s）: %v\n", issue.Key, err)
+		if embedded != nil {
+			return embedded.Worklogs
+		}
+		return nil
+	}
+	return worklogs
+}
+
+// fetchDevStatus は開発情報の詳細を取得する（設定で有効な場合のみ）
+func (ex *IssueExporter) fetchDevStatus(issue *cloud.Issue) *DevStatusDetail {
+	if !ex.config.Development.Enabled || issue.ID == "" {
+		return nil
+	}
+
+	apiType := ex.config.Development.APIType
+	if apiType == "" {
+		apiType = "rest" // デフォルトはREST API
+	}
+
+	if apiType == "graphql" {
+		// GraphQL APIを使用
+		devStatus, err := ex.jiraClient.GetDevStatusGraphQL(issue.ID)
+		if err != nil {
+			slog.Debug("GraphQL API 開発情報取得失敗",
+				"issueKey", issue.Key,
+				"issueID", issue.ID,
+				"error", err)
+			slog.Warn("開発情報の詳細取得に失敗（スキップして継続）",
+				"issueKey", issue.Key,
+				"error", err)
+			return nil
+		}
+		return devStatus
+	}
+
+	// REST APIを使用
+	appType := ex.config.Development.ApplicationType
+	if appType == "" {
+		appType = "bitbucket" // デフォルト
+	}
+
+	devStatus, err := ex.jiraClient.GetDevStatusDetails(issue.ID, appType, "pullrequest")
+	if err != nil {
+		slog.Debug("REST API 開発情報取得失敗",
+			"issueKey", issue.Key,
+			"issueID", issue.ID,
+			"appType", appType,
+			"error", err)
+		slog.Warn("開発情報の詳細取得に失敗（スキップして継続）",
+			"issueKey", issue.Key,
+			"error", err)
+		return nil
+	}
+	return devStatus
+}
+
+// fetchParentInfo は親課題情報を取得する（キャッシュを使用）
+func (ex *IssueExporter) fetchParentInfo(issue *cloud.Issue) *ParentIssueInfo {
+	if issue.Fields.Parent == nil || issue.Fields.Parent.Key == "" {
+		return nil
+	}
+
+	parentKey := issue.Fields.Parent.Key
+	ex.mu.Lock()
+	cachedInfo, exists := ex.parentInfoCache[parentKey]
+	ex.mu.Unlock()
+	if exists {
+		return cachedInfo
+	}
+
+	parentIssue, err := ex.jiraClient.GetIssue(parentKey)
+	if err != nil {
+		fmt.Printf("  警告: 親課題 %s の取得に失敗しました: %v\n", parentKey, err)
+		return nil
+	}
+	return ex.cacheParentInfo(parentIssue)
+}
+
+// cacheParentInfo は取得済みの課題を親課題情報としてキャッシュする
+// 一括取得した課題を登録しておくことで、子課題の出力時に親課題を再取得せずに済む
+func (ex *IssueExporter) cacheParentInfo(issue *cloud.Issue) *ParentIssueInfo {
+	parentInfo := &ParentIssueInfo{
+		Key: issue.Key,
+	}
+	if issue.Fields != nil {
+		parentInfo.Type = issue.Fields.Type.Name
+	}
+	ex.mu.Lock()
+	ex.parentInfoCache[issue.Key] = parentInfo
+	ex.mu.Unlock()
+	return parentInfo
+}
+
+// fetchChildIssues は子課題を取得する（キャッシュ使用、すべての課題に対して実行）
+func (ex *IssueExporter) fetchChildIssues(issue *cloud.Issue) []ChildIssueInfo {
+	ex.mu.Lock()
+	cachedChildren, exists := ex.childIssuesCache[issue.Key]
+	ex.mu.Unlock()
+	if exists {
+		return cachedChildren
+	}
+
+	// 子課題は一覧表示に必要なフィールドのみを1回の検索で取得する
+	children, err := ex.jiraClient.GetChildIssueSummaries(issue.Key, ex.config.Display.RankFieldId)
+	if err != nil {
+		fmt.Printf("  警告: 子課題の取得に失敗しました（課題: %s）: %v\n", issue.Key, err)
+		return nil
+	}
+	if len(children) == 0 {
+		return nil
+	}
+
+	childIssues := childIssueInfos(children, ex.config.Display.RankFieldId)
+	ex.mu.Lock()
+	ex.childIssuesCache[issue.Key] = childIssues
+	ex.mu.Unlock()
+	return childIssues
+}
+
+// childIssueInfos は子課題の検索結果を子作業項目の一覧に変換する（サブタスクは除外し、Rankフィールドでソートする）
+func childIssueInfos(children []cloud.Issue, rankFieldID string) []ChildIssueInfo {
+	childIssues := make([]ChildIssueInfo, 0, len(children))
+	for _, childIssue := range children {
+		if childIssue.Fields == nil {
+			continue
+		}
+		// Sub-task課題タイプは除外
+		issueType := childIssue.Fields.Type.Name
+		if issueType == "Sub-task" || issueType == "Subtask" || issueType == "サブタスク" {
+			continue
+		}
+
+		// Rankフィールドを取得
+		rankValue := ""
+		if rank, exists := childIssue.Fields.Unknowns[rankFieldID]; exists {
+			if rankStr, ok := rank.(string); ok {
+				rankValue = rankStr
+			}
+		}
+		status := ""
+		if childIssue.Fields.Status != nil {
+			status = childIssue.Fields.Status.Name
+		}
+		childIssues = append(childIssues, ChildIssueInfo{
+			Key:     childIssue.Key,
+			Summary: childIssue.Fields.Summary,
+			Status:  status,
+			Type:    issueType,
+			Rank:    rankValue,
+		})
+	}
+
+	// 子課題をRankフィールドでソート
+	sortChildIssuesByRank(childIssues)
+	return childIssues
+}
+
+// sortChildIssuesByRank は子課題をRankフィールドの辞書順でソートする（Rankが空の課題は後ろに配置）
+func sortChildIssuesByRank(childIssues []ChildIssueInfo) {
+	sort.Slice(childIssues, func(i, j int) bool {
+		// Rankが空の場合は後ろに配置
+		if childIssues[i].Rank == "" && childIssues[j].Rank != "" {
+			return false
+		}
+		if childIssues[i].Rank != "" && childIssues[j].Rank == "" {
+			return true
+		}
+		// 両方とも空でない場合は辞書順でソート
+		return childIssues[i].Rank < childIssues[j].Rank
+	})
+}
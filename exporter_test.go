@@ -0,0 +1,503 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// newTestJIRAServer はExportIssueで使用するエンドポイントを返すモックサーバーを作成する
+func newTestJIRAServer(t *testing.T, issues map[string]*cloud.Issue, children map[string][]string) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path
+
+		switch {
+		case path == "/rest/api/2/field":
+			json.NewEncoder(w).Encode([]cloud.Field{})
+		case strings.HasPrefix(path, "/rest/api/2/project/"):
+			key := strings.TrimPrefix(path, "/rest/api/2/project/")
+			json.NewEncoder(w).Encode(cloud.Project{Key: key, Name: key + " プロジェクト"})
+		case strings.HasSuffix(path, "/remotelink"):
+			json.NewEncoder(w).Encode([]cloud.RemoteLink{})
+		case strings.HasSuffix(path, "/worklog"):
+			json.NewEncoder(w).Encode(cloud.Worklog{Worklogs: []cloud.WorklogRecord{}})
+		case strings.HasSuffix(path, "/comment"):
+			key := strings.TrimSuffix(strings.TrimPrefix(path, "/rest/api/2/issue/"), "/comment")
+			issue, ok := issues[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			page := CommentPage{Comments: issueComments(issue)}
+			page.Total = len(page.Comments)
+			json.NewEncoder(w).Encode(page)
+		case strings.HasPrefix(path, "/rest/api/3/issue/") && strings.HasSuffix(path, "/changelog"):
+			key := strings.TrimSuffix(strings.TrimPrefix(path, "/rest/api/3/issue/"), "/changelog")
+			issue, ok := issues[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			page := ChangelogPage{IsLast: true, Values: []cloud.ChangelogHistory{}}
+			if issue.Changelog != nil {
+				page.Values = issue.Changelog.Histories
+			}
+			page.Total = len(page.Values)
+			json.NewEncoder(w).Encode(page)
+		case strings.HasPrefix(path, "/rest/api/2/issue/"):
+			key := strings.TrimPrefix(path, "/rest/api/2/issue/")
+			issue, ok := issues[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			// 変更履歴は変更履歴APIからのみ返す
+			withoutChangelog := *issue
+			withoutChangelog.Changelog = nil
+			json.NewEncoder(w).Encode(withoutChangelog)
+		case path == "/rest/api/3/search/jql" || path == "/rest/api/2/search/jql":
+			jql := r.URL.Query().Get("jql")
+			resp := JQLSearchResponse{IsLast: true, Issues: []cloud.Issue{}}
+			if strings.HasPrefix(jql, "parent = ") {
+				// parent = "KEY" 形式のJQLから親課題キーを取り出す
+				parentKey := strings.Trim(strings.TrimPrefix(jql, "parent = "), `"`)
+				for _, childKey := range children[parentKey] {
+					if child, ok := issues[childKey]; ok {
+						resp.Issues = append(resp.Issues, *child)
+					} else {
+						resp.Issues = append(resp.Issues, cloud.Issue{Key: childKey})
+					}
+				}
+			} else {
+				// それ以外のJQLは全課題を課題キーの順に返す
+				keys := make([]string, 0, len(issues))
+				for key := range issues {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					resp.Issues = append(resp.Issues, *issues[key])
+				}
+			}
+			json.NewEncoder(w).Encode(resp)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+// newTestIssue はテスト用の課題を作成する
+func newTestIssue(key, issueType, summary string, rank string) *cloud.Issue {
+	issue := &cloud.Issue{
+		ID:  key,
+		Key: key,
+		Fields: &cloud.IssueFields{
+			Summary: summary,
+			Type:    cloud.IssueType{Name: issueType},
+			Status:  &cloud.Status{Name: "未着手"},
+			Project: cloud.Project{Key: "TEST", Name: "TEST プロジェクト"},
+		},
+	}
+	if rank != "" {
+		issue.Fields.Unknowns = map[string]interface{}{"customfield_10019": rank}
+	}
+	return issue
+}
+
+// TestIssueExporter_ExportIssue は課題1件の出力処理（_index.md、親子課題、JSON、Markdown）のテスト
+func TestIssueExporter_ExportIssue(t *testing.T) {
+	epic := newTestIssue("TEST-1", "Epic", "エピック", "")
+	story := newTestIssue("TEST-2", "Story", "ストーリー", "0|b")
+	story.Fields.Parent = &cloud.Parent{Key: "TEST-1"}
+	story.Changelog = &cloud.Changelog{Histories: []cloud.ChangelogHistory{
+		{Id: "1", Author: cloud.User{DisplayName: "変更者"}, Items: []cloud.ChangelogItems{{Field: "status", FromString: "未着手", ToString: "進行中"}}},
+	}}
+	task := newTestIssue("TEST-3", "Task", "タスク", "0|a")
+	subtask := newTestIssue("TEST-4", "Sub-task", "サブタスク", "")
+
+	issues := map[string]*cloud.Issue{
+		"TEST-1": epic,
+		"TEST-2": story,
+		"TEST-3": task,
+		"TEST-4": subtask,
+	}
+	children := map[string][]string{
+		"TEST-1": {"TEST-2", "TEST-3", "TEST-4"},
+	}
+
+	server := newTestJIRAServer(t, issues, children)
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	config := createTestConfig()
+	config.JIRA = JIRAConfig{URL: server.URL, Email: "test@example.com", APIToken: "test-token"}
+	config.Output = OutputConfig{
+		MarkdownDir:    filepath.Join(tmpDir, "markdown"),
+		AttachmentsDir: filepath.Join(tmpDir, "attachments"),
+		JSONDir:        filepath.Join(tmpDir, "json"),
+	}
+
+	jiraClient, err := NewJIRAClient(context.Background(), &config.JIRA)
+	if err != nil {
+		t.Fatalf("JIRAクライアントの作成に失敗しました: %v", err)
+	}
+	exporter := NewIssueExporter(config, jiraClient)
+
+	for _, key := range []string{"TEST-1", "TEST-2"} {
+		if err := exporter.ExportIssue(key); err != nil {
+			t.Fatalf("ExportIssue(%s) error = %v", key, err)
+		}
+	}
+
+	// _index.mdが生成されていること
+	if _, err := os.Stat(filepath.Join(config.Output.MarkdownDir, "TEST", "_index.md")); err != nil {
+		t.Errorf("_index.mdが生成されていません: %v", err)
+	}
+
+	// エピックのJSONに子課題がRank順で保存され、Sub-taskは除外されていること
+	data, err := NewJSONSaver("").LoadIssue(filepath.Join(config.Output.JSONDir, "TEST", "TEST-1.json"))
+	if err != nil {
+		t.Fatalf("JSONの読み込みに失敗しました: %v", err)
+	}
+	if len(data.ChildIssues) != 2 {
+		t.Fatalf("子課題数 = %d, want 2", len(data.ChildIssues))
+	}
+	if data.ChildIssues[0].Key != "TEST-3" || data.ChildIssues[1].Key != "TEST-2" {
+		t.Errorf("子課題の順序 = [%s, %s], want [TEST-3, TEST-2]", data.ChildIssues[0].Key, data.ChildIssues[1].Key)
+	}
+
+	// ストーリーのMarkdownに親課題のパンくずが出力されていること
+	content, err := os.ReadFile(filepath.Join(config.Output.MarkdownDir, "TEST", "TEST-2.md"))
+	if err != nil {
+		t.Fatalf("Markdownの読み込みに失敗しました: %v", err)
+	}
+	if !strings.Contains(string(content), "[🟣 TEST-1](../TEST-1/)") {
+		t.Errorf("親課題のリンクが出力されていません\n実際の出力:\n%s", string(content))
+	}
+
+	// 変更履歴APIから取得した変更履歴が出力されていること
+	if !strings.Contains(string(content), "- **status**: `未着手` → `進行中`") {
+		t.Errorf("変更履歴が出力されていません\n実際の出力:\n%s", string(content))
+	}
+
+	// 存在しない課題はエラーになること
+	if err := exporter.ExportIssue("TEST-999"); err == nil {
+		t.Error("存在しない課題に対してエラーが返されませんでした")
+	}
+}
+
+// TestSortChildIssuesByRank は子課題のRank順ソートのテスト
+func TestSortChildIssuesByRank(t *testing.T) {
+	childIssues := []ChildIssueInfo{
+		{Key: "TEST-1", Rank: ""},
+		{Key: "TEST-2", Rank: "0|c"},
+		{Key: "TEST-3", Rank: "0|a"},
+		{Key: "TEST-4", Rank: "0|b"},
+	}
+
+	sortChildIssuesByRank(childIssues)
+
+	want := []string{"TEST-3", "TEST-4", "TEST-2", "TEST-1"}
+	for i, key := range want {
+		if childIssues[i].Key != key {
+			t.Errorf("childIssues[%d].Key = %q, want %q", i, childIssues[i].Key, key)
+		}
+	}
+}
+
+// TestChildIssueInfos は子課題の検索結果からの変換（サブタスクの除外、Rank順ソート）のテスト
+func TestChildIssueInfos(t *testing.T) {
+	children := []cloud.Issue{
+		{Key: "TEST-1", Fields: &cloud.IssueFields{Summary: "後", Type: cloud.IssueType{Name: "Story"}, Status: &cloud.Status{Name: "Done"}, Unknowns: map[string]interface{}{"customfield_10019": "0|b"}}},
+		{Key: "TEST-2", Fields: &cloud.IssueFields{Summary: "サブタスク", Type: cloud.IssueType{Name: "Sub-task"}}},
+		{Key: "TEST-3", Fields: &cloud.IssueFields{Summary: "先", Type: cloud.IssueType{Name: "Task"}, Unknowns: map[string]interface{}{"customfield_10019": "0|a"}}},
+		{Key: "TEST-4"},
+	}
+
+	got := childIssueInfos(children, "customfield_10019")
+	want := []ChildIssueInfo{
+		{Key: "TEST-3", Summary: "先", Type: "Task", Rank: "0|a"},
+		{Key: "TEST-1", Summary: "後", Status: "Done", Type: "Story", Rank: "0|b"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("childIssueInfos() = %+v, want %+v", got, want)
+	}
+}
+
+// TestIssueExporter_ExportIssues は並行取得時も逐次処理と同じ出力になることのテスト
+func TestIssueExporter_ExportIssues(t *testing.T) {
+	issues := map[string]*cloud.Issue{}
+	var issueKeys []string
+	for i := 1; i <= 20; i++ {
+		key := fmt.Sprintf("TEST-%d", i)
+		issue := newTestIssue(key, "Task", "タスク "+key, "")
+		issue.Fields.Assignee = &cloud.User{AccountID: fmt.Sprintf("user-%d", i%3), DisplayName: fmt.Sprintf("ユーザー%d", i%3)}
+		issues[key] = issue
+		issueKeys = append(issueKeys, key)
+	}
+	// 存在しない課題キーを途中に含める
+	issueKeys = append(issueKeys[:10], append([]string{"TEST-999"}, issueKeys[10:]...)...)
+
+	server := newTestJIRAServer(t, issues, nil)
+	defer server.Close()
+
+	export := func(workers int) (string, []string) {
+		tmpDir := t.TempDir()
+		config := createTestConfig()
+		config.JIRA = JIRAConfig{URL: server.URL, Email: "test@example.com", APIToken: "test-token"}
+		config.Output = OutputConfig{
+			MarkdownDir:    filepath.Join(tmpDir, "markdown"),
+			AttachmentsDir: filepath.Join(tmpDir, "attachments"),
+		}
+
+		jiraClient, err := NewJIRAClient(context.Background(), &config.JIRA)
+		if err != nil {
+			t.Fatalf("JIRAクライアントの作成に失敗しました: %v", err)
+		}
+		failedKeys := NewIssueExporter(config, jiraClient).ExportIssues(context.Background(), issueKeys, workers)
+		return config.Output.MarkdownDir, failedKeys
+	}
+
+	seqDir, seqFailed := export(1)
+	parDir, parFailed := export(4)
+
+	for _, failed := range [][]string{seqFailed, parFailed} {
+		if len(failed) != 1 || failed[0] != "TEST-999" {
+			t.Errorf("failedKeys = %v, want [TEST-999]", failed)
+		}
+	}
+
+	for key := range issues {
+		seq, err := os.ReadFile(filepath.Join(seqDir, "TEST", key+".md"))
+		if err != nil {
+			t.Fatalf("Markdownの読み込みに失敗しました: %v", err)
+		}
+		par, err := os.ReadFile(filepath.Join(parDir, "TEST", key+".md"))
+		if err != nil {
+			t.Fatalf("Markdownの読み込みに失敗しました: %v", err)
+		}
+		if string(seq) != string(par) {
+			t.Errorf("%s の出力が逐次処理と異なります\n逐次:\n%s\n並行:\n%s", key, seq, par)
+		}
+	}
+}
+
+// TestIssueExporter_ResumeFromCheckpoint は中断時に処理中の課題まで出力し、チェックポイントから再開できることのテスト
+func TestIssueExporter_ResumeFromCheckpoint(t *testing.T) {
+	issues := map[string]*cloud.Issue{}
+	var issueKeys []string
+	for i := 1; i <= 6; i++ {
+		key := fmt.Sprintf("TEST-%d", i)
+		issues[key] = newTestIssue(key, "Task", "タスク "+key, "")
+		issueKeys = append(issueKeys, key)
+	}
+	base := newTestJIRAServer(t, issues, nil)
+	defer base.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var mu sync.Mutex
+	requested := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if key, ok := strings.CutPrefix(r.URL.Path, "/rest/api/2/issue/"); ok && !strings.Contains(key, "/") {
+			mu.Lock()
+			requested[key]++
+			mu.Unlock()
+			// TEST-3の取得中に中断要求（Ctrl-C）
+			if key == "TEST-3" {
+				cancel()
+			}
+		}
+		base.Config.Handler.ServeHTTP(w, r)
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	config := createTestConfig()
+	config.JIRA = JIRAConfig{URL: server.URL, Email: "test@example.com", APIToken: "test-token"}
+	config.Output = OutputConfig{
+		MarkdownDir:    filepath.Join(tmpDir, "markdown"),
+		AttachmentsDir: filepath.Join(tmpDir, "attachments"),
+	}
+	checkpointPath := filepath.Join(tmpDir, "checkpoint.jsonl")
+
+	export := func(ctx context.Context, resume bool) []string {
+		jiraClient, err := NewJIRAClient(context.Background(), &config.JIRA)
+		if err != nil {
+			t.Fatalf("JIRAクライアントの作成に失敗しました: %v", err)
+		}
+		checkpoint, err := OpenCheckpoint(checkpointPath, "project = TEST", resume)
+		if err != nil {
+			t.Fatalf("OpenCheckpoint() error = %v", err)
+		}
+		defer checkpoint.Close()
+		exporter := NewIssueExporter(config, jiraClient)
+		exporter.UseCheckpoint(checkpoint)
+		return exporter.ExportIssues(ctx, issueKeys, 1)
+	}
+
+	// 1回目: TEST-3の取得中に中断され、TEST-3までを出力して終了する
+	if failed := export(ctx, false); len(failed) != 0 {
+		t.Errorf("failedKeys = %v", failed)
+	}
+	for i := 1; i <= 6; i++ {
+		key := fmt.Sprintf("TEST-%d", i)
+		_, err := os.Stat(filepath.Join(config.Output.MarkdownDir, "TEST", key+".md"))
+		if exists := err == nil; exists != (i <= 3) {
+			t.Errorf("%s の出力有無 = %v, want %v", key, exists, i <= 3)
+		}
+	}
+
+	// 2回目: チェックポイントから再開し、残りの課題のみ取得する
+	if failed := export(context.Background(), true); len(failed) != 0 {
+		t.Errorf("failedKeys = %v", failed)
+	}
+	for i := 1; i <= 6; i++ {
+		key := fmt.Sprintf("TEST-%d", i)
+		if _, err := os.Stat(filepath.Join(config.Output.MarkdownDir, "TEST", key+".md")); err != nil {
+			t.Errorf("%s が出力されていません", key)
+		}
+		if requested[key] != 1 {
+			t.Errorf("%s の取得回数 = %d, want 1", key, requested[key])
+		}
+	}
+}
+
+// TestIssueExporter_ExportIssuesByJQL は一括取得モードで課題ごとのGetIssueを呼び出さずに出力することのテスト
+func TestIssueExporter_ExportIssuesByJQL(t *testing.T) {
+	epic := newTestIssue("TEST-1", "Epic", "エピック", "")
+	story := newTestIssue("TEST-2", "Story", "ストーリー", "")
+	story.Fields.Parent = &cloud.Parent{Key: "TEST-1"}
+	issues := map[string]*cloud.Issue{
+		"TEST-1": epic,
+		"TEST-2": story,
+	}
+	children := map[string][]string{
+		"TEST-1": {"TEST-2"},
+	}
+
+	// 課題取得APIの呼び出しを記録する
+	var mu sync.Mutex
+	var getIssueCalls []string
+	mock := newTestJIRAServer(t, issues, children)
+	defer mock.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/rest/api/2/issue/") && !strings.Contains(strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue/"), "/") {
+			mu.Lock()
+			getIssueCalls = append(getIssueCalls, r.URL.Path)
+			mu.Unlock()
+		}
+		if r.URL.Path == "/rest/api/2/search/jql" && r.URL.Query().Get("fields") != "*all" {
+			t.Errorf("fields = %q, want *all", r.URL.Query().Get("fields"))
+		}
+		// 変更履歴は課題ごとに全件を取得するため、検索では取得しない
+		if r.URL.Path == "/rest/api/2/search/jql" && r.URL.Query().Get("expand") != "renderedFields" {
+			t.Errorf("expand = %q, want renderedFields", r.URL.Query().Get("expand"))
+		}
+		proxyReq, _ := http.NewRequest(r.Method, mock.URL+r.URL.RequestURI(), nil)
+		resp, err := http.DefaultClient.Do(proxyReq)
+		if err != nil {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		defer resp.Body.Close()
+		w.WriteHeader(resp.StatusCode)
+		io.Copy(w, resp.Body)
+	}))
+	defer server.Close()
+
+	tmpDir := t.TempDir()
+	config := createTestConfig()
+	config.JIRA = JIRAConfig{URL: server.URL, Email: "test@example.com", APIToken: "test-token"}
+	config.Output = OutputConfig{
+		MarkdownDir:    filepath.Join(tmpDir, "markdown"),
+		AttachmentsDir: filepath.Join(tmpDir, "attachments"),
+		JSONDir:        filepath.Join(tmpDir, "json"),
+	}
+
+	jiraClient, err := NewJIRAClient(context.Background(), &config.JIRA)
+	if err != nil {
+		t.Fatalf("JIRAクライアントの作成に失敗しました: %v", err)
+	}
+	failedKeys, err := NewIssueExporter(config, jiraClient).ExportIssuesByJQL(context.Background(), "project = TEST", 50, 0, 2)
+	if err != nil {
+		t.Fatalf("ExportIssuesByJQL() error = %v", err)
+	}
+	if len(failedKeys) != 0 {
+		t.Errorf("failedKeys = %v, want []", failedKeys)
+	}
+
+	// 課題・親課題・子課題のいずれもGetIssueで再取得していないこと
+	if len(getIssueCalls) != 0 {
+		t.Errorf("GetIssueが呼び出されました: %v", getIssueCalls)
+	}
+
+	// 子課題の要約が検索結果から設定されていること
+	data, err := NewJSONSaver("").LoadIssue(filepath.Join(config.Output.JSONDir, "TEST", "TEST-1.json"))
+	if err != nil {
+		t.Fatalf("JSONの読み込みに失敗しました: %v", err)
+	}
+	if len(data.ChildIssues) != 1 || data.ChildIssues[0].Summary != "ストーリー" {
+		t.Errorf("ChildIssues = %+v, want [TEST-2 ストーリー]", data.ChildIssues)
+	}
+
+	// 親課題のパンくずが出力されていること
+	content, err := os.ReadFile(filepath.Join(config.Output.MarkdownDir, "TEST", "TEST-2.md"))
+	if err != nil {
+		t.Fatalf("Markdownの読み込みに失敗しました: %v", err)
+	}
+	if !strings.Contains(string(content), "[🟣 TEST-1](../TEST-1/)") {
+		t.Errorf("親課題のリンクが出力されていません\n実際の出力:\n%s", string(content))
+	}
+}
+
+// TestBuildSprintReport はスプリントの課題の完了判定を確認する
+// 完了済みスプリントでは、スプリント完了後に解決された課題（持ち越し）を完了に含めない
+func TestBuildSprintReport(t *testing.T) {
+	done := &cloud.Status{Name: "完了", StatusCategory: cloud.StatusCategory{Key: "done"}}
+	inProgress := &cloud.Status{Name: "進行中", StatusCategory: cloud.StatusCategory{Key: "indeterminate"}}
+	resolvedAt := func(day int) cloud.Time {
+		return cloud.Time(time.Date(2025, 1, day, 12, 0, 0, 0, time.UTC))
+	}
+
+	sprint := SprintInfo{ID: 1, Name: "Sprint 1", State: "closed", CompleteDate: "2025-01-19T18:00:00.000Z"}
+	issues := []cloud.Issue{
+		{Key: "TEST-1", Fields: &cloud.IssueFields{Summary: "期間内に完了", Status: done, Resolutiondate: resolvedAt(10), Project: cloud.Project{Key: "TEST"}}},
+		{Key: "TEST-2", Fields: &cloud.IssueFields{Summary: "持ち越して完了", Status: done, Resolutiondate: resolvedAt(25), Project: cloud.Project{Key: "TEST"}}},
+		{Key: "TEST-3", Fields: &cloud.IssueFields{Summary: "未完了", Status: inProgress, Project: cloud.Project{Key: "TEST"}}},
+	}
+
+	report := buildSprintReport("TEST ボード", sprint, issues)
+	if len(report.Issues) != 3 {
+		t.Fatalf("課題数 = %d, want 3", len(report.Issues))
+	}
+	wantCompleted := []bool{true, false, false}
+	for i, issue := range report.Issues {
+		if issue.Completed != wantCompleted[i] {
+			t.Errorf("%s の完了判定 = %v, want %v", issue.Key, issue.Completed, wantCompleted[i])
+		}
+	}
+
+	// 進行中のスプリントでは解決日時によらずステータスで判定する
+	sprint.State = "active"
+	report = buildSprintReport("TEST ボード", sprint, issues)
+	if !report.Issues[1].Completed {
+		t.Errorf("進行中のスプリントで TEST-2 が完了と判定されませんでした")
+	}
+}
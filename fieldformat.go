@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// FieldSchemaCache はフィールドID→スキーマ（type・items・custom）のマッピング
+type FieldSchemaCache map[string]cloud.FieldSchema
+
+// BuildFieldSchemaCache はフィールドリストからスキーマのキャッシュを構築する
+func BuildFieldSchemaCache(fields []cloud.Field) FieldSchemaCache {
+	cache := make(FieldSchemaCache)
+	for _, field := range fields {
+		cache[field.ID] = field.Schema
+	}
+	return cache
+}
+
+// FieldFormatOptions はフィールドの値を文字列に変換する際の設定
+type FieldFormatOptions struct {
+	FieldFormatConfig                          // display.field_formats の数値・日付の形式
+	User              func(*cloud.User) string // ユーザーの表示名（nilの場合はdisplayName）
+	Messages          Messages                 // 値が無い場合等の文言（nilの場合は既定の文言）
+}
+
+// FieldFormatter はフィールドの値（配列のフィールドの場合は要素）を文字列に変換する関数
+type FieldFormatter func(value interface{}, opts FieldFormatOptions) string
+
+// customFieldTypePrefix はJira標準のカスタムフィールドの種類（schema.custom）の接頭辞
+const customFieldTypePrefix = "com.atlassian.jira.plugin.system.customfieldtypes:"
+
+// customFieldFormatters はカスタムフィールドの種類（schema.custom）ごとの変換関数
+var customFieldFormatters = map[string]FieldFormatter{
+	customFieldTypePrefix + "textfield":           formatTextFieldValue,
+	customFieldTypePrefix + "textarea":            formatTextFieldValue,
+	customFieldTypePrefix + "readonlyfield":       formatTextFieldValue,
+	customFieldTypePrefix + "url":                 formatTextFieldValue,
+	customFieldTypePrefix + "labels":              formatTextFieldValue,
+	customFieldTypePrefix + "float":               formatNumberFieldValue,
+	customFieldTypePrefix + "importid":            formatNumberFieldValue,
+	customFieldTypePrefix + "datepicker":          formatDateFieldValue,
+	customFieldTypePrefix + "datetime":            formatDateTimeFieldValue,
+	customFieldTypePrefix + "select":              formatOptionFieldValue,
+	customFieldTypePrefix + "radiobuttons":        formatOptionFieldValue,
+	customFieldTypePrefix + "multiselect":         formatOptionFieldValue,
+	customFieldTypePrefix + "multicheckboxes":     formatOptionFieldValue,
+	customFieldTypePrefix + "cascadingselect":     formatCascadingSelectFieldValue,
+	customFieldTypePrefix + "userpicker":          formatUserFieldValue,
+	customFieldTypePrefix + "multiuserpicker":     formatUserFieldValue,
+	customFieldTypePrefix + "people":              formatUserFieldValue,
+	customFieldTypePrefix + "grouppicker":         formatNamedFieldValue,
+	customFieldTypePrefix + "multigrouppicker":    formatNamedFieldValue,
+	customFieldTypePrefix + "version":             formatNamedFieldValue,
+	customFieldTypePrefix + "multiversion":        formatNamedFieldValue,
+	customFieldTypePrefix + "project":             formatNamedFieldValue,
+	customFieldTypePrefix + "atlassian-team":      formatNamedFieldValue,
+	"com.pyxis.greenhopper.jira:jsw-story-points": formatNumberFieldValue,
+	"com.pyxis.greenhopper.jira:gh-epic-link":     formatTextFieldValue,
+	"com.pyxis.greenhopper.jira:gh-lexo-rank":     formatTextFieldValue,
+}
+
+// schemaTypeFormatters は種類が登録されていないフィールドの、値の型（schema.type・配列はschema.items）ごとの変換関数
+var schemaTypeFormatters = map[string]FieldFormatter{
+	"string":            formatTextFieldValue,
+	"number":            formatNumberFieldValue,
+	"date":              formatDateFieldValue,
+	"datetime":          formatDateTimeFieldValue,
+	"option":            formatOptionFieldValue,
+	"option-with-child": formatCascadingSelectFieldValue,
+	"user":              formatUserFieldValue,
+	"group":             formatNamedFieldValue,
+	"version":           formatNamedFieldValue,
+	"project":           formatNamedFieldValue,
+	"component":         formatNamedFieldValue,
+}
+
+// fieldFormatterForSchema はスキーマに対応する変換関数を返す（対応するものが無い場合はnil）
+func fieldFormatterForSchema(schema cloud.FieldSchema) FieldFormatter {
+	if formatter, exists := customFieldFormatters[schema.Custom]; exists {
+		return formatter
+	}
+	if schema.Type == "array" {
+		return schemaTypeFormatters[schema.Items]
+	}
+	return schemaTypeFormatters[schema.Type]
+}
+
+// FormatFieldValue はフィールドの値をスキーマ（GetFieldListで取得したフィールドの種類と型）に従って文字列に変換する
+// 配列の値は要素ごとに変換して ", " で連結する
+// スキーマが不明な場合や対応する変換関数が無い場合はFormatCustomFieldValueで変換する
+func FormatFieldValue(value interface{}, schema cloud.FieldSchema, opts FieldFormatOptions) string {
+	formatter := fieldFormatterForSchema(schema)
+	if formatter == nil || IsCustomFieldEmpty(value) {
+		return formatCustomFieldValue(value, opts.Messages)
+	}
+	items, ok := value.([]interface{})
+	if !ok {
+		return formatter(value, opts)
+	}
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		if s := formatter(item, opts); s != "" {
+			parts = append(parts, s)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// formatTextFieldValue はテキスト（1行・複数行・URL・ラベル等）の値を変換する
+// 開発情報の文字列の判定を行わずにそのまま出力する
+func formatTextFieldValue(value interface{}, opts FieldFormatOptions) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return formatCustomFieldValue(value, opts.Messages)
+}
+
+// formatNumberFieldValue は数値の値を変換する（数値の形式の指定が無い場合、整数は小数点以下を出力しない）
+func formatNumberFieldValue(value interface{}, opts FieldFormatOptions) string {
+	number, ok := CustomFieldNumber(value)
+	if !ok {
+		return formatCustomFieldValue(value, opts.Messages)
+	}
+	if opts.Number != "" {
+		switch n := number.(type) {
+		case int:
+			return fmt.Sprintf(opts.Number, float64(n))
+		case float64:
+			return fmt.Sprintf(opts.Number, n)
+		}
+	}
+	switch n := number.(type) {
+	case int:
+		return strconv.Itoa(n)
+	case float64:
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return formatCustomFieldValue(value, opts.Messages)
+}
+
+// formatDateFieldValue は日付（YYYY-MM-DD）の値を日付の形式（デフォルト: YYYY-MM-DD）で変換する
+func formatDateFieldValue(value interface{}, opts FieldFormatOptions) string {
+	s, ok := value.(string)
+	if !ok {
+		return formatCustomFieldValue(value, opts.Messages)
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		// 日時の値の場合は日時として変換する
+		return formatDateTimeFieldValue(value, opts)
+	}
+	layout := "2006-01-02"
+	if opts.Date != "" {
+		layout = opts.Date
+	}
+	return t.Format(layout)
+}
+
+// formatDateTimeFieldValue は日時の値を日付の形式（デフォルト: YYYY-MM-DD hh:mm:ss）で変換する
+func formatDateTimeFieldValue(value interface{}, opts FieldFormatOptions) string {
+	s, ok := value.(string)
+	if !ok {
+		return formatCustomFieldValue(value, opts.Messages)
+	}
+	layout := "2006-01-02 15:04:05"
+	if opts.Date != "" {
+		layout = opts.Date
+	}
+	// JIRAの日時形式（2026-01-22T00:43:07.025+0900）とRFC3339
+	for _, jiraLayout := range []string{"2006-01-02T15:04:05.000-0700", time.RFC3339} {
+		if t, err := time.Parse(jiraLayout, s); err == nil {
+			return t.Format(layout)
+		}
+	}
+	return s
+}
+
+// formatOptionFieldValue は選択肢（選択リスト・ラジオボタン・チェックボックス）の値を変換する
+func formatOptionFieldValue(value interface{}, opts FieldFormatOptions) string {
+	if obj, ok := value.(map[string]interface{}); ok {
+		if s, ok := obj["value"].(string); ok {
+			return s
+		}
+	}
+	return formatCustomFieldValue(value, opts.Messages)
+}
+
+// formatCascadingSelectFieldValue は連鎖選択リストの値を「親 - 子」の形式で変換する
+func formatCascadingSelectFieldValue(value interface{}, opts FieldFormatOptions) string {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return formatCustomFieldValue(value, opts.Messages)
+	}
+	parent, _ := obj["value"].(string)
+	if child, ok := obj["child"].(map[string]interface{}); ok {
+		if childValue, ok := child["value"].(string); ok && childValue != "" {
+			return parent + " - " + childValue
+		}
+	}
+	return parent
+}
+
+// formatUserFieldValue はユーザーの値を表示名で変換する
+func formatUserFieldValue(value interface{}, opts FieldFormatOptions) string {
+	users := CustomFieldUsers(value)
+	if len(users) == 0 {
+		return formatCustomFieldValue(value, opts.Messages)
+	}
+	names := make([]string, len(users))
+	for i, user := range users {
+		if opts.User != nil {
+			names[i] = opts.User(user)
+		} else {
+			names[i] = user.DisplayName
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatNamedFieldValue は名前を持つオブジェクト（グループ・バージョン・プロジェクト・チーム等）の値を名前で変換する
+func formatNamedFieldValue(value interface{}, opts FieldFormatOptions) string {
+	if obj, ok := value.(map[string]interface{}); ok {
+		for _, key := range []string{"name", "title", "value"} {
+			if s, ok := obj[key].(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	return formatCustomFieldValue(value, opts.Messages)
+}
+
+// fieldFormat はフィールドの数値・日付の形式（display.field_formats、フィールドIDまたはフィールド名で指定）を返す
+func (mw *MarkdownWriter) fieldFormat(fieldID string, fieldNameCache FieldNameCache) FieldFormatConfig {
+	if mw.config == nil || len(mw.config.Display.FieldFormats) == 0 {
+		return FieldFormatConfig{}
+	}
+	formats := mw.config.Display.FieldFormats
+	if format, exists := formats[fieldID]; exists {
+		return format
+	}
+	if name, exists := fieldNameCache[fieldID]; exists {
+		for field, format := range formats {
+			if strings.EqualFold(field, name) {
+				return format
+			}
+		}
+	}
+	return FieldFormatConfig{}
+}
+
+// formatFieldValue はフィールドの値をスキーマとdisplay.field_formatsの形式に従って文字列に変換する
+func (mw *MarkdownWriter) formatFieldValue(fieldID string, value interface{}, fieldSchemas FieldSchemaCache, fieldNameCache FieldNameCache) string {
+	return FormatFieldValue(value, fieldSchemas[fieldID], FieldFormatOptions{
+		FieldFormatConfig: mw.fieldFormat(fieldID, fieldNameCache),
+		User:              mw.getUser,
+		Messages:          mw.messages,
+	})
+}
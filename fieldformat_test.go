@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// customSchema はJira標準のカスタムフィールドのスキーマを作成する
+func customSchema(fieldType, items, custom string) cloud.FieldSchema {
+	return cloud.FieldSchema{Type: fieldType, Items: items, Custom: customFieldTypePrefix + custom}
+}
+
+// TestFormatFieldValue はスキーマの種類ごとのフィールドの値の変換を確認する
+func TestFormatFieldValue(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    interface{}
+		schema   cloud.FieldSchema
+		opts     FieldFormatOptions
+		expected string
+	}{
+		{
+			name:     "数値（整数）",
+			value:    float64(3),
+			schema:   customSchema("number", "", "float"),
+			expected: "3",
+		},
+		{
+			name:     "数値（小数）",
+			value:    2.5,
+			schema:   customSchema("number", "", "float"),
+			expected: "2.5",
+		},
+		{
+			name:     "数値（形式の指定）",
+			value:    float64(3),
+			schema:   customSchema("number", "", "float"),
+			opts:     FieldFormatOptions{FieldFormatConfig: FieldFormatConfig{Number: "%.1f pt"}},
+			expected: "3.0 pt",
+		},
+		{
+			name:     "ストーリーポイントの見積もり",
+			value:    float64(8),
+			schema:   cloud.FieldSchema{Type: "number", Custom: "com.pyxis.greenhopper.jira:jsw-story-points"},
+			expected: "8",
+		},
+		{
+			name:     "日付",
+			value:    "2025-01-10",
+			schema:   customSchema("date", "", "datepicker"),
+			expected: "2025-01-10",
+		},
+		{
+			name:     "日付（形式の指定）",
+			value:    "2025-01-10",
+			schema:   customSchema("date", "", "datepicker"),
+			opts:     FieldFormatOptions{FieldFormatConfig: FieldFormatConfig{Date: "2006年1月2日"}},
+			expected: "2025年1月10日",
+		},
+		{
+			name:     "日時",
+			value:    "2025-01-10T09:30:00.000+0900",
+			schema:   customSchema("datetime", "", "datetime"),
+			expected: "2025-01-10 09:30:00",
+		},
+		{
+			name:     "URL（開発情報と同じ文字列を含む）",
+			value:    "https://example.com/?repository=app",
+			schema:   customSchema("string", "", "url"),
+			expected: "https://example.com/?repository=app",
+		},
+		{
+			name:     "ラベル",
+			value:    []interface{}{"frontend", "urgent"},
+			schema:   customSchema("array", "string", "labels"),
+			expected: "frontend, urgent",
+		},
+		{
+			name:     "選択リスト",
+			value:    map[string]interface{}{"id": "1", "value": "高"},
+			schema:   customSchema("option", "", "select"),
+			expected: "高",
+		},
+		{
+			name: "複数選択",
+			value: []interface{}{
+				map[string]interface{}{"value": "A"},
+				map[string]interface{}{"value": "B"},
+			},
+			schema:   customSchema("array", "option", "multiselect"),
+			expected: "A, B",
+		},
+		{
+			name: "連鎖選択リスト",
+			value: map[string]interface{}{
+				"value": "ハードウェア",
+				"child": map[string]interface{}{"value": "ノートPC"},
+			},
+			schema:   customSchema("option-with-child", "", "cascadingselect"),
+			expected: "ハードウェア - ノートPC",
+		},
+		{
+			name:     "連鎖選択リスト（子の選択なし）",
+			value:    map[string]interface{}{"value": "ソフトウェア"},
+			schema:   customSchema("option-with-child", "", "cascadingselect"),
+			expected: "ソフトウェア",
+		},
+		{
+			name: "複数ユーザー",
+			value: []interface{}{
+				map[string]interface{}{"accountId": "a1", "displayName": "ユーザー1"},
+				map[string]interface{}{"accountId": "a2", "displayName": "ユーザー2"},
+			},
+			schema:   customSchema("array", "user", "multiuserpicker"),
+			expected: "ユーザー1, ユーザー2",
+		},
+		{
+			name:   "ユーザー（表示名の関数）",
+			value:  map[string]interface{}{"accountId": "a1", "displayName": "ユーザー1"},
+			schema: customSchema("user", "", "userpicker"),
+			opts: FieldFormatOptions{User: func(user *cloud.User) string {
+				return user.DisplayName + "さん"
+			}},
+			expected: "ユーザー1さん",
+		},
+		{
+			name:     "バージョン",
+			value:    []interface{}{map[string]interface{}{"id": "10", "name": "1.0"}},
+			schema:   customSchema("array", "version", "multiversion"),
+			expected: "1.0",
+		},
+		{
+			name:     "未登録の種類は型で変換",
+			value:    float64(5),
+			schema:   cloud.FieldSchema{Type: "number", Custom: "com.example:custom-number"},
+			expected: "5",
+		},
+		{
+			name:     "スキーマ不明",
+			value:    map[string]interface{}{"value": "高"},
+			expected: "高",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatFieldValue(tt.value, tt.schema, tt.opts)
+			if result != tt.expected {
+				t.Errorf("FormatFieldValue(%v) = %q, expected %q", tt.value, result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestGenerateBasicInfo_FieldSchema は基本情報のカスタムフィールドをスキーマとdisplay.field_formatsに従って出力することを確認する
+func TestGenerateBasicInfo_FieldSchema(t *testing.T) {
+	config := createTestConfig()
+	config.Display.FieldFormats = map[string]FieldFormatConfig{
+		"Due":               {Date: "2006/01/02 15:04"},
+		"customfield_10017": {Number: "%.1f"},
+	}
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+
+	issue := &cloud.Issue{
+		Key: "TEST-1",
+		Fields: &cloud.IssueFields{
+			Type:   cloud.IssueType{Name: "Task"},
+			Status: &cloud.Status{Name: "未着手"},
+			Unknowns: map[string]interface{}{
+				"customfield_10016": float64(3),
+				"customfield_10017": float64(2),
+				"customfield_10018": "2025-01-10T09:30:00.000+0900",
+				"customfield_10030": map[string]interface{}{"value": "A", "child": map[string]interface{}{"value": "A-1"}},
+			},
+		},
+	}
+	fields := []cloud.Field{
+		{ID: "customfield_10016", Name: "Story Points", Schema: customSchema("number", "", "float")},
+		{ID: "customfield_10017", Name: "Score", Schema: customSchema("number", "", "float")},
+		{ID: "customfield_10018", Name: "Due", Schema: customSchema("datetime", "", "datetime")},
+		{ID: "customfield_10030", Name: "Category", Schema: customSchema("option-with-child", "", "cascadingselect")},
+	}
+
+	var sb strings.Builder
+	mw.generateBasicInfo(&sb, issue, BuildFieldNameCache(fields), BuildFieldSchemaCache(fields), nil)
+	result := sb.String()
+
+	for _, expected := range []string{
+		"- **Story Points**: 3\n",
+		"- **Score**: 2.0\n",
+		"- **Due**: 2025/01/10 09:30\n",
+		"- **Category**: A - A-1\n",
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("基本情報に %q が含まれていません\n%s", expected, result)
+		}
+	}
+}
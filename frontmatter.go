@@ -0,0 +1,254 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// frontMatter はページのフロントマターの項目（キーと値、追加した順に出力する）
+type frontMatter struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// newFrontMatter は空のフロントマターを作成する
+func newFrontMatter() *frontMatter {
+	return &frontMatter{values: make(map[string]interface{})}
+}
+
+// Set は項目を設定する（既存のキーの場合は出力順を変えずに値を置き換える）
+// 値は string、bool、int、float64、time.Time、[]string のいずれか
+func (fm *frontMatter) Set(key string, value interface{}) {
+	if _, exists := fm.values[key]; !exists {
+		fm.keys = append(fm.keys, key)
+	}
+	fm.values[key] = value
+}
+
+// Encode はフロントマターを指定された形式（"toml"、"yaml"、"json"）で区切りを含めて出力する
+func (fm *frontMatter) Encode(format string) (string, error) {
+	switch format {
+	case "", "toml":
+		return fm.encodeTOML()
+	case "yaml":
+		return fm.encodeYAML()
+	case "json":
+		return fm.encodeJSON()
+	default:
+		return "", fmt.Errorf("未対応のフロントマターの形式です: %s", format)
+	}
+}
+
+// encodeTOML はTOML形式（+++ で囲む）で出力する
+func (fm *frontMatter) encodeTOML() (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("+++\n")
+	for _, key := range fm.keys {
+		// 出力順を保持するため1項目ずつエンコードする
+		if err := toml.NewEncoder(&buf).Encode(map[string]interface{}{key: fm.values[key]}); err != nil {
+			return "", fmt.Errorf("フロントマターの %s をTOMLに変換できません: %w", key, err)
+		}
+	}
+	buf.WriteString("+++\n")
+	return buf.String(), nil
+}
+
+// encodeJSON はJSON形式（オブジェクト）で出力する
+func (fm *frontMatter) encodeJSON() (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("{\n")
+	for i, key := range fm.keys {
+		value, err := marshalFrontMatterJSON(fm.values[key], "  ")
+		if err != nil {
+			return "", fmt.Errorf("フロントマターの %s をJSONに変換できません: %w", key, err)
+		}
+		name, _ := marshalFrontMatterJSON(key, "")
+		buf.WriteString("  " + name + ": " + value)
+		if i < len(fm.keys)-1 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n")
+	}
+	buf.WriteString("}\n")
+	return buf.String(), nil
+}
+
+// yamlPlainKeyPattern は引用符なしで出力できるYAMLのキーにマッチする
+var yamlPlainKeyPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// encodeYAML はYAML形式（--- で囲む）で出力する
+// 文字列はJSONの文字列表現（YAMLのダブルクォート形式として有効）で出力する
+func (fm *frontMatter) encodeYAML() (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	for _, key := range fm.keys {
+		name := key
+		if !yamlPlainKeyPattern.MatchString(key) {
+			name, _ = marshalFrontMatterJSON(key, "")
+		}
+		var value string
+		switch v := fm.values[key].(type) {
+		case time.Time:
+			value = v.Format(time.RFC3339)
+		case bool:
+			value = strconv.FormatBool(v)
+		case int:
+			value = strconv.Itoa(v)
+		case []string:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i], _ = marshalFrontMatterJSON(item, "")
+			}
+			value = "[" + strings.Join(items, ", ") + "]"
+		default:
+			// 文字列等はJSONの表現（YAMLのダブルクォート・フロー形式）で出力する
+			encoded, err := marshalFrontMatterJSON(v, "")
+			if err != nil {
+				return "", fmt.Errorf("フロントマターの %s をYAMLに変換できません: %w", key, err)
+			}
+			value = encoded
+		}
+		buf.WriteString(name + ": " + value + "\n")
+	}
+	buf.WriteString("---\n")
+	return buf.String(), nil
+}
+
+// marshalFrontMatterJSON は値をJSONに変換する（HTMLの文字はエスケープしない）
+// prefixが空でない場合は2行目以降をprefixで字下げした複数行の形式にする
+func marshalFrontMatterJSON(value interface{}, prefix string) (string, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	if prefix != "" {
+		encoder.SetIndent(prefix, "  ")
+	}
+	if err := encoder.Encode(value); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// frontMatterFormat はフロントマターの形式（output.front_matter、デフォルト: "toml"）を返す
+func (mw *MarkdownWriter) frontMatterFormat() string {
+	if mw.config == nil || mw.config.Output.FrontMatter == "" {
+		return "toml"
+	}
+	return mw.config.Output.FrontMatter
+}
+
+// writeFrontMatter はフロントマターを設定の形式で書き込む
+func (mw *MarkdownWriter) writeFrontMatter(sb *strings.Builder, fm *frontMatter) error {
+	encoded, err := fm.Encode(mw.frontMatterFormat())
+	if err != nil {
+		return err
+	}
+	sb.WriteString(encoded)
+	return nil
+}
+
+// frontMatterTime はフロントマターに出力する日時（秒未満を切り捨てる）を返す
+func frontMatterTime(t time.Time) time.Time {
+	return t.Truncate(time.Second)
+}
+
+// setMappedFields は [front_matter.fields] で指定されたフィールドの値を型に従って変換し、フロントマターに設定する
+// 値が無いフィールドは出力せず、型に変換できない値は警告を出して出力しない
+func (mw *MarkdownWriter) setMappedFields(fm *frontMatter, issue *cloud.Issue, fieldNameCache FieldNameCache, fieldSchemas FieldSchemaCache) {
+	if mw.config == nil || len(mw.config.FrontMatter.Fields) == 0 {
+		return
+	}
+	mappings := mw.config.FrontMatter.Fields
+
+	// フロントマターのキーの順に出力する
+	fields := make([]string, 0, len(mappings))
+	for field := range mappings {
+		fields = append(fields, field)
+	}
+	sort.Slice(fields, func(i, j int) bool {
+		return mappings[fields[i]].Key < mappings[fields[j]].Key
+	})
+
+	values := GetAllFieldValues(issue)
+	for _, field := range fields {
+		mapping := mappings[field]
+		fieldID := resolveFieldID(field, values, fieldNameCache)
+		value, exists := values[fieldID]
+		if !exists || IsCustomFieldEmpty(value) {
+			continue
+		}
+		typed, ok := mw.typedFieldValue(value, mapping.Type, func() string {
+			return mw.formatFieldValue(fieldID, value, fieldSchemas, fieldNameCache)
+		})
+		if !ok {
+			slog.Warn("フロントマターに出力するフィールドの値を変換できません", "issueKey", issue.Key, "field", field, "type", mapping.Type)
+			continue
+		}
+		fm.Set(mapping.Key, typed)
+	}
+}
+
+// resolveFieldID はフィールドIDまたはフィールド名からフィールドIDを返す
+// 同じ名前のフィールドが複数ある場合は、課題に値があるフィールドのうちIDの順で最初のもの
+func resolveFieldID(field string, values map[string]interface{}, fieldNameCache FieldNameCache) string {
+	if _, exists := values[field]; exists {
+		return field
+	}
+	if _, exists := fieldNameCache[field]; exists {
+		return field
+	}
+	var candidates []string
+	for id, name := range fieldNameCache {
+		if strings.EqualFold(name, field) {
+			candidates = append(candidates, id)
+		}
+	}
+	sort.Strings(candidates)
+	for _, id := range candidates {
+		if _, exists := values[id]; exists {
+			return id
+		}
+	}
+	return field
+}
+
+// typedFieldValue はフィールドの値を型（"string"、"number"、"date"、"list"、"user"）の値に変換する
+// "string" の場合はformatの文字列（基本情報と同じ形式）にする
+func (mw *MarkdownWriter) typedFieldValue(value interface{}, fieldType string, format func() string) (interface{}, bool) {
+	switch fieldType {
+	case "number":
+		return CustomFieldNumber(value)
+	case "date":
+		return CustomFieldDate(value)
+	case "list":
+		list := CustomFieldList(value, mw.messages)
+		return list, len(list) > 0
+	case "user":
+		users := CustomFieldUsers(value)
+		if len(users) == 0 {
+			return nil, false
+		}
+		names := make([]string, len(users))
+		for i, user := range users {
+			names[i] = mw.getUser(user)
+		}
+		// 複数のユーザーを選択するフィールドはリストにする
+		if _, isList := value.([]interface{}); isList {
+			return names, true
+		}
+		return names[0], true
+	default:
+		s := format()
+		return s, s != "" && s != mw.msg("not_set")
+	}
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+	"gopkg.in/yaml.v3"
+)
+
+// TestFrontMatterEncode はフロントマターの各形式の出力（項目の順序とエスケープ）を確認する
+func TestFrontMatterEncode(t *testing.T) {
+	fm := newFrontMatter()
+	fm.Set("title", `"引用符" と \ と <b>`)
+	fm.Set("date", time.Date(2025, 1, 1, 10, 0, 0, 0, time.FixedZone("JST", 9*60*60)))
+	fm.Set("sprint_id", 3)
+	fm.Set("released", true)
+	fm.Set("tags", []string{"a, b", "c"})
+	fm.Set("status", "完了")
+	fm.Set("title", "置き換えたタイトル: 1行目\n2行目")
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "toml",
+			want: "+++\n" +
+				"title = \"置き換えたタイトル: 1行目\\n2行目\"\n" +
+				"date = 2025-01-01T10:00:00+09:00\n" +
+				"sprint_id = 3\n" +
+				"released = true\n" +
+				"tags = [\"a, b\", \"c\"]\n" +
+				"status = \"完了\"\n" +
+				"+++\n",
+		},
+		{
+			format: "yaml",
+			want: "---\n" +
+				"title: \"置き換えたタイトル: 1行目\\n2行目\"\n" +
+				"date: 2025-01-01T10:00:00+09:00\n" +
+				"sprint_id: 3\n" +
+				"released: true\n" +
+				"tags: [\"a, b\", \"c\"]\n" +
+				"status: \"完了\"\n" +
+				"---\n",
+		},
+		{
+			format: "json",
+			want: "{\n" +
+				"  \"title\": \"置き換えたタイトル: 1行目\\n2行目\",\n" +
+				"  \"date\": \"2025-01-01T10:00:00+09:00\",\n" +
+				"  \"sprint_id\": 3,\n" +
+				"  \"released\": true,\n" +
+				"  \"tags\": [\n    \"a, b\",\n    \"c\"\n  ],\n" +
+				"  \"status\": \"完了\"\n" +
+				"}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			got, err := fm.Encode(tt.format)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Encode()\n実際:\n%s\n期待:\n%s", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := fm.Encode("xml"); err == nil {
+		t.Error("Encode() 未対応の形式でエラーになりません")
+	}
+}
+
+// TestFrontMatterEncode_Escape は引用符・バックスラッシュ・HTMLの文字をエスケープすることを確認する
+func TestFrontMatterEncode_Escape(t *testing.T) {
+	fm := newFrontMatter()
+	fm.Set("title", `"引用符" と \ と <b>`)
+
+	want := map[string]string{
+		"toml": "+++\ntitle = \"\\\"引用符\\\" と \\\\ と <b>\"\n+++\n",
+		"yaml": "---\ntitle: \"\\\"引用符\\\" と \\\\ と <b>\"\n---\n",
+		"json": "{\n  \"title\": \"\\\"引用符\\\" と \\\\ と <b>\"\n}\n",
+	}
+	for format, expected := range want {
+		got, err := fm.Encode(format)
+		if err != nil {
+			t.Fatalf("Encode(%s) error = %v", format, err)
+		}
+		if got != expected {
+			t.Errorf("Encode(%s)\n実際:\n%s\n期待:\n%s", format, got, expected)
+		}
+	}
+}
+
+// TestFrontMatterEncode_YAMLRoundTrip はYAML形式の出力をYAMLのパーサーで読み戻し、キーの順序・値・型が保たれることを確認する
+func TestFrontMatterEncode_YAMLRoundTrip(t *testing.T) {
+	fm := newFrontMatter()
+	values := []struct {
+		key   string
+		value interface{}
+	}{
+		{"title", "📦\"引用符\" と 'シングル' と \\ と <b>&amp;</b>"},
+		{"summary", "コロン: と # コメント風 と - 先頭のハイフン"},
+		{"multiline", "1行目\n2行目\r\n\tタブ\u2028区切り"},
+		{"padded", "  前後の空白  "},
+		{"bool_like", "true"},
+		{"null_like", "null"},
+		{"yes_like", "yes"},
+		{"number_like", "007"},
+		{"float_like", "1e3"},
+		{"empty", ""},
+		{"date", time.Date(2025, 1, 1, 10, 0, 0, 0, time.FixedZone("JST", 9*60*60))},
+		{"released", false},
+		{"sprint_id", 42},
+		{"story_points", 3.5},
+		{"tags", []string{"a, b", "[c]", "d: e", "", "😀"}},
+		{"no_tags", []string{}},
+		{"カスタム キー", "日本語のキー"},
+		{"key: with colon", "記号を含むキー"},
+		{"-dash", "ハイフンで始まるキー"},
+	}
+	for _, v := range values {
+		fm.Set(v.key, v.value)
+	}
+
+	encoded, err := fm.Encode("yaml")
+	if err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	body, ok := strings.CutPrefix(encoded, "---\n")
+	if !ok || !strings.HasSuffix(body, "---\n") {
+		t.Fatalf("YAMLの区切りがありません:\n%s", encoded)
+	}
+	body = strings.TrimSuffix(body, "---\n")
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(body), &doc); err != nil {
+		t.Fatalf("出力をYAMLとして読み込めません: %v\n%s", err, encoded)
+	}
+	mapping := doc.Content[0]
+	if len(mapping.Content) != len(values)*2 {
+		t.Fatalf("項目数 = %d, want %d\n%s", len(mapping.Content)/2, len(values), encoded)
+	}
+	for i, v := range values {
+		keyNode, valueNode := mapping.Content[i*2], mapping.Content[i*2+1]
+		if keyNode.Value != v.key {
+			t.Errorf("%d番目のキー = %q, want %q", i, keyNode.Value, v.key)
+			continue
+		}
+
+		// 文字列は文字列のまま（true・null・007等が別の型として解釈されない）
+		if _, isString := v.value.(string); isString && valueNode.ShortTag() != "!!str" {
+			t.Errorf("%s の型 = %s, want !!str", v.key, valueNode.ShortTag())
+		}
+		if items, isList := v.value.([]string); isList {
+			if valueNode.Kind != yaml.SequenceNode || len(valueNode.Content) != len(items) {
+				t.Errorf("%s がリストとして読み込めません", v.key)
+				continue
+			}
+			for _, item := range valueNode.Content {
+				if item.ShortTag() != "!!str" {
+					t.Errorf("%s の要素 %q の型 = %s, want !!str", v.key, item.Value, item.ShortTag())
+				}
+			}
+		}
+
+		decoded := reflect.New(reflect.TypeOf(v.value))
+		if err := valueNode.Decode(decoded.Interface()); err != nil {
+			t.Errorf("%s の値を読み込めません: %v", v.key, err)
+			continue
+		}
+		got := decoded.Elem().Interface()
+		if want, isTime := v.value.(time.Time); isTime {
+			if !got.(time.Time).Equal(want) {
+				t.Errorf("%s = %v, want %v", v.key, got, want)
+			}
+			continue
+		}
+		if !reflect.DeepEqual(got, v.value) {
+			t.Errorf("%s = %#v, want %#v", v.key, got, v.value)
+		}
+	}
+}
+
+// TestWriteProjectIndex_FrontMatterFormat はoutput.front_matterの形式でプロジェクトページを出力することを確認する
+func TestWriteProjectIndex_FrontMatterFormat(t *testing.T) {
+	outputDir := t.TempDir()
+	config := createTestConfig()
+	config.Output.FrontMatter = "yaml"
+	mw := NewMarkdownWriter(context.Background(), outputDir, "", nil, config)
+
+	if err := mw.WriteProjectIndex(&cloud.Project{Key: "PROJ", Name: `"特殊" プロジェクト`}); err != nil {
+		t.Fatalf("WriteProjectIndex() error = %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(outputDir, "PROJ", "_index.md"))
+	if err != nil {
+		t.Fatalf("_index.mdの読み込みに失敗しました: %v", err)
+	}
+	want := "---\n" +
+		"title: \"📦\\\"特殊\\\" プロジェクト\"\n" +
+		"project_key: \"PROJ\"\n" +
+		"project_name: \"\\\"特殊\\\" プロジェクト\"\n" +
+		"type: \"project\"\n" +
+		"---\n\n# \"特殊\" プロジェクト\n\n"
+	if got := string(content); !strings.HasPrefix(got, want) {
+		t.Errorf("_index.mdのフロントマターが期待と異なります\n実際:\n%s\n期待:\n%s", got, want)
+	}
+}
+
+// TestIssueFrontMatter_MappedFields は[front_matter.fields]で指定したフィールドを型に従ってフロントマターに出力することを確認する
+func TestIssueFrontMatter_MappedFields(t *testing.T) {
+	config := createTestConfig()
+	config.FrontMatter.Fields = map[string]FrontMatterFieldConfig{
+		"customfield_10016": {Key: "story_points", Type: "number"},
+		"customfield_10020": {Key: "sprint", Type: "list"},
+		"Team":              {Key: "team", Type: "string"},
+		"customfield_10015": {Key: "startdate", Type: "date"},
+		"customfield_10040": {Key: "reviewers", Type: "user"},
+		"customfield_10041": {Key: "owner", Type: "user"},
+		"customfield_10042": {Key: "estimate", Type: "number"},
+		"customfield_10099": {Key: "missing", Type: "string"},
+	}
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+
+	issue := &cloud.Issue{
+		Key: "TEST-1",
+		Fields: &cloud.IssueFields{
+			Summary: "課題",
+			Type:    cloud.IssueType{Name: "Task"},
+			Status:  &cloud.Status{Name: "未着手"},
+			Project: cloud.Project{Key: "TEST"},
+			Unknowns: map[string]interface{}{
+				"customfield_10016": 5.0,
+				"customfield_10020": []interface{}{
+					map[string]interface{}{"id": 1.0, "name": "Sprint 1"},
+					map[string]interface{}{"id": 2.0, "name": "Sprint 2"},
+				},
+				"customfield_10030": map[string]interface{}{"value": "Platform"},
+				"customfield_10015": "2025-01-10",
+				"customfield_10040": []interface{}{
+					map[string]interface{}{"accountId": "a1", "displayName": "ユーザー1"},
+					map[string]interface{}{"accountId": "a2", "displayName": "ユーザー2"},
+				},
+				"customfield_10041": map[string]interface{}{"accountId": "a3", "displayName": "ユーザー3"},
+				"customfield_10042": "見積もり不可",
+			},
+		},
+	}
+	fieldNameCache := FieldNameCache{"customfield_10030": "Team"}
+
+	var sb strings.Builder
+	if err := mw.generateFrontMatter(&sb, &IssueData{Issue: issue}, fieldNameCache); err != nil {
+		t.Fatalf("generateFrontMatter() error = %v", err)
+	}
+	got := sb.String()
+
+	// 既定の項目と同じキーは出力順を変えずに値を置き換え、それ以外はキーの順で最後に追加する
+	want := "status = \"未着手\"\n" +
+		"assignee = \"未設定\"\n" +
+		"startdate = \"2025-01-10\"\n" +
+		"owner = \"ユーザー3\"\n" +
+		"reviewers = [\"ユーザー1\", \"ユーザー2\"]\n" +
+		"sprint = [\"Sprint 1\", \"Sprint 2\"]\n" +
+		"story_points = 5\n" +
+		"team = \"Platform\"\n" +
+		"+++\n"
+	if !strings.HasSuffix(got, want) {
+		t.Errorf("フロントマターが期待と異なります\n実際:\n%s\n期待する末尾:\n%s", got, want)
+	}
+	// 数値に変換できない値・値の無いフィールドは出力しない
+	for _, key := range []string{"estimate", "missing"} {
+		if strings.Contains(got, key+" =") {
+			t.Errorf("フロントマターに %s が出力されています\n%s", key, got)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// jiraIconValue は[display.icons]でJiraのアイコン（iconUrl）をダウンロードして使用することを表す値
+const jiraIconValue = "jira"
+
+// defaultProjectIcon はプロジェクトのデフォルトのアイコン
+const defaultProjectIcon = "📦"
+
+// iconsDirName はダウンロードしたJiraのアイコンを保存するディレクトリ（添付ファイルディレクトリ内）
+const iconsDirName = "icons"
+
+// アイコンの種類（JiraのREST APIの一覧 /rest/api/2/<種類> の名前）
+const (
+	iconKindIssueType = "issuetype"
+	iconKindPriority  = "priority"
+	iconKindStatus    = "status"
+)
+
+// ページから添付ファイルディレクトリへの相対パス（ダウンロードしたJiraのアイコンの参照に使用）
+const (
+	issuePageAttachments = "../../attachments/"    // 課題ページ（<プロジェクト>/<課題キー>/）
+	subPageAttachments   = "../../../attachments/" // スプリントページ・リリースノート（<プロジェクト>/sprints/<ページ>/ 等）
+)
+
+// JiraIcon はJiraの課題タイプ・優先度・ステータスの名前とアイコンのURL
+type JiraIcon struct {
+	Name    string `json:"name"`
+	IconURL string `json:"iconUrl"`
+}
+
+// table は種類ごとのアイコンの設定（名前 → アイコン）を返す
+func (c *IconsConfig) table(kind string) map[string]string {
+	switch kind {
+	case iconKindIssueType:
+		return c.IssueTypes
+	case iconKindPriority:
+		return c.Priorities
+	case iconKindStatus:
+		return c.Statuses
+	}
+	return nil
+}
+
+// lookup は名前のアイコンの設定を返す（名前の設定が無い場合は "*" の設定、どちらも無い場合はfalse）
+func (c *IconsConfig) lookup(kind, name string) (string, bool) {
+	table := c.table(kind)
+	if icon, exists := table[name]; exists {
+		return icon, true
+	}
+	icon, exists := table["*"]
+	return icon, exists
+}
+
+// usesJiraIcons は種類のいずれかのアイコンにJiraのアイコンを指定しているかを返す
+func (c *IconsConfig) usesJiraIcons(kind string) bool {
+	for _, icon := range c.table(kind) {
+		if icon == jiraIconValue {
+			return true
+		}
+	}
+	return false
+}
+
+// iconKinds はJiraのアイコンを使用できる種類
+var iconKinds = []string{iconKindIssueType, iconKindPriority, iconKindStatus}
+
+// jiraIconBaseName はダウンロードしたJiraのアイコンのファイル名（拡張子を除く）を返す
+func jiraIconBaseName(kind, name string) string {
+	return kind + "_" + url.PathEscape(name)
+}
+
+// iconExtension はアイコンのファイルの拡張子をURLのパス、またはContent-Typeから決める
+func iconExtension(iconURL, contentType string) string {
+	if u, err := url.Parse(iconURL); err == nil && IsImageFile(u.Path) {
+		return strings.ToLower(path.Ext(u.Path))
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "image/svg+xml":
+		return ".svg"
+	case "image/gif":
+		return ".gif"
+	case "image/jpeg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	default:
+		return ".png"
+	}
+}
+
+// iconsConfig はアイコンの設定を返す（設定が無い場合は空の設定）
+func (mw *MarkdownWriter) iconsConfig() *IconsConfig {
+	if mw.config == nil {
+		return &IconsConfig{}
+	}
+	return &mw.config.Display.Icons
+}
+
+// loadJiraIcons はダウンロード済みのJiraのアイコン（ファイル名（拡張子を除く） → ファイル名）を返す
+// Jiraのアイコンを指定していない場合はnilを返す（JSONからの変換ではダウンロード済みのアイコンのみ使用する）
+func loadJiraIcons(attachmentsDir string, config *Config) map[string]string {
+	if config == nil {
+		return nil
+	}
+	used := false
+	for _, kind := range iconKinds {
+		used = used || config.Display.Icons.usesJiraIcons(kind)
+	}
+	if !used {
+		return nil
+	}
+
+	icons := make(map[string]string)
+	entries, err := os.ReadDir(filepath.Join(attachmentsDir, iconsDirName))
+	if err != nil {
+		return icons
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !IsImageFile(name) {
+			continue
+		}
+		icons[strings.TrimSuffix(name, filepath.Ext(name))] = name
+	}
+	return icons
+}
+
+// DownloadJiraIcons は[display.icons]で "jira" を指定した課題タイプ・優先度・ステータスのアイコンをダウンロードする
+// ダウンロード済みのアイコンは再取得しない。取得に失敗した場合は警告を出して組み込みのアイコンで継続する
+// 同じ名前が複数ある場合（チーム管理対象プロジェクトの課題タイプ等）は一覧の最初のアイコンを使用する
+func (mw *MarkdownWriter) DownloadJiraIcons(jiraClient *JIRAClient, downloader *Downloader) {
+	icons := mw.iconsConfig()
+	for _, kind := range iconKinds {
+		if !icons.usesJiraIcons(kind) {
+			continue
+		}
+		list, err := jiraClient.GetIcons(kind)
+		if err != nil {
+			slog.Warn("Jiraのアイコンの一覧の取得に失敗（組み込みのアイコンで継続）", "kind", kind, "error", err)
+			fmt.Printf("警告: Jiraのアイコンの一覧の取得に失敗しました: %v\n", err)
+			continue
+		}
+		for _, jiraIcon := range list {
+			if icon, _ := icons.lookup(kind, jiraIcon.Name); icon != jiraIconValue || jiraIcon.IconURL == "" {
+				continue
+			}
+			baseName := jiraIconBaseName(kind, jiraIcon.Name)
+			if _, exists := mw.jiraIcons[baseName]; exists {
+				continue
+			}
+			// 認証情報はJiraのホストのアイコンの取得時のみ付ける
+			filename, err := downloader.DownloadIcon(jiraIcon.IconURL, baseName, jiraClient.IsJiraURL(jiraIcon.IconURL))
+			if err != nil {
+				slog.Warn("Jiraのアイコンのダウンロードに失敗（組み込みのアイコンで継続）", "kind", kind, "name", jiraIcon.Name, "error", err)
+				fmt.Printf("警告: %s のアイコンのダウンロードに失敗しました: %v\n", jiraIcon.Name, err)
+				continue
+			}
+			mw.jiraIcons[baseName] = filename
+		}
+	}
+}
+
+// isImageIcon はアイコンが画像（パスまたはURL）かどうかを判定する
+func isImageIcon(icon string) bool {
+	return strings.HasPrefix(icon, "http://") || strings.HasPrefix(icon, "https://") || IsImageFile(icon)
+}
+
+// iconMarkdown はアイコンをMarkdownで返す（画像は代替テキストを名前にした画像、それ以外はそのまま）
+func iconMarkdown(icon, name string) string {
+	if icon != "" && isImageIcon(icon) {
+		return fmt.Sprintf("![%s](%s)", name, icon)
+	}
+	return icon
+}
+
+// withIcon はテキストの前にアイコンを付ける（アイコンが無い場合はテキストのみ）
+func withIcon(icon, text string) string {
+	if icon == "" {
+		return text
+	}
+	return icon + " " + text
+}
+
+// icon は課題タイプ・優先度・ステータスのアイコンをMarkdownで返す（アイコンが無い場合は空文字列）
+// 設定に無い課題タイプとダウンロードしていないJiraのアイコンは組み込みのアイコン（優先度・ステータスはアイコンなし）になる
+// attachmentsPath はページから添付ファイルディレクトリへの相対パス
+func (mw *MarkdownWriter) icon(kind, name, attachmentsPath string) string {
+	icon, configured := mw.iconsConfig().lookup(kind, name)
+	if configured && icon == jiraIconValue {
+		if filename, exists := mw.jiraIcons[jiraIconBaseName(kind, name)]; exists {
+			return fmt.Sprintf("![%s](%s%s/%s)", name, attachmentsPath, iconsDirName, url.PathEscape(filename))
+		}
+		configured = false
+	}
+	if !configured {
+		if kind == iconKindIssueType {
+			return getIssueTypeIcon(name)
+		}
+		return ""
+	}
+	return iconMarkdown(icon, name)
+}
+
+// configuredIcon は[display.icons]にアイコンが設定されている場合のみアイコンをMarkdownで返す（設定が無い場合は空文字列）
+// 組み込みのアイコンを付けていなかった箇所（基本情報・サブタスク・関連リンク）で使用する
+func (mw *MarkdownWriter) configuredIcon(kind, name, attachmentsPath string) string {
+	if _, configured := mw.iconsConfig().lookup(kind, name); !configured {
+		return ""
+	}
+	return mw.icon(kind, name, attachmentsPath)
+}
+
+// projectIcon はプロジェクトのアイコンをMarkdownで返す
+func (mw *MarkdownWriter) projectIcon(projectName string) string {
+	icon := mw.iconsConfig().Project
+	if icon == "" {
+		icon = defaultProjectIcon
+	}
+	return iconMarkdown(icon, projectName)
+}
@@ -0,0 +1,244 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// TestMarkdownWriterIcon は[display.icons]の設定に応じたアイコンを確認する
+func TestMarkdownWriterIcon(t *testing.T) {
+	config := createTestConfig()
+	config.Display.Icons = IconsConfig{
+		IssueTypes: map[string]string{
+			"Incident": "🚨",
+			"Spike":    "/icons/spike.svg",
+			"Change":   jiraIconValue,
+			"Bug":      jiraIconValue,
+			"Task":     "",
+		},
+		Priorities: map[string]string{"High": "🔼", "*": "▪️"},
+		Statuses:   map[string]string{"Done": "https://example.com/done.png"},
+	}
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+	mw.jiraIcons = map[string]string{"issuetype_Change": "issuetype_Change.svg"}
+
+	tests := []struct {
+		name     string
+		kind     string
+		value    string
+		expected string
+	}{
+		{"課題タイプ（絵文字）", iconKindIssueType, "Incident", "🚨"},
+		{"課題タイプ（画像のパス）", iconKindIssueType, "Spike", "![Spike](/icons/spike.svg)"},
+		{"課題タイプ（Jiraのアイコン）", iconKindIssueType, "Change", "![Change](../../attachments/icons/issuetype_Change.svg)"},
+		{"課題タイプ（ダウンロードしていないJiraのアイコン）", iconKindIssueType, "Bug", "🐞"},
+		{"課題タイプ（アイコンなし）", iconKindIssueType, "Task", ""},
+		{"課題タイプ（設定なし）", iconKindIssueType, "Story", "📗"},
+		{"優先度", iconKindPriority, "High", "🔼"},
+		{"優先度（*の設定）", iconKindPriority, "Low", "▪️"},
+		{"ステータス（画像のURL）", iconKindStatus, "Done", "![Done](https://example.com/done.png)"},
+		{"ステータス（設定なし）", iconKindStatus, "To Do", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mw.icon(tt.kind, tt.value, issuePageAttachments); got != tt.expected {
+				t.Errorf("icon(%s, %s) = %q, expected %q", tt.kind, tt.value, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestGenerateMarkdown_Icons はパンくずリスト・基本情報・子作業項目・関連リンクで同じアイコンを使用することを確認する
+func TestGenerateMarkdown_Icons(t *testing.T) {
+	config := createTestConfig()
+	config.Display.Icons = IconsConfig{
+		Project:    "/icons/project.svg",
+		IssueTypes: map[string]string{"Incident": "🚨", "Epic": "⚡"},
+		Priorities: map[string]string{"High": "🔼"},
+		Statuses:   map[string]string{"Done": "✅"},
+	}
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+
+	issue := &cloud.Issue{
+		Key: "OPS-2",
+		Fields: &cloud.IssueFields{
+			Summary:  "障害対応",
+			Type:     cloud.IssueType{Name: "Incident"},
+			Status:   &cloud.Status{Name: "Done"},
+			Priority: &cloud.Priority{Name: "High"},
+			Project:  cloud.Project{Key: "OPS", Name: "運用"},
+			IssueLinks: []*cloud.IssueLink{
+				{
+					Type: cloud.IssueLinkType{Outward: "blocks"},
+					OutwardIssue: &cloud.Issue{Key: "OPS-3", Fields: &cloud.IssueFields{
+						Summary: "再発防止",
+						Type:    cloud.IssueType{Name: "Incident"},
+						Status:  &cloud.Status{Name: "Done"},
+					}},
+				},
+			},
+		},
+	}
+	data := &IssueData{
+		Issue:       issue,
+		ParentInfo:  &ParentIssueInfo{Key: "OPS-1", Type: "Epic"},
+		ChildIssues: []ChildIssueInfo{{Key: "OPS-4", Summary: "調査", Status: "Done", Type: "Incident"}},
+	}
+
+	got, err := mw.generateMarkdown(data, nil, FieldNameCache{})
+	if err != nil {
+		t.Fatalf("generateMarkdown() error = %v", err)
+	}
+	for _, expected := range []string{
+		"[![運用](/icons/project.svg) 運用](../) / [⚡ OPS-1](../OPS-1/) / [🚨 OPS-2](../OPS-2/)",
+		"- **課題タイプ**: 🚨 Incident\n",
+		"- **ステータス**: ✅ Done\n",
+		"- **優先度**: 🔼 High\n",
+		"- 🚨 **[OPS-4](../OPS-4/)**: 調査 [✅ Done]\n",
+		"- **blocks**: 🚨 [OPS-3](../OPS-3/) - 再発防止 [✅ Done]\n",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("出力に %q が含まれていません\n%s", expected, got)
+		}
+	}
+}
+
+// TestDownloadJiraIcons はJiraのアイコンのダウンロードと、ダウンロード済みのアイコンの再利用を確認する
+// 認証情報はJiraのホストのアイコンの取得時のみ付ける
+func TestDownloadJiraIcons(t *testing.T) {
+	cdn := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if auth := r.Header.Get("Authorization"); auth != "" {
+			t.Errorf("Jira以外のホストに認証情報が送信されました: %s", auth)
+		}
+		w.Write([]byte("png"))
+	}))
+	defer cdn.Close()
+
+	var server *httptest.Server
+	iconRequests := 0
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/issuetype":
+			json.NewEncoder(w).Encode([]JiraIcon{
+				{Name: "Change", IconURL: server.URL + "/rest/api/2/universal_avatar/view/type/issuetype/avatar/10318"},
+				{Name: "Task", IconURL: server.URL + "/images/icons/task.png"},
+				{Name: "Story", IconURL: cdn.URL + "/icons/story.png"},
+			})
+		case "/rest/api/2/universal_avatar/view/type/issuetype/avatar/10318":
+			iconRequests++
+			if r.Header.Get("Authorization") == "" {
+				t.Error("Jiraのアイコンの取得に認証情報が付いていません")
+			}
+			w.Header().Set("Content-Type", "image/svg+xml;charset=UTF-8")
+			w.Write([]byte("<svg/>"))
+		default:
+			t.Errorf("予期しないリクエスト: %s", r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	attachmentsDir := t.TempDir()
+	config := createTestConfig()
+	config.JIRA = JIRAConfig{URL: server.URL, Email: "test@example.com", APIToken: "test-token"}
+	config.Display.Icons = IconsConfig{IssueTypes: map[string]string{"Change": jiraIconValue, "Story": jiraIconValue}}
+
+	jiraClient, err := NewJIRAClient(context.Background(), &config.JIRA)
+	if err != nil {
+		t.Fatalf("JIRAクライアントの作成に失敗しました: %v", err)
+	}
+	downloader := NewDownloader(context.Background(), attachmentsDir, jiraClient.Authenticator(), RetryConfig{})
+
+	mw := NewMarkdownWriter(context.Background(), "", attachmentsDir, nil, config)
+	mw.DownloadJiraIcons(jiraClient, downloader)
+	for _, name := range []string{"issuetype_Change.svg", "issuetype_Story.png"} {
+		if _, err := os.Stat(filepath.Join(attachmentsDir, "icons", name)); err != nil {
+			t.Fatalf("アイコンが保存されていません: %v", err)
+		}
+	}
+	if got := mw.icon(iconKindIssueType, "Change", subPageAttachments); got != "![Change](../../../attachments/icons/issuetype_Change.svg)" {
+		t.Errorf("icon(Change) = %q", got)
+	}
+
+	// ダウンロード済みのアイコンは再取得せず、JSONからの変換でも使用する
+	mw = NewMarkdownWriter(context.Background(), "", attachmentsDir, nil, config)
+	mw.DownloadJiraIcons(jiraClient, downloader)
+	if iconRequests != 1 {
+		t.Errorf("アイコンの取得回数 = %d, expected 1", iconRequests)
+	}
+	if got := mw.icon(iconKindIssueType, "Change", issuePageAttachments); got != "![Change](../../attachments/icons/issuetype_Change.svg)" {
+		t.Errorf("icon(Change) = %q", got)
+	}
+}
+
+// TestIconExtension はアイコンのファイルの拡張子の判定を確認する
+func TestIconExtension(t *testing.T) {
+	tests := []struct {
+		iconURL     string
+		contentType string
+		expected    string
+	}{
+		{"https://example.atlassian.net/images/icons/priorities/high.svg", "", ".svg"},
+		{"https://example.atlassian.net/images/icons/task.PNG?size=small", "image/svg+xml", ".png"},
+		{"https://example.atlassian.net/rest/api/2/universal_avatar/view/type/issuetype/avatar/10318", "image/svg+xml;charset=UTF-8", ".svg"},
+		{"https://example.atlassian.net/secure/viewavatar?avatarId=10303", "image/gif", ".gif"},
+		{"https://example.atlassian.net/secure/viewavatar?avatarId=10303", "", ".png"},
+	}
+	for _, tt := range tests {
+		if got := iconExtension(tt.iconURL, tt.contentType); got != tt.expected {
+			t.Errorf("iconExtension(%s, %s) = %q, expected %q", tt.iconURL, tt.contentType, got, tt.expected)
+		}
+	}
+}
+
+// TestGenerateMarkdown_IconsNotConfigured は[display.icons]の設定が無い場合に基本情報・サブタスク・関連リンクにアイコンを付けないことを確認する
+func TestGenerateMarkdown_IconsNotConfigured(t *testing.T) {
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
+
+	issue := &cloud.Issue{
+		Key: "OPS-2",
+		Fields: &cloud.IssueFields{
+			Summary: "障害対応",
+			Type:    cloud.IssueType{Name: "Bug"},
+			Status:  &cloud.Status{Name: "Done"},
+			Project: cloud.Project{Key: "OPS", Name: "運用"},
+			Subtasks: []*cloud.Subtasks{
+				{Key: "OPS-5", Fields: cloud.IssueFields{Summary: "修正", Type: cloud.IssueType{Name: "Sub-task"}, Status: &cloud.Status{Name: "Done"}}},
+			},
+			IssueLinks: []*cloud.IssueLink{
+				{
+					Type: cloud.IssueLinkType{Outward: "blocks"},
+					OutwardIssue: &cloud.Issue{Key: "OPS-3", Fields: &cloud.IssueFields{
+						Summary: "再発防止",
+						Type:    cloud.IssueType{Name: "Task"},
+						Status:  &cloud.Status{Name: "Done"},
+					}},
+				},
+			},
+		},
+	}
+
+	got, err := mw.generateMarkdown(&IssueData{Issue: issue}, nil, FieldNameCache{})
+	if err != nil {
+		t.Fatalf("generateMarkdown() error = %v", err)
+	}
+	for _, expected := range []string{
+		"[📦 運用](../) / [🐞 OPS-2](../OPS-2/)",
+		"- **課題タイプ**: Bug\n",
+		"- **ステータス**: Done\n",
+		"- **[OPS-5](../OPS-5/)**: 修正 [Done]\n",
+		"- **blocks**: [OPS-3](../OPS-3/) - 再発防止 [Done]\n",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("出力に %q が含まれていません\n%s", expected, got)
+		}
+	}
+}
@@ -9,19 +9,24 @@ import (
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/andygrunwald/go-jira/v2/cloud"
+	"github.com/andygrunwald/go-jira/v2/onpremise"
 )
 
 // JIRAClient はJIRA APIクライアントのラッパー
 type JIRAClient struct {
 	client     *cloud.Client
+	onpremise  *onpremise.Client // Data Centerモードの場合のみ設定（clientはnil）
 	ctx        context.Context
 	httpClient *http.Client
 	baseURL    string
+	siteURL    string // サイトのURL（jira.url、jira.cloud_idの場合はbaseURLがゲートウェイのURLになる）
 	email      string
 	apiToken   string
+	auth       Authenticator // nilの場合はemailとapiTokenのBasic認証
 }
 
 // JQLSearchRequest は新しい /rest/api/3/search/jql エンドポイント用のリクエスト構造体
@@ -41,31 +46,76 @@ type JQLSearchResponse struct {
 }
 
 // NewJIRAClient は新しいJIRAクライアントを作成する
-func NewJIRAClient(config *JIRAConfig) (*JIRAClient, error) {
-	// Basic認証用のトランスポート設定
-	tp := cloud.BasicAuthTransport{
-		Username: config.Email,
-		APIToken: config.APIToken,
+// 認証方式はjira.authに従う（Basic認証、Bearer認証、OAuth 2.0）
+// jira.cloud_idが設定されている場合はapi.atlassian.comのゲートウェイ経由で接続する
+// jira.deploymentが"datacenter"の場合はData Center / Server用のクライアントを作成する
+// ctxはすべてのAPIリクエストに使用し、キャンセルすると処理中のリクエストを中断する
+func NewJIRAClient(ctx context.Context, config *JIRAConfig) (*JIRAClient, error) {
+	// レート制限（429）や一時エラー（503等）はリトライ用トランスポートで再送する
+	retryTransport := NewRetryTransport(http.DefaultTransport, config.Retry)
+	httpClient := &http.Client{Transport: retryTransport}
+	auth := NewAuthenticator(config, httpClient)
+
+	// go-jiraのクライアントはリクエストごとにAuthTransportで認証情報を設定する
+	authClient := &http.Client{Transport: &AuthTransport{Auth: auth, Transport: retryTransport}}
+
+	if config.IsDataCenter() {
+		return newDataCenterClient(ctx, config, auth, authClient, httpClient)
 	}
 
 	// JIRAクライアントの作成
-	client, err := cloud.NewClient(config.URL, tp.Client())
+	client, err := cloud.NewClient(config.APIBaseURL(), authClient)
 	if err != nil {
 		return nil, fmt.Errorf("JIRAクライアントの作成に失敗しました: %w", err)
 	}
 
 	return &JIRAClient{
 		client:     client,
-		ctx:        context.Background(),
-		httpClient: tp.Client(),
-		baseURL:    config.URL,
+		ctx:        ctx,
+		httpClient: httpClient,
+		baseURL:    config.APIBaseURL(),
+		siteURL:    config.URL,
 		email:      config.Email,
 		apiToken:   config.APIToken,
+		auth:       auth,
 	}, nil
 }
 
+// Authenticator はクライアントの認証方式を返す（添付ファイルのダウンロードで共有する）
+func (jc *JIRAClient) Authenticator() Authenticator {
+	if jc.auth != nil {
+		return jc.auth
+	}
+	return &BasicAuthenticator{Email: jc.email, APIToken: jc.apiToken}
+}
+
+// IsJiraURL はURLのスキームとホストがJiraのAPI（baseURL）またはサイト（jira.url）と一致するかを判定する
+// 認証情報を付けてURLを取得してよいかの判定に使用する
+func (jc *JIRAClient) IsJiraURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	for _, jiraURL := range []string{jc.baseURL, jc.siteURL} {
+		base, err := url.Parse(jiraURL)
+		if err == nil && base.Host != "" && strings.EqualFold(u.Scheme, base.Scheme) && strings.EqualFold(u.Host, base.Host) {
+			return true
+		}
+	}
+	return false
+}
+
+// setAuth はリクエストに認証情報を設定する
+func (jc *JIRAClient) setAuth(req *http.Request) error {
+	return jc.Authenticator().Authorize(req)
+}
+
 // GetIssue は指定された課題キーまたはIDの詳細情報を取得する
 func (jc *JIRAClient) GetIssue(issueKey string) (*cloud.Issue, error) {
+	if jc.isDataCenter() {
+		return jc.getIssueDataCenter(issueKey)
+	}
+
 	// expandパラメータで追加情報を取得
 	// - renderedFields: HTMLレンダリング済みの項目値
 	issue, resp, err := jc.client.Issue.Get(jc.ctx, issueKey, &cloud.GetQueryOptions{
@@ -93,100 +143,96 @@ func (jc *JIRAClient) GetIssue(issueKey string) (*cloud.Issue, error) {
 	return issue, nil
 }
 
+// defaultMaxSearchPages はSearchJQLV3で取得する最大ページ数（無限ループ防止）
+const defaultMaxSearchPages = 100
+
 // SearchJQLV3 は新しい /rest/api/3/search/jql エンドポイントを使用してJQL検索を実行する（GETメソッド）
 // 課題キーのリストのみを返す（軽量な検索）
 func (jc *JIRAClient) SearchJQLV3(jql string, maxResults int) ([]string, error) {
-	allIssueKeys := []string{}
-	nextPageToken := ""
-	seenTokens := make(map[string]bool)
-	maxPages := 100 // 無限ループ防止
-
-	for page := 0; page < maxPages; page++ {
-		// URLクエリパラメータの構築
-		apiURL := fmt.Sprintf("%s/rest/api/3/search/jql", jc.baseURL)
-
-		// JQLの値だけをURLエンコード
-		encodedJQL := url.QueryEscape(jql)
-
-		// クエリ文字列を手動で構築
-		// id,keyのみを取得して軽量なレスポンスにする
-		params := fmt.Sprintf("jql=%s&maxResults=%d&fields=id,key",
-			encodedJQL, maxResults)
-
-		// NextPageTokenがある場合は追加（値だけエンコード）
-		if nextPageToken != "" {
-			encodedToken := url.QueryEscape(nextPageToken)
-			params += fmt.Sprintf("&nextPageToken=%s", encodedToken)
-		}
+	return jc.searchJQLKeys(jql, maxResults, defaultMaxSearchPages)
+}
 
-		// URL形式: ?jql=project%3DSCRUM&maxResults=50&fields=*all&expand=renderedFields
-		requestURL := fmt.Sprintf("%s?%s", apiURL, params)
+// SearchAllJQLV3 はページ数の上限なしでJQL検索を実行し、最終ページまでの全課題キーを返す
+// NextPageTokenの重複検出により無限ループは防止される
+func (jc *JIRAClient) SearchAllJQLV3(jql string, pageSize int) ([]string, error) {
+	return jc.searchJQLKeys(jql, pageSize, 0)
+}
 
-		// HTTPリクエストの作成（GETメソッド）
-		req, err := http.NewRequestWithContext(jc.ctx, "GET", requestURL, nil)
-		if err != nil {
-			return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %w", err)
+// searchJQLKeys はJQL検索をページングしながら実行し、課題キーのリストを返す
+// maxPagesが0以下の場合はページ数の上限を設けない
+func (jc *JIRAClient) searchJQLKeys(jql string, maxResults int, maxPages int) ([]string, error) {
+	allIssueKeys := []string{}
+	opts := jqlSearchOptions{
+		apiPath:    "/rest/api/3/search/jql",
+		fields:     "id,key", // id,keyのみを取得して軽量なレスポンスにする
+		maxResults: maxResults,
+		maxPages:   maxPages,
+	}
+	err := jc.searchJQLPages(jql, opts, func(issues []cloud.Issue) error {
+		for _, issue := range issues {
+			allIssueKeys = append(allIssueKeys, issue.Key)
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return allIssueKeys, nil
+}
 
-		// ヘッダーの設定
-		req.Header.Set("Accept", "application/json")
-		req.SetBasicAuth(jc.email, jc.apiToken)
+// SearchIssuesJQL はJQL検索で全フィールドを含む課題をページ単位で取得し、handleに渡す
+// 課題ごとにGetIssueを呼び出す必要がないため、大量の課題を出力する際のAPI呼び出し回数を削減できる
+// maxPagesが0以下の場合はページ数の上限を設けない
+// 変更履歴は課題に含まれる分が切り詰められており課題ごとにGetChangelogで全件を取得するため、expandには含めない
+//
+// /rest/api/3 ではdescriptionやコメント本文がADF形式で返りcloud.Issueにデコードできないため、
+// 同じ拡張検索APIのv2版（Wiki記法で返る）を使用する
+func (jc *JIRAClient) SearchIssuesJQL(jql string, pageSize int, maxPages int, handle func(issues []cloud.Issue) error) error {
+	opts := jqlSearchOptions{
+		apiPath:    "/rest/api/2/search/jql",
+		fields:     "*all",
+		expand:     "renderedFields",
+		maxResults: pageSize,
+		maxPages:   maxPages,
+	}
+	return jc.searchJQLPages(jql, opts, handle)
+}
 
-		slog.Info("JQL検索リクエスト",
-			"method", "GET",
-			"url", requestURL,
-			"page", page+1,
-			"maxResults", maxResults,
-			"headers", req.Header)
+// jqlSearchOptions は拡張JQL検索APIのリクエストパラメータ
+type jqlSearchOptions struct {
+	apiPath    string // エンドポイントのパス（例: /rest/api/3/search/jql）
+	fields     string // 取得するフィールド（カンマ区切り）
+	expand     string // expandパラメータ（空の場合は指定しない）
+	maxResults int    // 1ページあたりの取得件数
+	maxPages   int    // 最大ページ数（0以下の場合は上限なし）
+}
 
-		// リクエストの実行
-		resp, err := jc.httpClient.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("HTTPリクエストの実行に失敗しました: %w", err)
-		}
-		defer resp.Body.Close()
+// searchJQLPages はJQL検索をページングしながら実行し、ページごとの課題をhandleに渡す
+// handleがエラーを返した場合はその時点で検索を中断する
+func (jc *JIRAClient) searchJQLPages(jql string, opts jqlSearchOptions, handle func(issues []cloud.Issue) error) error {
+	if jc.isDataCenter() {
+		return jc.searchJQLPagesDataCenter(jql, opts, handle)
+	}
 
-		// ステータスコードの確認
-		if resp.StatusCode != http.StatusOK {
-			bodyBytes, _ := io.ReadAll(resp.Body)
-			slog.Error("JQL検索エラー",
-				"status", resp.StatusCode,
-				"body", string(bodyBytes))
-			return nil, fmt.Errorf("JQL検索に失敗しました。ステータスコード: %d, レスポンス: %s", resp.StatusCode, string(bodyBytes))
-		}
+	nextPageToken := ""
+	seenTokens := make(map[string]bool)
+	totalIssues := 0
 
-		// レスポンスボディを読み取り
-		bodyBytes, err := io.ReadAll(resp.Body)
+	for page := 0; opts.maxPages <= 0 || page < opts.maxPages; page++ {
+		searchResp, err := jc.fetchJQLPage(jql, opts, nextPageToken, page)
 		if err != nil {
-			slog.Error("レスポンスボディ読み取りエラー",
-				"error", err)
-			return nil, fmt.Errorf("レスポンスボディの読み取りに失敗しました: %w", err)
-		}
-
-		// デバッグ用：レスポンスボディをログ出力
-		slog.Debug("レスポンスボディ",
-			"body", string(bodyBytes))
-
-		// レスポンスのパース
-		var searchResp JQLSearchResponse
-		if err := json.Unmarshal(bodyBytes, &searchResp); err != nil {
-			slog.Error("レスポンスパースエラー",
-				"error", err,
-				"bodyPreview", string(bodyBytes[:min(500, len(bodyBytes))]))
-			return nil, fmt.Errorf("レスポンスのパースに失敗しました: %w", err)
+			return err
 		}
 
+		totalIssues += len(searchResp.Issues)
 		slog.Info("JQL検索レスポンス",
-			"status", resp.StatusCode,
-			"headers", resp.Header,
 			"count", len(searchResp.Issues),
 			"isLast", searchResp.IsLast,
 			"hasNextToken", searchResp.NextPageToken != "",
-			"totalIssues", len(allIssueKeys)+len(searchResp.Issues))
+			"totalIssues", totalIssues)
 
-		// 取得した課題キーを追加
-		for _, issue := range searchResp.Issues {
-			allIssueKeys = append(allIssueKeys, issue.Key)
+		if err := handle(searchResp.Issues); err != nil {
+			return err
 		}
 
 		// 終了条件の判定
@@ -208,7 +254,90 @@ func (jc *JIRAClient) SearchJQLV3(jql string, maxResults int) ([]string, error)
 		}
 	}
 
-	return allIssueKeys, nil
+	return nil
+}
+
+// fetchJQLPage はJQL検索の1ページ分を取得する（GETメソッド）
+func (jc *JIRAClient) fetchJQLPage(jql string, opts jqlSearchOptions, nextPageToken string, page int) (*JQLSearchResponse, error) {
+	// URLクエリパラメータの構築
+	apiURL := jc.baseURL + opts.apiPath
+
+	// JQLの値だけをURLエンコード
+	encodedJQL := url.QueryEscape(jql)
+
+	// クエリ文字列を手動で構築
+	params := fmt.Sprintf("jql=%s&maxResults=%d&fields=%s",
+		encodedJQL, opts.maxResults, url.QueryEscape(opts.fields))
+	if opts.expand != "" {
+		params += fmt.Sprintf("&expand=%s", url.QueryEscape(opts.expand))
+	}
+
+	// NextPageTokenがある場合は追加（値だけエンコード）
+	if nextPageToken != "" {
+		encodedToken := url.QueryEscape(nextPageToken)
+		params += fmt.Sprintf("&nextPageToken=%s", encodedToken)
+	}
+
+	// URL形式: ?jql=project%3DSCRUM&maxResults=50&fields=*all&expand=renderedFields
+	requestURL := fmt.Sprintf("%s?%s", apiURL, params)
+
+	// HTTPリクエストの作成（GETメソッド）
+	req, err := http.NewRequestWithContext(jc.ctx, "GET", requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの作成に失敗しました: %w", err)
+	}
+
+	// ヘッダーの設定
+	req.Header.Set("Accept", "application/json")
+	if err := jc.setAuth(req); err != nil {
+		return nil, err
+	}
+
+	slog.Info("JQL検索リクエスト",
+		"method", "GET",
+		"url", requestURL,
+		"page", page+1,
+		"maxResults", opts.maxResults,
+		"headers", req.Header)
+
+	// リクエストの実行
+	resp, err := jc.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("HTTPリクエストの実行に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// ステータスコードの確認
+	if resp.StatusCode != http.StatusOK {
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		slog.Error("JQL検索エラー",
+			"status", resp.StatusCode,
+			"body", string(bodyBytes))
+		return nil, fmt.Errorf("JQL検索に失敗しました。ステータスコード: %d, レスポンス: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	// レスポンスボディを読み取り
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		slog.Error("レスポンスボディ読み取りエラー",
+			"error", err)
+		return nil, fmt.Errorf("レスポンスボディの読み取りに失敗しました: %w", err)
+	}
+
+	// デバッグ用：レスポンスボディをログ出力
+	slog.Debug("レスポンスボディ",
+		"body", string(bodyBytes))
+
+	// レスポンスのパース
+	var searchResp JQLSearchResponse
+	if err := json.Unmarshal(bodyBytes, &searchResp); err != nil {
+		slog.Error("レスポンスパースエラー",
+			"error", err,
+			"bodyPreview", string(bodyBytes[:min(500, len(bodyBytes))]))
+		return nil, fmt.Errorf("レスポンスのパースに失敗しました: %w", err)
+	}
+
+	return &searchResp, nil
 }
 
 // GetIssuesByJQL はJQLクエリに基づいて課題キーのリストを取得する（新しいAPIエンドポイントを使用）
@@ -222,23 +351,536 @@ func (jc *JIRAClient) GetIssuesByJQL(jql string, maxResults int) ([]string, erro
 	return issueKeys, nil
 }
 
-// GetChildIssues は指定された課題の子課題キーを取得する
-func (jc *JIRAClient) GetChildIssues(parentKey string, maxResults int) ([]string, error) {
-	// JQLクエリで親課題を指定して子課題を取得
-	jql := fmt.Sprintf(`parent = "%s"`, parentKey)
-	issueKeys, err := jc.GetIssuesByJQL(jql, maxResults)
+// GetAllIssuesByJQL はJQLクエリに一致する全課題キーを取得する（ページ数の上限なし）
+func (jc *JIRAClient) GetAllIssuesByJQL(jql string, pageSize int) ([]string, error) {
+	issueKeys, err := jc.SearchAllJQLV3(jql, pageSize)
 	if err != nil {
-		slog.Warn("子課題の取得に失敗しました",
-			"parentKey", parentKey,
-			"error", err)
-		return []string{}, nil // 子課題が存在しない場合は空配列を返す
+		return nil, fmt.Errorf("JQL検索に失敗しました: %w", err)
 	}
 
 	return issueKeys, nil
 }
 
+// GetChildIssueSummaries は指定された課題の子課題を、一覧表示に必要なフィールドのみで取得する
+// 子課題ごとにGetIssueを呼び出さず、1回の検索（ページング含む）で取得する
+func (jc *JIRAClient) GetChildIssueSummaries(parentKey string, rankFieldID string) ([]cloud.Issue, error) {
+	jql := fmt.Sprintf(`parent = "%s"`, parentKey)
+	fields := "summary,status,issuetype"
+	if rankFieldID != "" {
+		fields += "," + rankFieldID
+	}
+	opts := jqlSearchOptions{
+		apiPath:    "/rest/api/3/search/jql",
+		fields:     fields,
+		maxResults: 100,
+		maxPages:   defaultMaxSearchPages,
+	}
+
+	var children []cloud.Issue
+	err := jc.searchJQLPages(jql, opts, func(issues []cloud.Issue) error {
+		children = append(children, issues...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("子課題の取得に失敗しました: %w", err)
+	}
+	return children, nil
+}
+
+// SearchIssueRelations はJQLに一致する課題を、キー・プロジェクト・親課題のフィールドのみで全件取得する
+// 差分同期で再出力する課題（更新された課題とその親子課題）の判定に使用する
+func (jc *JIRAClient) SearchIssueRelations(jql string, pageSize int) ([]cloud.Issue, error) {
+	opts := jqlSearchOptions{
+		apiPath:    "/rest/api/3/search/jql",
+		fields:     "project,parent",
+		maxResults: pageSize,
+	}
+
+	var result []cloud.Issue
+	err := jc.searchJQLPages(jql, opts, func(issues []cloud.Issue) error {
+		result = append(result, issues...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("JQL検索に失敗しました: %w", err)
+	}
+	return result, nil
+}
+
+// ResolveIssueKey は課題の現在のキーを返す
+// 別プロジェクトへ移動された課題は古いキーでも取得でき、移動先のキーが返る
+// 課題が存在しない（削除された、または参照権限がない）場合は空文字列を返す
+func (jc *JIRAClient) ResolveIssueKey(issueKey string) (string, error) {
+	apiPath := "/rest/api/3/issue/"
+	if jc.isDataCenter() {
+		apiPath = "/rest/api/2/issue/"
+	}
+	requestURL := fmt.Sprintf("%s%s%s?fields=project", jc.baseURL, apiPath, url.PathEscape(issueKey))
+
+	req, err := http.NewRequestWithContext(jc.ctx, "GET", requestURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの作成に失敗しました: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if err := jc.setAuth(req); err != nil {
+		return "", err
+	}
+
+	slog.Debug("APIリクエスト", "method", "GET", "url", requestURL)
+
+	resp, err := jc.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("HTTPリクエストの実行に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", fmt.Errorf("レスポンスボディの読み取りに失敗しました: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", nil
+	default:
+		return "", fmt.Errorf("課題 %s の確認に失敗しました。ステータスコード: %d, レスポンス: %s", issueKey, resp.StatusCode, string(bodyBytes))
+	}
+
+	var issue struct {
+		Key string `json:"key"`
+	}
+	if err := json.Unmarshal(bodyBytes, &issue); err != nil {
+		return "", fmt.Errorf("レスポンスのパースに失敗しました: %w", err)
+	}
+	return issue.Key, nil
+}
+
+// ChangelogPage は /rest/api/3/issue/{key}/changelog のレスポンス構造体（1ページ分）
+type ChangelogPage struct {
+	StartAt    int                      `json:"startAt"`
+	MaxResults int                      `json:"maxResults"`
+	Total      int                      `json:"total"`
+	IsLast     bool                     `json:"isLast"`
+	Values     []cloud.ChangelogHistory `json:"values"`
+}
+
+// changelogPageSize はGetChangelogで1回のリクエストで取得する件数
+const changelogPageSize = 100
+
+// GetChangelog は課題の変更履歴をページングしながらすべて取得する
+// 課題取得時のexpand=changelogは先頭の100件までに切り詰められるため、専用のエンドポイントを使用する
+func (jc *JIRAClient) GetChangelog(issueKey string) ([]cloud.ChangelogHistory, error) {
+	if jc.isDataCenter() {
+		return jc.getChangelogDataCenter(issueKey)
+	}
+
+	histories := []cloud.ChangelogHistory{}
+	startAt := 0
+
+	for {
+		requestURL := fmt.Sprintf("%s/rest/api/3/issue/%s/changelog?startAt=%d&maxResults=%d",
+			jc.baseURL, url.PathEscape(issueKey), startAt, changelogPageSize)
+
+		var page ChangelogPage
+		if err := jc.getJSON(requestURL, &page); err != nil {
+			return nil, fmt.Errorf("課題 %s の変更履歴の取得に失敗しました: %w", issueKey, err)
+		}
+		histories = append(histories, page.Values...)
+
+		// 終了条件の判定（isLastが返らない場合はtotalで判定する）
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 || (page.Total > 0 && startAt >= page.Total) {
+			break
+		}
+	}
+
+	slog.Debug("変更履歴取得成功",
+		"issueKey", issueKey,
+		"count", len(histories))
+
+	return histories, nil
+}
+
+// IssueComment は課題のコメント1件を表す（公開範囲の情報を含む）
+type IssueComment struct {
+	cloud.Comment
+	// JSDPublic はJira Service Managementで顧客に公開されているかどうか（JSM以外のプロジェクトではnil）
+	JSDPublic *bool `json:"jsdPublic,omitempty"`
+	// RenderedBody はJiraがレンダリングした本文のHTML（display.source = "rendered" / "auto" で使用）
+	RenderedBody string `json:"renderedBody,omitempty"`
+}
+
+// CommentPage は /rest/api/2/issue/{key}/comment のレスポンス構造体（1ページ分）
+type CommentPage struct {
+	StartAt    int            `json:"startAt"`
+	MaxResults int            `json:"maxResults"`
+	Total      int            `json:"total"`
+	Comments   []IssueComment `json:"comments"`
+}
+
+// commentPageSize はGetCommentsで1回のリクエストで取得する件数
+const commentPageSize = 100
+
+// GetComments は課題のコメントを作成日時の昇順でページングしながらすべて取得する
+// 課題取得時に含まれるコメントは件数が制限されるため、専用のエンドポイントを使用する
+//
+// /rest/api/3 ではコメント本文がADF形式で返るため、Wiki記法で返るv2版を使用する
+// 変換時にdisplay.sourceを切り替えられるよう、レンダリング済みのHTML（renderedBody）も取得する
+func (jc *JIRAClient) GetComments(issueKey string) ([]IssueComment, error) {
+	comments := []IssueComment{}
+	startAt := 0
+
+	for {
+		requestURL := fmt.Sprintf("%s/rest/api/2/issue/%s/comment?startAt=%d&maxResults=%d&orderBy=created&expand=renderedBody",
+			jc.baseURL, url.PathEscape(issueKey), startAt, commentPageSize)
+
+		var page CommentPage
+		if err := jc.getJSON(requestURL, &page); err != nil {
+			return nil, fmt.Errorf("課題 %s のコメントの取得に失敗しました: %w", issueKey, err)
+		}
+		comments = append(comments, page.Comments...)
+
+		startAt += len(page.Comments)
+		if len(page.Comments) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+
+	slog.Debug("コメント取得成功",
+		"issueKey", issueKey,
+		"count", len(comments))
+
+	return comments, nil
+}
+
+// GetIssueADF は課題の説明・コメント本文・指定したフィールドをADF形式（/rest/api/3）で取得する
+// 指定したフィールド（複数行テキストのカスタムフィールド）の値がADFの場合は、issueのカスタムフィールドの値をADFで置き換える
+func (jc *JIRAClient) GetIssueADF(issue *cloud.Issue, fieldIDs []string) (*IssueADF, error) {
+	requestURL := fmt.Sprintf("%s/rest/api/3/issue/%s?fields=%s",
+		jc.baseURL, url.PathEscape(issue.Key), url.QueryEscape(strings.Join(append([]string{"description"}, fieldIDs...), ",")))
+
+	var resp struct {
+		Fields map[string]interface{} `json:"fields"`
+	}
+	if err := jc.getJSON(requestURL, &resp); err != nil {
+		return nil, fmt.Errorf("課題 %s のADFの取得に失敗しました: %w", issue.Key, err)
+	}
+
+	result := &IssueADF{Comments: make(map[string]*ADFNode)}
+	if doc, ok := parseADF(resp.Fields["description"]); ok {
+		result.Description = doc
+	}
+	for _, fieldID := range fieldIDs {
+		if value, ok := resp.Fields[fieldID]; ok {
+			if _, isADF := parseADF(value); isADF && issue.Fields != nil {
+				if issue.Fields.Unknowns == nil {
+					issue.Fields.Unknowns = make(map[string]interface{})
+				}
+				issue.Fields.Unknowns[fieldID] = value
+			}
+		}
+	}
+
+	// コメント本文（/rest/api/3 ではADFで返る）
+	startAt := 0
+	for {
+		requestURL := fmt.Sprintf("%s/rest/api/3/issue/%s/comment?startAt=%d&maxResults=%d&orderBy=created",
+			jc.baseURL, url.PathEscape(issue.Key), startAt, commentPageSize)
+
+		var page struct {
+			Total    int `json:"total"`
+			Comments []struct {
+				ID   string   `json:"id"`
+				Body *ADFNode `json:"body"`
+			} `json:"comments"`
+		}
+		if err := jc.getJSON(requestURL, &page); err != nil {
+			return nil, fmt.Errorf("課題 %s のコメントのADFの取得に失敗しました: %w", issue.Key, err)
+		}
+		for _, comment := range page.Comments {
+			if comment.Body != nil && comment.Body.Type == "doc" {
+				result.Comments[comment.ID] = comment.Body
+			}
+		}
+
+		startAt += len(page.Comments)
+		if len(page.Comments) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+
+	slog.Debug("ADF取得成功",
+		"issueKey", issue.Key,
+		"description", result.Description != nil,
+		"comments", len(result.Comments))
+
+	return result, nil
+}
+
+// worklogPageSize はGetWorklogsで1回のリクエストで取得する件数
+const worklogPageSize = 100
+
+// GetWorklogs は課題の作業ログをページングしながらすべて取得する
+// 課題取得時に含まれる作業ログは先頭の20件までに切り詰められるため、専用のエンドポイントを使用する
+//
+// /rest/api/3 では作業ログのコメントがADF形式で返るため、Wiki記法で返るv2版を使用する
+func (jc *JIRAClient) GetWorklogs(issueKey string) ([]cloud.WorklogRecord, error) {
+	worklogs := []cloud.WorklogRecord{}
+	startAt := 0
+
+	for {
+		requestURL := fmt.Sprintf("%s/rest/api/2/issue/%s/worklog?startAt=%d&maxResults=%d",
+			jc.baseURL, url.PathEscape(issueKey), startAt, worklogPageSize)
+
+		var page cloud.Worklog
+		if err := jc.getJSON(requestURL, &page); err != nil {
+			return nil, fmt.Errorf("課題 %s の作業ログの取得に失敗しました: %w", issueKey, err)
+		}
+		worklogs = append(worklogs, page.Worklogs...)
+
+		startAt += len(page.Worklogs)
+		if len(page.Worklogs) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+
+	slog.Debug("作業ログ取得成功",
+		"issueKey", issueKey,
+		"count", len(worklogs))
+
+	return worklogs, nil
+}
+
+// VersionPage は /rest/api/2/project/{key}/version のレスポンス構造体（1ページ分）
+type VersionPage struct {
+	StartAt    int             `json:"startAt"`
+	MaxResults int             `json:"maxResults"`
+	Total      int             `json:"total"`
+	IsLast     bool            `json:"isLast"`
+	Values     []cloud.Version `json:"values"`
+}
+
+// versionPageSize はGetProjectVersionsで1回のリクエストで取得する件数
+const versionPageSize = 50
+
+// GetProjectVersions はプロジェクトのバージョンをJIRAでの並び順にページングしながらすべて取得する
+func (jc *JIRAClient) GetProjectVersions(projectKey string) ([]cloud.Version, error) {
+	versions := []cloud.Version{}
+	startAt := 0
+
+	for {
+		requestURL := fmt.Sprintf("%s/rest/api/2/project/%s/version?startAt=%d&maxResults=%d&orderBy=sequence",
+			jc.baseURL, url.PathEscape(projectKey), startAt, versionPageSize)
+
+		var page VersionPage
+		if err := jc.getJSON(requestURL, &page); err != nil {
+			return nil, fmt.Errorf("プロジェクト %s のバージョンの取得に失敗しました: %w", projectKey, err)
+		}
+		versions = append(versions, page.Values...)
+
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 || (page.Total > 0 && startAt >= page.Total) {
+			break
+		}
+	}
+
+	slog.Debug("バージョン取得成功",
+		"project", projectKey,
+		"count", len(versions))
+
+	return versions, nil
+}
+
+// GetVersionIssueSummaries は修正バージョンに指定された課題を、一覧表示に必要なフィールドのみで取得する
+func (jc *JIRAClient) GetVersionIssueSummaries(projectKey string, versionID string) ([]cloud.Issue, error) {
+	jql := fmt.Sprintf(`project = "%s" AND fixVersion = %s ORDER BY key ASC`, projectKey, versionID)
+	opts := jqlSearchOptions{
+		apiPath:    "/rest/api/3/search/jql",
+		fields:     "summary,status,issuetype",
+		maxResults: 100,
+		maxPages:   0,
+	}
+
+	var issues []cloud.Issue
+	err := jc.searchJQLPages(jql, opts, func(page []cloud.Issue) error {
+		issues = append(issues, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("バージョン %s の課題の取得に失敗しました: %w", versionID, err)
+	}
+	return issues, nil
+}
+
+// SprintInfo はスプリントの情報（/rest/agile/1.0 のスプリント、および課題のスプリントフィールドの値）
+type SprintInfo struct {
+	ID            int    `json:"id"`
+	Name          string `json:"name"`
+	State         string `json:"state"`                   // future, active, closed
+	BoardID       int    `json:"boardId,omitempty"`       // 課題のスプリントフィールドに含まれるボードID
+	OriginBoardID int    `json:"originBoardId,omitempty"` // Agile APIが返すスプリント作成元のボードID
+	Goal          string `json:"goal,omitempty"`
+	StartDate     string `json:"startDate,omitempty"`
+	EndDate       string `json:"endDate,omitempty"`
+	CompleteDate  string `json:"completeDate,omitempty"`
+}
+
+// SprintPage は /rest/agile/1.0/board/{id}/sprint のレスポンス構造体（1ページ分）
+type SprintPage struct {
+	StartAt    int          `json:"startAt"`
+	MaxResults int          `json:"maxResults"`
+	IsLast     bool         `json:"isLast"`
+	Values     []SprintInfo `json:"values"`
+}
+
+// SprintIssuePage は /rest/agile/1.0/sprint/{id}/issue のレスポンス構造体（1ページ分）
+type SprintIssuePage struct {
+	StartAt    int           `json:"startAt"`
+	MaxResults int           `json:"maxResults"`
+	Total      int           `json:"total"`
+	Issues     []cloud.Issue `json:"issues"`
+}
+
+// agilePageSize はAgile APIで1回のリクエストで取得する件数（Agile APIの上限は50）
+const agilePageSize = 50
+
+// sprintIssueFields はスプリントの課題一覧で取得するフィールド
+const sprintIssueFields = "summary,status,issuetype,project,resolutiondate"
+
+// GetBoards はプロジェクトに関連するスクラムボードをすべて取得する
+func (jc *JIRAClient) GetBoards(projectKey string) ([]cloud.Board, error) {
+	boards := []cloud.Board{}
+	startAt := 0
+
+	for {
+		requestURL := fmt.Sprintf("%s/rest/agile/1.0/board?projectKeyOrId=%s&type=scrum&startAt=%d&maxResults=%d",
+			jc.baseURL, url.QueryEscape(projectKey), startAt, agilePageSize)
+
+		var page cloud.BoardsList
+		if err := jc.getJSON(requestURL, &page); err != nil {
+			return nil, fmt.Errorf("プロジェクト %s のボードの取得に失敗しました: %w", projectKey, err)
+		}
+		boards = append(boards, page.Values...)
+
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+	}
+
+	slog.Debug("ボード取得成功",
+		"project", projectKey,
+		"count", len(boards))
+
+	return boards, nil
+}
+
+// GetBoardSprints はボードのスプリント（未開始・進行中・完了）をすべて取得する
+func (jc *JIRAClient) GetBoardSprints(boardID int) ([]SprintInfo, error) {
+	sprints := []SprintInfo{}
+	startAt := 0
+
+	for {
+		requestURL := fmt.Sprintf("%s/rest/agile/1.0/board/%d/sprint?startAt=%d&maxResults=%d",
+			jc.baseURL, boardID, startAt, agilePageSize)
+
+		var page SprintPage
+		if err := jc.getJSON(requestURL, &page); err != nil {
+			return nil, fmt.Errorf("ボード %d のスプリントの取得に失敗しました: %w", boardID, err)
+		}
+		sprints = append(sprints, page.Values...)
+
+		startAt += len(page.Values)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+	}
+
+	slog.Debug("スプリント取得成功",
+		"boardId", boardID,
+		"count", len(sprints))
+
+	return sprints, nil
+}
+
+// GetSprintIssues はスプリントに含まれる課題をすべて取得する（一覧表示に必要なフィールドのみ）
+func (jc *JIRAClient) GetSprintIssues(sprintID int) ([]cloud.Issue, error) {
+	issues := []cloud.Issue{}
+	startAt := 0
+
+	for {
+		requestURL := fmt.Sprintf("%s/rest/agile/1.0/sprint/%d/issue?fields=%s&startAt=%d&maxResults=%d",
+			jc.baseURL, sprintID, url.QueryEscape(sprintIssueFields), startAt, agilePageSize)
+
+		var page SprintIssuePage
+		if err := jc.getJSON(requestURL, &page); err != nil {
+			return nil, fmt.Errorf("スプリント %d の課題の取得に失敗しました: %w", sprintID, err)
+		}
+		issues = append(issues, page.Issues...)
+
+		startAt += len(page.Issues)
+		if len(page.Issues) == 0 || startAt >= page.Total {
+			break
+		}
+	}
+
+	slog.Debug("スプリントの課題取得成功",
+		"sprintId", sprintID,
+		"count", len(issues))
+
+	return issues, nil
+}
+
+// getJSON はGETリクエストを送信し、レスポンスのJSONをvにデコードする
+func (jc *JIRAClient) getJSON(requestURL string, v interface{}) error {
+	req, err := http.NewRequestWithContext(jc.ctx, "GET", requestURL, nil)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストの作成に失敗しました: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if err := jc.setAuth(req); err != nil {
+		return err
+	}
+
+	slog.Debug("APIリクエスト", "method", "GET", "url", requestURL)
+
+	resp, err := jc.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTPリクエストの実行に失敗しました: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("レスポンスボディの読み取りに失敗しました: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		slog.Debug("APIエラーレスポンス",
+			"url", requestURL,
+			"status", resp.StatusCode,
+			"body", string(bodyBytes))
+		return fmt.Errorf("ステータスコード: %d, レスポンス: %s", resp.StatusCode, string(bodyBytes))
+	}
+
+	if err := json.Unmarshal(bodyBytes, v); err != nil {
+		return fmt.Errorf("レスポンスのパースに失敗しました: %w", err)
+	}
+	return nil
+}
+
 // GetFieldList は全フィールド情報を取得する
 func (jc *JIRAClient) GetFieldList() ([]cloud.Field, error) {
+	if jc.isDataCenter() {
+		var fields []cloud.Field
+		if _, err := jc.getOnPremise("rest/api/2/field", &fields); err != nil {
+			return nil, fmt.Errorf("フィールドリストの取得に失敗しました: %w", err)
+		}
+		return fields, nil
+	}
+
 	fields, _, err := jc.client.Field.GetList(jc.ctx)
 	if err != nil {
 		return nil, fmt.Errorf("フィールドリストの取得に失敗しました: %w", err)
@@ -246,8 +888,26 @@ func (jc *JIRAClient) GetFieldList() ([]cloud.Field, error) {
 	return fields, nil
 }
 
+// GetIcons は課題タイプ・優先度・ステータス（kind）の名前とアイコンのURLの一覧を取得する
+func (jc *JIRAClient) GetIcons(kind string) ([]JiraIcon, error) {
+	var icons []JiraIcon
+	requestURL := fmt.Sprintf("%s/rest/api/2/%s", jc.baseURL, kind)
+	if err := jc.getJSON(requestURL, &icons); err != nil {
+		return nil, fmt.Errorf("%s の一覧の取得に失敗しました: %w", kind, err)
+	}
+	return icons, nil
+}
+
 // GetProject はプロジェクトの詳細情報を取得する
 func (jc *JIRAClient) GetProject(projectKey string) (*cloud.Project, error) {
+	if jc.isDataCenter() {
+		var project cloud.Project
+		if _, err := jc.getOnPremise("rest/api/2/project/"+url.PathEscape(projectKey), &project); err != nil {
+			return nil, fmt.Errorf("プロジェクト %s の取得に失敗しました: %w", projectKey, err)
+		}
+		return &project, nil
+	}
+
 	project, resp, err := jc.client.Project.Get(jc.ctx, projectKey)
 	if err != nil {
 		slog.Error("プロジェクト取得エラー",
@@ -381,7 +1041,9 @@ func (jc *JIRAClient) GetDevStatusDetails(issueID, applicationType, dataType str
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.SetBasicAuth(jc.email, jc.apiToken)
+	if err := jc.setAuth(req); err != nil {
+		return nil, err
+	}
 
 	slog.Debug("Dev-Status API リクエスト",
 		"url", requestURL,
@@ -440,6 +1102,10 @@ func (jc *JIRAClient) GetDevStatusDetails(issueID, applicationType, dataType str
 
 // GetDevStatusGraphQL はGraphQL APIで開発情報の詳細を取得する
 func (jc *JIRAClient) GetDevStatusGraphQL(issueID string) (*DevStatusDetail, error) {
+	if jc.isDataCenter() {
+		return nil, fmt.Errorf("GraphQL API（jsw2/graphql）はJira Cloudでのみ使用できます")
+	}
+
 	startTime := time.Now()
 	apiURL := fmt.Sprintf("%s/jsw2/graphql?operation=DevDetailsDialog", jc.baseURL)
 
@@ -507,7 +1173,9 @@ func (jc *JIRAClient) GetDevStatusGraphQL(issueID string) (*DevStatusDetail, err
 	req.Header.Set("Accept", "application/json")
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("X-Query-Context", fmt.Sprintf("ari:cloud:platform::site/%s", ""))
-	req.SetBasicAuth(jc.email, jc.apiToken)
+	if err := jc.setAuth(req); err != nil {
+		return nil, err
+	}
 
 	slog.Debug("GraphQL API リクエスト",
 		"url", apiURL,
@@ -566,6 +1234,14 @@ func (jc *JIRAClient) GetDevStatusGraphQL(issueID string) (*DevStatusDetail, err
 
 // GetRemoteLinks は課題のリモートリンク（外部リンク）を取得する
 func (jc *JIRAClient) GetRemoteLinks(issueKey string) ([]cloud.RemoteLink, error) {
+	if jc.isDataCenter() {
+		remoteLinks := []cloud.RemoteLink{}
+		if _, err := jc.getOnPremise(fmt.Sprintf("rest/api/2/issue/%s/remotelink", url.PathEscape(issueKey)), &remoteLinks); err != nil {
+			return nil, fmt.Errorf("リモートリンク取得失敗: %w", err)
+		}
+		return remoteLinks, nil
+	}
+
 	remoteLinks, resp, err := jc.client.Issue.GetRemoteLinks(jc.ctx, issueKey)
 	if err != nil {
 		slog.Debug("リモートリンク取得エラー",
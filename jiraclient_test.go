@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -39,7 +40,7 @@ func TestNewJIRAClient(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			client, err := NewJIRAClient(tt.config)
+			client, err := NewJIRAClient(context.Background(), tt.config)
 
 			if tt.wantErr {
 				if err == nil {
@@ -499,3 +500,359 @@ func TestGetIssuesByJQL(t *testing.T) {
 		}
 	}
 }
+
+// TestSearchAllJQLV3 はページ数の上限なしで最終ページまで取得できることのテスト
+func TestSearchAllJQLV3(t *testing.T) {
+	// SearchJQLV3の上限（100ページ）を超えるページ数を返すモック
+	totalPages := defaultMaxSearchPages + 20
+	requestCount := 0
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount++
+		resp := JQLSearchResponse{
+			IsLast: requestCount >= totalPages,
+			Issues: []cloud.Issue{
+				{Key: fmt.Sprintf("TEST-%d", requestCount)},
+			},
+		}
+		if !resp.IsLast {
+			resp.NextPageToken = fmt.Sprintf("page%d", requestCount+1)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	client := &JIRAClient{
+		ctx:        context.Background(),
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		email:      "test@example.com",
+		apiToken:   "test-token",
+	}
+
+	issueKeys, err := client.SearchAllJQLV3("project = TEST", 1)
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	if len(issueKeys) != totalPages {
+		t.Errorf("課題数 = %d, want %d", len(issueKeys), totalPages)
+	}
+	if issueKeys[len(issueKeys)-1] != fmt.Sprintf("TEST-%d", totalPages) {
+		t.Errorf("最後の課題キー = %q, want %q", issueKeys[len(issueKeys)-1], fmt.Sprintf("TEST-%d", totalPages))
+	}
+
+	// SearchJQLV3は従来どおり上限で打ち切られる
+	requestCount = 0
+	issueKeys, err = client.SearchJQLV3("project = TEST", 1)
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	if len(issueKeys) != defaultMaxSearchPages {
+		t.Errorf("SearchJQLV3の課題数 = %d, want %d", len(issueKeys), defaultMaxSearchPages)
+	}
+}
+
+// TestSearchAllJQLV3_RepeatedToken は同じNextPageTokenが返された場合に終了することのテスト
+func TestSearchAllJQLV3_RepeatedToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(JQLSearchResponse{
+			IsLast:        false,
+			NextPageToken: "same-token",
+			Issues:        []cloud.Issue{{Key: "TEST-1"}},
+		})
+	}))
+	defer server.Close()
+
+	client := &JIRAClient{
+		ctx:        context.Background(),
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		email:      "test@example.com",
+		apiToken:   "test-token",
+	}
+
+	issueKeys, err := client.SearchAllJQLV3("project = TEST", 1)
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	// 1ページ目 + 同じトークンでの2ページ目で終了
+	if len(issueKeys) != 2 {
+		t.Errorf("課題数 = %d, want 2", len(issueKeys))
+	}
+}
+
+// TestGetChangelog は変更履歴をページングしながら全件取得することのテスト
+func TestGetChangelog(t *testing.T) {
+	total := 250
+	var startAts []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/3/issue/TEST-1/changelog" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		startAts = append(startAts, r.URL.Query().Get("startAt"))
+
+		var startAt int
+		fmt.Sscanf(r.URL.Query().Get("startAt"), "%d", &startAt)
+		page := ChangelogPage{StartAt: startAt, MaxResults: changelogPageSize, Total: total}
+		for i := startAt; i < min(startAt+changelogPageSize, total); i++ {
+			page.Values = append(page.Values, cloud.ChangelogHistory{Id: fmt.Sprintf("%d", i)})
+		}
+		page.IsLast = startAt+len(page.Values) >= total
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := &JIRAClient{
+		ctx:        context.Background(),
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		email:      "test@example.com",
+		apiToken:   "test-token",
+	}
+
+	histories, err := client.GetChangelog("TEST-1")
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	if len(histories) != total {
+		t.Errorf("変更履歴数 = %d, want %d", len(histories), total)
+	}
+	if histories[total-1].Id != fmt.Sprintf("%d", total-1) {
+		t.Errorf("最後の変更履歴ID = %q, want %q", histories[total-1].Id, fmt.Sprintf("%d", total-1))
+	}
+	wantStartAts := []string{"0", "100", "200"}
+	if fmt.Sprint(startAts) != fmt.Sprint(wantStartAts) {
+		t.Errorf("startAt = %v, want %v", startAts, wantStartAts)
+	}
+
+	// 存在しない課題はエラーになること
+	if _, err := client.GetChangelog("TEST-999"); err == nil {
+		t.Error("存在しない課題に対してエラーが返されませんでした")
+	}
+}
+
+// TestGetComments はコメントをページングしながら全件取得し、公開範囲の情報を保持することのテスト
+func TestGetComments(t *testing.T) {
+	total := 150
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/TEST-1/comment" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("orderBy") != "created" {
+			t.Errorf("orderBy = %q, want created", r.URL.Query().Get("orderBy"))
+		}
+		if r.URL.Query().Get("expand") != "renderedBody" {
+			t.Errorf("expand = %q, want renderedBody", r.URL.Query().Get("expand"))
+		}
+
+		var startAt int
+		fmt.Sscanf(r.URL.Query().Get("startAt"), "%d", &startAt)
+		page := CommentPage{StartAt: startAt, MaxResults: commentPageSize, Total: total}
+		for i := startAt; i < min(startAt+commentPageSize, total); i++ {
+			comment := IssueComment{Comment: cloud.Comment{ID: fmt.Sprintf("%d", i), Body: "本文"}}
+			if i == 0 {
+				comment.Visibility = &cloud.CommentVisibility{Type: "role", Value: "Developers"}
+				public := false
+				comment.JSDPublic = &public
+			}
+			page.Comments = append(page.Comments, comment)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := &JIRAClient{
+		ctx:        context.Background(),
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		email:      "test@example.com",
+		apiToken:   "test-token",
+	}
+
+	comments, err := client.GetComments("TEST-1")
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	if len(comments) != total {
+		t.Fatalf("コメント数 = %d, want %d", len(comments), total)
+	}
+	if comments[0].Visibility == nil || comments[0].Visibility.Value != "Developers" {
+		t.Errorf("Visibility = %+v, want role Developers", comments[0].Visibility)
+	}
+	if comments[0].JSDPublic == nil || *comments[0].JSDPublic {
+		t.Errorf("JSDPublic = %v, want false", comments[0].JSDPublic)
+	}
+	if comments[1].JSDPublic != nil {
+		t.Errorf("JSDPublic = %v, want nil", *comments[1].JSDPublic)
+	}
+}
+
+// TestGetWorklogs は作業ログをページングしながら全件取得することのテスト
+func TestGetWorklogs(t *testing.T) {
+	total := 120
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/TEST-1/worklog" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requestCount++
+
+		var startAt int
+		fmt.Sscanf(r.URL.Query().Get("startAt"), "%d", &startAt)
+		page := cloud.Worklog{StartAt: startAt, MaxResults: worklogPageSize, Total: total}
+		for i := startAt; i < min(startAt+worklogPageSize, total); i++ {
+			page.Worklogs = append(page.Worklogs, cloud.WorklogRecord{ID: fmt.Sprintf("%d", i), TimeSpentSeconds: 3600})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := &JIRAClient{
+		ctx:        context.Background(),
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		email:      "test@example.com",
+		apiToken:   "test-token",
+	}
+
+	worklogs, err := client.GetWorklogs("TEST-1")
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	if len(worklogs) != total {
+		t.Errorf("作業ログ数 = %d, want %d", len(worklogs), total)
+	}
+	if requestCount != 2 {
+		t.Errorf("リクエスト数 = %d, want 2", requestCount)
+	}
+}
+
+// TestGetBoardSprints はボードのスプリントをisLastまでページングして取得することを確認する
+func TestGetBoardSprints(t *testing.T) {
+	total := 60
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/agile/1.0/board/7/sprint" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requestCount++
+
+		var startAt int
+		fmt.Sscanf(r.URL.Query().Get("startAt"), "%d", &startAt)
+		end := min(startAt+agilePageSize, total)
+		page := SprintPage{StartAt: startAt, MaxResults: agilePageSize, IsLast: end >= total}
+		for i := startAt; i < end; i++ {
+			page.Values = append(page.Values, SprintInfo{ID: i + 1, Name: fmt.Sprintf("Sprint %d", i+1), State: "closed", OriginBoardID: 7})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := &JIRAClient{
+		ctx:        context.Background(),
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		email:      "test@example.com",
+		apiToken:   "test-token",
+	}
+
+	sprints, err := client.GetBoardSprints(7)
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	if len(sprints) != total {
+		t.Errorf("スプリント数 = %d, want %d", len(sprints), total)
+	}
+	if requestCount != 2 {
+		t.Errorf("リクエスト数 = %d, want 2", requestCount)
+	}
+	if sprints[total-1].Name != "Sprint 60" {
+		t.Errorf("最後のスプリント名 = %q, want %q", sprints[total-1].Name, "Sprint 60")
+	}
+}
+
+// TestGetProjectVersions はプロジェクトのバージョンをページングして全件取得することを確認する
+func TestGetProjectVersions(t *testing.T) {
+	total := 70
+	requestCount := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/project/TEST/version" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requestCount++
+
+		var startAt int
+		fmt.Sscanf(r.URL.Query().Get("startAt"), "%d", &startAt)
+		end := min(startAt+versionPageSize, total)
+		page := VersionPage{StartAt: startAt, MaxResults: versionPageSize, Total: total, IsLast: end >= total}
+		for i := startAt; i < end; i++ {
+			page.Values = append(page.Values, cloud.Version{ID: fmt.Sprintf("%d", 10000+i), Name: fmt.Sprintf("1.%d", i)})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page)
+	}))
+	defer server.Close()
+
+	client := &JIRAClient{
+		ctx:        context.Background(),
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		email:      "test@example.com",
+		apiToken:   "test-token",
+	}
+
+	versions, err := client.GetProjectVersions("TEST")
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	if len(versions) != total {
+		t.Errorf("バージョン数 = %d, want %d", len(versions), total)
+	}
+	if requestCount != 2 {
+		t.Errorf("リクエスト数 = %d, want 2", requestCount)
+	}
+}
+
+// TestIsJiraURL は認証情報を付けてよいURL（JiraのAPI・サイトと同じスキームとホスト）の判定を確認する
+func TestIsJiraURL(t *testing.T) {
+	jiraClient, err := NewJIRAClient(context.Background(), &JIRAConfig{URL: "https://example.atlassian.net", CloudID: "cloud-1", Email: "test@example.com", APIToken: "test-token"})
+	if err != nil {
+		t.Fatalf("JIRAクライアントの作成に失敗しました: %v", err)
+	}
+
+	tests := []struct {
+		rawURL   string
+		expected bool
+	}{
+		{"https://example.atlassian.net/images/icons/priorities/high.svg", true},
+		{"https://EXAMPLE.atlassian.net/secure/viewavatar?avatarId=10303", true},
+		{"https://api.atlassian.com/ex/jira/cloud-1/rest/api/2/universal_avatar/view/type/issuetype/avatar/10318", true},
+		{"http://example.atlassian.net/images/icons/task.png", false},
+		{"https://example.atlassian.net:8443/images/icons/task.png", false},
+		{"https://cdn.example.com/icons/task.png", false},
+		{"https://example.atlassian.net.evil.example/icons/task.png", false},
+		{"/images/icons/task.png", false},
+	}
+	for _, tt := range tests {
+		if got := jiraClient.IsJiraURL(tt.rawURL); got != tt.expected {
+			t.Errorf("IsJiraURL(%s) = %v, want %v", tt.rawURL, got, tt.expected)
+		}
+	}
+}
@@ -11,13 +11,16 @@ import (
 
 // IssueData はJSONファイルに保存する課題データの構造
 type IssueData struct {
-	Issue       *cloud.Issue       `json:"issue"`
-	DevStatus   *DevStatusDetail   `json:"devStatus,omitempty"`
-	ParentInfo  *ParentIssueInfo   `json:"parentInfo,omitempty"`
-	ChildIssues []ChildIssueInfo   `json:"childIssues,omitempty"`
-	RemoteLinks []cloud.RemoteLink `json:"remoteLinks,omitempty"`
-	Fields      []cloud.Field      `json:"fields,omitempty"`
-	SavedAt     string             `json:"savedAt"`
+	Issue       *cloud.Issue          `json:"issue"`
+	DevStatus   *DevStatusDetail      `json:"devStatus,omitempty"`
+	ParentInfo  *ParentIssueInfo      `json:"parentInfo,omitempty"`
+	ChildIssues []ChildIssueInfo      `json:"childIssues,omitempty"`
+	RemoteLinks []cloud.RemoteLink    `json:"remoteLinks,omitempty"`
+	Comments    []IssueComment        `json:"comments,omitempty"` // 全コメント（公開範囲を含む、nilの場合は課題に含まれるコメントを使用）
+	Worklogs    []cloud.WorklogRecord `json:"worklogs,omitempty"` // 全作業ログ
+	ADF         *IssueADF             `json:"adf,omitempty"`      // 説明・コメント本文のADF（display.adf = true の場合）
+	Fields      []cloud.Field         `json:"fields,omitempty"`
+	SavedAt     string                `json:"savedAt"`
 }
 
 // JSONSaver はJSON保存を管理する構造体
@@ -46,7 +49,7 @@ func (js *JSONSaver) SaveIssue(data *IssueData) (string, error) {
 
 	// ファイル保存
 	outputPath := filepath.Join(projectDir, fmt.Sprintf("%s.json", data.Issue.Key))
-	if err := os.WriteFile(outputPath, jsonData, 0644); err != nil {
+	if err := writeFileAtomic(outputPath, jsonData, 0644); err != nil {
 		return "", fmt.Errorf("JSONファイル書き込みエラー: %w", err)
 	}
 
@@ -1,15 +1,18 @@
 package main
 
 import (
+	"bufio"
 	"context"
-	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"log/slog"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
-	"sort"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/andygrunwald/go-jira/v2/cloud"
@@ -52,14 +55,14 @@ func main() {
 	app := &cli.Command{
 		Name:  "migJira",
 		Usage: "JIRA課題を取得してMarkdownで出力する",
-		Flags: []cli.Flag{
+		Flags: append([]cli.Flag{
 			&cli.StringFlag{
 				Name:    "config",
 				Aliases: []string{"c"},
 				Value:   "config.toml",
 				Usage:   "設定ファイルのパス",
 			},
-		},
+		}, jiraSettingFlags()...),
 		Commands: []*cli.Command{
 			{
 				Name:    "issue",
@@ -78,9 +81,81 @@ func main() {
 						Value:   100,
 						Usage:   "最大取得件数",
 					},
+					&cli.IntFlag{
+						Name:    "workers",
+						Aliases: []string{"w"},
+						Usage:   "課題を並行に取得するワーカー数（省略時は設定ファイルのperformance.workers）",
+					},
+					&cli.BoolFlag{
+						Name:  "bulk",
+						Usage: "検索APIで全フィールドを一括取得する（課題ごとの取得リクエストを省略してAPI呼び出しを削減）",
+					},
+					&cli.BoolFlag{
+						Name:  "resume",
+						Usage: "前回中断した処理をチェックポイントから再開する（出力済みの課題をスキップ）",
+					},
+					&cli.BoolFlag{
+						Name:  "prune",
+						Usage: "出力後に出力済みの課題をJiraと突き合わせ、削除・移動された課題のファイルを整理する",
+					},
 				},
 				Action: searchIssues,
 			},
+			{
+				Name:    "project",
+				Aliases: []string{"p"},
+				Usage:   "プロジェクトの全課題とプロジェクト情報を出力する(例: PROJ)",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "page-size",
+						Value: 100,
+						Usage: "1回の検索リクエストで取得する件数",
+					},
+					&cli.IntFlag{
+						Name:    "workers",
+						Aliases: []string{"w"},
+						Usage:   "課題を並行に取得するワーカー数（省略時は設定ファイルのperformance.workers）",
+					},
+					&cli.BoolFlag{
+						Name:  "bulk",
+						Usage: "検索APIで全フィールドを一括取得する（課題ごとの取得リクエストを省略してAPI呼び出しを削減）",
+					},
+					&cli.BoolFlag{
+						Name:  "resume",
+						Usage: "前回中断した処理をチェックポイントから再開する（出力済みの課題をスキップ）",
+					},
+					&cli.BoolFlag{
+						Name:  "prune",
+						Usage: "出力後に出力済みの課題をJiraと突き合わせ、削除・移動された課題のファイルを整理する",
+					},
+				},
+				Action: exportProject,
+			},
+			{
+				Name:  "sync",
+				Usage: "前回の同期以降に更新された課題（とその親子課題）のみを出力する。JQLを省略した場合は--projectまたは設定ファイルのdefault_jqlを使用",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:    "project",
+						Aliases: []string{"p"},
+						Usage:   "同期するプロジェクトキー（JQLの代わりに指定）",
+					},
+					&cli.IntFlag{
+						Name:    "workers",
+						Aliases: []string{"w"},
+						Usage:   "課題を並行に取得するワーカー数（省略時は設定ファイルのperformance.workers）",
+					},
+					&cli.BoolFlag{
+						Name:  "full",
+						Usage: "前回の同期時刻を無視して全件を出力する",
+					},
+					&cli.BoolFlag{
+						Name:  "prune",
+						Usage: "出力後に出力済みの課題をJiraと突き合わせ、削除・移動された課題のファイルを整理する",
+					},
+				},
+				Action: syncIssues,
+			},
 			{
 				Name:    "convert",
 				Aliases: []string{"conv"},
@@ -100,6 +175,17 @@ func main() {
 				},
 				Action: convertFromJSON,
 			},
+			{
+				Name:  "auth",
+				Usage: "OAuth 2.0（3LO）で認可し、トークンをトークンキャッシュに保存する（jira.auth = \"oauth2\" の場合）",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:  "code",
+						Usage: "認可後にリダイレクトURLに付与された認可コード（省略時は標準入力から読み込む）",
+					},
+				},
+				Action: authorizeOAuth2,
+			},
 		},
 	}
 
@@ -108,10 +194,33 @@ func main() {
 	}
 }
 
+// jiraSettingFlags はjiraセクションの設定を上書きするCLIフラグ（--jira-*）を返す
+func jiraSettingFlags() []cli.Flag {
+	var flags []cli.Flag
+	for _, key := range JIRASettingKeys() {
+		flags = append(flags, &cli.StringFlag{
+			Name:     SettingFlagName(key),
+			Usage:    fmt.Sprintf("設定ファイルの jira.%s を上書きする（環境変数: %s）", key, SettingEnvName(key)),
+			Category: "JIRA接続設定の上書き",
+		})
+	}
+	return flags
+}
+
+// loadConfig は設定ファイルを読み込み、環境変数・CLIフラグでjiraセクションの設定を上書きする
+func loadConfig(cmd *cli.Command) (*Config, error) {
+	root := cmd.Root()
+	flagValues := make(map[string]string)
+	for _, key := range JIRASettingKeys() {
+		if name := SettingFlagName(key); root.IsSet(name) {
+			flagValues[key] = root.String(name)
+		}
+	}
+	return LoadConfigWithOverrides(root.String("config"), flagValues)
+}
+
 // fetchIssue は単一の課題を取得して出力する
 func fetchIssue(ctx context.Context, cmd *cli.Command) error {
-	configPath := cmd.String("config")
-
 	// 位置引数からチケット番号を取得
 	if cmd.Args().Len() == 0 {
 		return fmt.Errorf("チケット番号を指定してください（例: PROJ-123）")
@@ -119,13 +228,17 @@ func fetchIssue(ctx context.Context, cmd *cli.Command) error {
 	issueKey := cmd.Args().First()
 
 	// 設定ファイルの読み込み
-	config, err := LoadConfig(configPath)
+	config, err := loadConfig(cmd)
 	if err != nil {
 		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
 	}
 
+	// Ctrl-Cで処理中のAPIリクエストを中断する
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
 	// JIRAクライアントの作成
-	jiraClient, err := NewJIRAClient(&config.JIRA)
+	jiraClient, err := NewJIRAClient(ctx, &config.JIRA)
 	if err != nil {
 		return fmt.Errorf("JIRAクライアントの作成に失敗しました: %w", err)
 	}
@@ -151,6 +264,14 @@ func fetchIssue(ctx context.Context, cmd *cli.Command) error {
 
 	fmt.Printf("課題を取得しました: %s - %s\n---\n", issue.Key, issue.Fields.Summary)
 
+	// 変更履歴の取得（課題に含まれる変更履歴は切り詰められているため、全件で置き換える）
+	histories, err := jiraClient.GetChangelog(issueKey)
+	if err != nil {
+		fmt.Printf("警告: 変更履歴の取得に失敗しました（スキップして継続）: %v\n", err)
+	} else {
+		issue.Changelog = &cloud.Changelog{Histories: histories}
+	}
+
 	// 開発情報の詳細を取得（設定で有効な場合のみ）
 	var devStatus *DevStatusDetail
 	if config.Development.Enabled && issue.ID != "" {
@@ -195,7 +316,7 @@ func fetchIssue(ctx context.Context, cmd *cli.Command) error {
 	}
 
 	// 添付ファイルのダウンロード
-	downloader := NewDownloader(config.Output.AttachmentsDir, config.JIRA.Email, config.JIRA.APIToken)
+	downloader := NewDownloader(ctx, config.Output.AttachmentsDir, jiraClient.Authenticator(), config.JIRA.Retry)
 	attachmentFiles, err := downloader.DownloadAttachments(issue)
 	if err != nil {
 		return fmt.Errorf("添付ファイルのダウンロードに失敗しました: %w", err)
@@ -223,54 +344,13 @@ func fetchIssue(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
-	// 子課題情報の取得（すべての課題に対して実行）
+	// 子課題情報の取得（すべての課題に対して実行、一覧表示に必要なフィールドのみを1回の検索で取得）
 	var childIssues []ChildIssueInfo
-	childKeys, err := jiraClient.GetChildIssues(issue.Key, 100)
+	children, err := jiraClient.GetChildIssueSummaries(issue.Key, config.Display.RankFieldId)
 	if err != nil {
 		fmt.Printf("警告: 子課題の取得に失敗しました（課題: %s）: %v\n", issue.Key, err)
-	} else if len(childKeys) > 0 {
-		childIssues = make([]ChildIssueInfo, 0, len(childKeys))
-		for _, childKey := range childKeys {
-			childIssue, err := jiraClient.GetIssue(childKey)
-			if err != nil {
-				fmt.Printf("警告: 子課題 %s の取得に失敗しました: %v\n", childKey, err)
-				continue
-			}
-			// Sub-task課題タイプは除外
-			issueType := childIssue.Fields.Type.Name
-			if issueType == "Sub-task" || issueType == "Subtask" || issueType == "サブタスク" {
-				continue
-			}
-
-			// Rankフィールドを取得
-			rankValue := ""
-			if rank, exists := childIssue.Fields.Unknowns[config.Display.RankFieldId]; exists {
-				if rankStr, ok := rank.(string); ok {
-					rankValue = rankStr
-				}
-			}
-			childIssues = append(childIssues, ChildIssueInfo{
-				Key:     childIssue.Key,
-				Summary: childIssue.Fields.Summary,
-				Status:  childIssue.Fields.Status.Name,
-				Type:    childIssue.Fields.Type.Name,
-				Rank:    rankValue,
-			})
-		}
-		// 子課題をRankフィールドでソート
-		if len(childIssues) > 0 {
-			sort.Slice(childIssues, func(i, j int) bool {
-				// Rankが空の場合は後ろに配置
-				if childIssues[i].Rank == "" && childIssues[j].Rank != "" {
-					return false
-				}
-				if childIssues[i].Rank != "" && childIssues[j].Rank == "" {
-					return true
-				}
-				// 両方とも空でない場合は辞書順でソート
-				return childIssues[i].Rank < childIssues[j].Rank
-			})
-		}
+	} else if len(children) > 0 {
+		childIssues = childIssueInfos(children, config.Display.RankFieldId)
 	}
 
 	// リモートリンク（Confluenceコンテンツなど）の取得
@@ -286,7 +366,8 @@ func fetchIssue(ctx context.Context, cmd *cli.Command) error {
 	}
 
 	// Markdown出力
-	mdWriter := NewMarkdownWriter(config.Output.MarkdownDir, config.Output.AttachmentsDir, userMapping, config)
+	mdWriter := NewMarkdownWriter(ctx, config.Output.MarkdownDir, config.Output.AttachmentsDir, userMapping, config)
+	mdWriter.DownloadJiraIcons(jiraClient, downloader)
 
 	// プロジェクトの_index.md生成
 	// issueコマンドではチケット一覧なしで_index.md生成
@@ -304,18 +385,45 @@ func fetchIssue(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
+	issueData := &IssueData{
+		Issue:       issue,
+		DevStatus:   devStatus,
+		ParentInfo:  parentInfo,
+		ChildIssues: childIssues,
+		RemoteLinks: remoteLinks,
+		Fields:      fields,
+		SavedAt:     time.Now().Format(time.RFC3339),
+	}
+
+	// コメントの全件取得（失敗した場合は課題に含まれるコメントで出力する）
+	comments, err := jiraClient.GetComments(issueKey)
+	if err != nil {
+		fmt.Printf("警告: コメントの取得に失敗しました（スキップして継続）: %v\n", err)
+	} else {
+		issueData.Comments = comments
+	}
+
+	// 説明・コメント本文のADF（設定で有効な場合のみ）
+	if config.Display.ADF {
+		adf, err := jiraClient.GetIssueADF(issue, textareaFieldIDs(fields))
+		if err != nil {
+			fmt.Printf("警告: ADFの取得に失敗しました（Wiki記法で出力します）: %v\n", err)
+		} else {
+			issueData.ADF = adf
+		}
+	}
+
+	// 作業ログの全件取得
+	worklogs, err := jiraClient.GetWorklogs(issueKey)
+	if err != nil {
+		fmt.Printf("警告: 作業ログの取得に失敗しました（スキップして継続）: %v\n", err)
+	} else {
+		issueData.Worklogs = worklogs
+	}
+
 	// JSON保存（設定されている場合）
 	if config.Output.JSONDir != "" {
 		jsonSaver := NewJSONSaver(config.Output.JSONDir)
-		issueData := &IssueData{
-			Issue:       issue,
-			DevStatus:   devStatus,
-			ParentInfo:  parentInfo,
-			ChildIssues: childIssues,
-			RemoteLinks: remoteLinks,
-			Fields:      fields,
-			SavedAt:     time.Now().Format(time.RFC3339),
-		}
 		jsonPath, err := jsonSaver.SaveIssue(issueData)
 		if err != nil {
 			slog.Warn("JSON保存エラー", "error", err)
@@ -324,7 +432,7 @@ func fetchIssue(ctx context.Context, cmd *cli.Command) error {
 		}
 	}
 
-	if err := mdWriter.WriteIssue(issue, attachmentFiles, fieldNameCache, devStatus, parentInfo, childIssues, remoteLinks); err != nil {
+	if err := mdWriter.WriteIssue(issueData, attachmentFiles, fieldNameCache); err != nil {
 		return fmt.Errorf("Markdownファイルの出力に失敗しました: %w", err)
 	}
 
@@ -335,7 +443,6 @@ func fetchIssue(ctx context.Context, cmd *cli.Command) error {
 
 // searchIssues はJQLで課題を検索して出力する
 func searchIssues(ctx context.Context, cmd *cli.Command) error {
-	configPath := cmd.String("config")
 	maxResults := cmd.Int("max")
 
 	// 位置引数からJQLを取得（省略可能）
@@ -345,7 +452,7 @@ func searchIssues(ctx context.Context, cmd *cli.Command) error {
 	}
 
 	// 設定ファイルの読み込み
-	config, err := LoadConfig(configPath)
+	config, err := loadConfig(cmd)
 	if err != nil {
 		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
 	}
@@ -359,22 +466,44 @@ func searchIssues(ctx context.Context, cmd *cli.Command) error {
 		fmt.Printf("設定ファイルのデフォルトJQLを使用: %s\n", jql)
 	}
 
+	stopCtx, abortCtx, stop := handleSignals(ctx)
+	defer stop()
+
 	// JIRAクライアントの作成
-	jiraClient, err := NewJIRAClient(&config.JIRA)
+	jiraClient, err := NewJIRAClient(abortCtx, &config.JIRA)
 	if err != nil {
 		return fmt.Errorf("JIRAクライアントの作成に失敗しました: %w", err)
 	}
 
-	// フィールドリストを取得してキャッシュを作成
-	fields, err := jiraClient.GetFieldList()
+	// チェックポイント（--resumeの場合は出力済みの課題をスキップ）
+	checkpoint, err := OpenCheckpoint(config.Output.CheckpointFile, jql, cmd.Bool("resume"))
 	if err != nil {
-		fmt.Printf("警告: フィールドリストの取得に失敗しました: %v\n", err)
-		fields = nil
+		return err
+	}
+	defer checkpoint.Close()
+	if n := checkpoint.CompletedCount(); n > 0 {
+		fmt.Printf("チェックポイントから再開します（出力済み: %d 件）\n", n)
 	}
-	fieldNameCache := BuildFieldNameCache(fields)
+
+	exporter := NewIssueExporter(config, jiraClient)
+	exporter.UseCheckpoint(checkpoint)
 
 	fmt.Printf("JQLで検索中: %s\n", jql)
 
+	// 一括取得モード: 検索結果の課題をそのまま出力する
+	if cmd.Bool("bulk") {
+		failedKeys, err := exporter.ExportIssuesByJQL(stopCtx, jql, maxResults, defaultMaxSearchPages, workerCount(cmd, config))
+		printExportSummary(config, failedKeys)
+		if err != nil {
+			return fmt.Errorf("課題の検索に失敗しました: %w", err)
+		}
+		movedFailedKeys, err := pruneStaleIssues(stopCtx, cmd, config, jiraClient, exporter, jql, nil)
+		if err != nil {
+			return err
+		}
+		return finishCheckpoint(stopCtx, checkpoint, append(failedKeys, movedFailedKeys...))
+	}
+
 	// 課題キーの検索
 	issueKeys, err := jiraClient.GetIssuesByJQL(jql, maxResults)
 	if err != nil {
@@ -383,247 +512,316 @@ func searchIssues(ctx context.Context, cmd *cli.Command) error {
 
 	fmt.Printf("%d 件の課題が見つかりました\n", len(issueKeys))
 
-	// ユーザーマッピングの初期化
-	userMapping := make(UserMapping)
-
 	// 各課題を処理
-	downloader := NewDownloader(config.Output.AttachmentsDir, config.JIRA.Email, config.JIRA.APIToken)
-	mdWriter := NewMarkdownWriter(config.Output.MarkdownDir, config.Output.AttachmentsDir, userMapping, config)
+	failedKeys := exporter.ExportIssues(stopCtx, issueKeys, workerCount(cmd, config))
+	printExportSummary(config, failedKeys)
 
-	// 親課題情報のキャッシュ
-	parentInfoCache := make(map[string]*ParentIssueInfo)
+	movedFailedKeys, err := pruneStaleIssues(stopCtx, cmd, config, jiraClient, exporter, jql, nil)
+	if err != nil {
+		return err
+	}
+	return finishCheckpoint(stopCtx, checkpoint, append(failedKeys, movedFailedKeys...))
+}
 
-	// 子課題キャッシュ
-	childIssuesCache := make(map[string][]ChildIssueInfo)
+// exportProject はプロジェクトの全課題とプロジェクト情報（_index.md）を出力する
+func exportProject(ctx context.Context, cmd *cli.Command) error {
+	pageSize := cmd.Int("page-size")
 
-	// プロジェクトごとの_index.md生成済みフラグ（重複防止）
-	generatedProjects := make(map[string]bool)
+	// 位置引数からプロジェクトキーを取得
+	if cmd.Args().Len() == 0 {
+		return fmt.Errorf("プロジェクトキーを指定してください（例: PROJ）")
+	}
+	projectKey := cmd.Args().First()
+
+	// 設定ファイルの読み込み
+	config, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
 
-	for i, issueKey := range issueKeys {
-		fmt.Printf("[%d/%d] 処理中: %s\n", i+1, len(issueKeys), issueKey)
+	stopCtx, abortCtx, stop := handleSignals(ctx)
+	defer stop()
 
-		// 課題の詳細情報を取得（descriptionを含む完全な情報）
-		issue, err := jiraClient.GetIssue(issueKey)
-		if err != nil {
-			fmt.Printf("警告: 課題 %s の取得に失敗しました: %v\n", issueKey, err)
-			continue
-		}
+	// JIRAクライアントの作成
+	jiraClient, err := NewJIRAClient(abortCtx, &config.JIRA)
+	if err != nil {
+		return fmt.Errorf("JIRAクライアントの作成に失敗しました: %w", err)
+	}
 
-		fmt.Printf("  取得完了: %s - %s\n", issue.Key, issue.Fields.Summary)
+	// プロジェクト情報の取得（存在しないプロジェクトキーの場合はここでエラー終了）
+	project, err := jiraClient.GetProject(projectKey)
+	if err != nil {
+		return fmt.Errorf("プロジェクトの取得に失敗しました: %w", err)
+	}
+	fmt.Printf("プロジェクトを取得しました: %s - %s\n", project.Key, project.Name)
 
-		// プロジェクトの_index.md生成（初回のみ）
-		projectKey := issue.Fields.Project.Key
-		if !generatedProjects[projectKey] {
-			project, err := jiraClient.GetProject(projectKey)
-			if err != nil {
-				slog.Warn("プロジェクト取得に失敗",
-					"project", projectKey,
-					"error", err)
-			} else {
-				if err := mdWriter.WriteProjectIndex(project); err != nil {
-					slog.Warn("_index.md生成に失敗",
-						"project", projectKey,
-						"error", err)
-				} else {
-					fmt.Printf("_index.mdを生成しました: %s\n", projectKey)
-				}
-			}
-			generatedProjects[projectKey] = true
-		}
+	exporter := NewIssueExporter(config, jiraClient)
 
-		// ユーザーマッピングに追加
-		BuildUserMappingFromIssue(issue, userMapping)
+	// プロジェクトの_index.md生成（リーダー、コンポーネント、バージョンを含む）
+	if err := exporter.WriteProjectIndex(project); err != nil {
+		return fmt.Errorf("_index.md の生成に失敗しました: %w", err)
+	}
+	fmt.Printf("_index.mdを生成しました: %s\n", project.Key)
 
-		// デバッグ用: 取得した課題データをJSON形式でログ出力
-		if issueJSON, err := json.MarshalIndent(issue, "", "  "); err == nil {
-			slog.Debug("JIRA課題データ (JSON)",
-				"issueKey", issue.Key,
-				"json", string(issueJSON))
-		} else {
-			slog.Warn("JSON変換に失敗しました", "issueKey", issue.Key, "error", err)
-		}
+	// プロジェクトの全課題キーを取得（ページ数の上限なし）
+	jql := fmt.Sprintf(`project = "%s" ORDER BY key ASC`, project.Key)
+
+	// チェックポイント（--resumeの場合は出力済みの課題をスキップ）
+	checkpoint, err := OpenCheckpoint(config.Output.CheckpointFile, jql, cmd.Bool("resume"))
+	if err != nil {
+		return err
+	}
+	defer checkpoint.Close()
+	if n := checkpoint.CompletedCount(); n > 0 {
+		fmt.Printf("チェックポイントから再開します（出力済み: %d 件）\n", n)
+	}
+	exporter.UseCheckpoint(checkpoint)
+
+	fmt.Printf("JQLで検索中: %s\n", jql)
 
-		// 添付ファイルのダウンロード
-		attachmentFiles, err := downloader.DownloadAttachments(issue)
+	// 一括取得モード: 検索結果の課題をそのまま出力する（ページ数の上限なし）
+	if cmd.Bool("bulk") {
+		failedKeys, err := exporter.ExportIssuesByJQL(stopCtx, jql, pageSize, 0, workerCount(cmd, config))
+		printExportSummary(config, failedKeys)
 		if err != nil {
-			fmt.Printf("  警告: 添付ファイルのダウンロードに失敗しました: %v\n", err)
-			attachmentFiles = []string{}
+			return fmt.Errorf("課題の検索に失敗しました: %w", err)
 		}
+		movedFailedKeys, err := pruneStaleIssues(stopCtx, cmd, config, jiraClient, exporter, jql, []string{project.Key})
+		if err != nil {
+			return err
+		}
+		return finishCheckpoint(stopCtx, checkpoint, append(failedKeys, movedFailedKeys...))
+	}
 
-		// 開発情報の詳細を取得（設定で有効な場合のみ）
-		var devStatus *DevStatusDetail
-		if config.Development.Enabled && issue.ID != "" {
-			apiType := config.Development.APIType
-			if apiType == "" {
-				apiType = "rest" // デフォルトはREST API
-			}
+	issueKeys, err := jiraClient.GetAllIssuesByJQL(jql, pageSize)
+	if err != nil {
+		return fmt.Errorf("課題の検索に失敗しました: %w", err)
+	}
 
-			if apiType == "graphql" {
-				// GraphQL APIを使用
-				devStatus, err = jiraClient.GetDevStatusGraphQL(issue.ID)
-				if err != nil {
-					slog.Debug("GraphQL API 開発情報取得失敗",
-						"issueKey", issueKey,
-						"issueID", issue.ID,
-						"error", err)
-					slog.Warn("開発情報の詳細取得に失敗（スキップして継続）",
-						"issueKey", issueKey,
-						"error", err)
-					devStatus = nil
-				}
-			} else {
-				// REST APIを使用
-				appType := config.Development.ApplicationType
-				if appType == "" {
-					appType = "bitbucket" // デフォルト
-				}
-
-				devStatus, err = jiraClient.GetDevStatusDetails(issue.ID, appType, "pullrequest")
-				if err != nil {
-					slog.Debug("REST API 開発情報取得失敗",
-						"issueKey", issueKey,
-						"issueID", issue.ID,
-						"appType", appType,
-						"error", err)
-					slog.Warn("開発情報の詳細取得に失敗（スキップして継続）",
-						"issueKey", issueKey,
-						"error", err)
-					devStatus = nil
-				}
-			}
-		}
+	fmt.Printf("%d 件の課題が見つかりました\n", len(issueKeys))
 
-		// 親課題情報の取得（キャッシュを使用）
-		var parentInfo *ParentIssueInfo
-		if issue.Fields.Parent != nil && issue.Fields.Parent.Key != "" {
-			parentKey := issue.Fields.Parent.Key
-			if cachedInfo, exists := parentInfoCache[parentKey]; exists {
-				parentInfo = cachedInfo
-			} else {
-				parentIssue, err := jiraClient.GetIssue(parentKey)
-				if err != nil {
-					fmt.Printf("  警告: 親課題 %s の取得に失敗しました: %v\n", parentKey, err)
-				} else {
-					parentInfo = &ParentIssueInfo{
-						Key:  parentIssue.Key,
-						Type: parentIssue.Fields.Type.Name,
-					}
-					parentInfoCache[parentKey] = parentInfo
-				}
-			}
-		}
+	// 各課題を処理
+	failedKeys := exporter.ExportIssues(stopCtx, issueKeys, workerCount(cmd, config))
+	printExportSummary(config, failedKeys)
 
-		// 子課題の取得（キャッシュ使用、すべての課題に対して実行）
-		var childIssues []ChildIssueInfo
-		if cachedChildren, exists := childIssuesCache[issue.Key]; exists {
-			childIssues = cachedChildren
-		} else {
-			childKeys, err := jiraClient.GetChildIssues(issue.Key, 100)
-			if err != nil {
-				fmt.Printf("  警告: 子課題の取得に失敗しました（課題: %s）: %v\n", issue.Key, err)
-			} else if len(childKeys) > 0 {
-				childIssues = make([]ChildIssueInfo, 0, len(childKeys))
-				for _, childKey := range childKeys {
-					childIssue, err := jiraClient.GetIssue(childKey)
-					if err != nil {
-						fmt.Printf("  警告: 子課題 %s の取得に失敗しました: %v\n", childKey, err)
-						continue
-					}
-					// Sub-task課題タイプは除外
-					issueType := childIssue.Fields.Type.Name
-					if issueType == "Sub-task" || issueType == "Subtask" || issueType == "サブタスク" {
-						continue
-					}
-
-					// Rankフィールドを取得
-					rankValue := ""
-					if rank, exists := childIssue.Fields.Unknowns[config.Display.RankFieldId]; exists {
-						if rankStr, ok := rank.(string); ok {
-							rankValue = rankStr
-						}
-					}
-					childIssues = append(childIssues, ChildIssueInfo{
-						Key:     childIssue.Key,
-						Summary: childIssue.Fields.Summary,
-						Status:  childIssue.Fields.Status.Name,
-						Type:    childIssue.Fields.Type.Name,
-						Rank:    rankValue,
-					})
-				}
-				// 子課題をRankフィールドでソート
-				if len(childIssues) > 0 {
-					sort.Slice(childIssues, func(i, j int) bool {
-						// Rankが空の場合は後ろに配置
-						if childIssues[i].Rank == "" && childIssues[j].Rank != "" {
-							return false
-						}
-						if childIssues[i].Rank != "" && childIssues[j].Rank == "" {
-							return true
-						}
-						// 両方とも空でない場合は辞書順でソート
-						return childIssues[i].Rank < childIssues[j].Rank
-					})
-				}
-				childIssuesCache[issue.Key] = childIssues
-			}
-		}
+	movedFailedKeys, err := pruneStaleIssues(stopCtx, cmd, config, jiraClient, exporter, jql, []string{project.Key})
+	if err != nil {
+		return err
+	}
+	return finishCheckpoint(stopCtx, checkpoint, append(failedKeys, movedFailedKeys...))
+}
 
-		// リモートリンク（Confluenceコンテンツなど）の取得
-		var remoteLinks []cloud.RemoteLink
-		remoteLinksResult, err := jiraClient.GetRemoteLinks(issue.Key)
-		if err != nil {
-			slog.Debug("リモートリンク取得エラー",
-				"issueKey", issue.Key,
-				"error", err)
-			remoteLinks = []cloud.RemoteLink{}
-		} else {
-			remoteLinks = remoteLinksResult
+// handleSignals はSIGINT/SIGTERMによる終了要求を扱うコンテキストを返す
+// 1回目のシグナルでstopCtxをキャンセルし（新しい課題の処理を止め、処理中の課題は出力を終える）、
+// 2回目のシグナルでabortCtxもキャンセルする（処理中のAPIリクエストと書き込みを中断する）
+func handleSignals(parent context.Context) (stopCtx, abortCtx context.Context, release func()) {
+	stopCtx, stop := context.WithCancel(parent)
+	abortCtx, abort := context.WithCancel(parent)
+
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sigCh:
+			fmt.Println("\n中断要求を受け付けました。処理中の課題を出力してから終了します（もう一度押すと即座に中断します）")
+			stop()
+		case <-abortCtx.Done():
+			return
+		}
+		select {
+		case <-sigCh:
+			fmt.Println("\n処理を中断します")
+			abort()
+		case <-abortCtx.Done():
 		}
+	}()
 
-		// JSON保存（設定されている場合）
-		if config.Output.JSONDir != "" {
-			jsonSaver := NewJSONSaver(config.Output.JSONDir)
-			issueData := &IssueData{
-				Issue:       issue,
-				DevStatus:   devStatus,
-				ParentInfo:  parentInfo,
-				ChildIssues: childIssues,
-				RemoteLinks: remoteLinks,
-				Fields:      fields,
-				SavedAt:     time.Now().Format(time.RFC3339),
-			}
-			jsonPath, err := jsonSaver.SaveIssue(issueData)
-			if err != nil {
-				slog.Warn("JSON保存エラー", "issueKey", issue.Key, "error", err)
-			} else {
-				fmt.Printf("  JSON出力: %s\n", jsonPath)
-			}
+	return stopCtx, abortCtx, func() {
+		signal.Stop(sigCh)
+		stop()
+		abort()
+	}
+}
+
+// finishCheckpoint は処理結果に応じてチェックポイントを後始末する
+// 中断した場合や取得に失敗した課題がある場合はチェックポイントを残し、--resumeで再開できるようにする
+func finishCheckpoint(ctx context.Context, checkpoint *Checkpoint, failedKeys []string) error {
+	if ctx.Err() != nil {
+		return fmt.Errorf("処理を中断しました。--resume を付けて同じコマンドを実行すると続きから再開できます")
+	}
+	if len(failedKeys) > 0 {
+		fmt.Printf("取得に失敗した課題は --resume を付けて同じコマンドを実行すると再取得できます\n")
+		return nil
+	}
+	return checkpoint.Remove()
+}
+
+// syncIssues は前回の同期以降に更新された課題とその親子課題を出力し、同期時刻を記録する
+func syncIssues(ctx context.Context, cmd *cli.Command) error {
+	// 設定ファイルの読み込み
+	config, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+
+	// 同期対象のJQL（状態ファイルのキー）
+	var jql, description string
+	var projectKeys []string
+	switch {
+	case cmd.String("project") != "" && cmd.Args().Len() > 0:
+		return fmt.Errorf("JQLと--projectは同時に指定できません")
+	case cmd.String("project") != "":
+		projectKey := cmd.String("project")
+		projectKeys = []string{projectKey}
+		jql = fmt.Sprintf(`project = "%s" ORDER BY key ASC`, projectKey)
+		description = "project " + projectKey
+	case cmd.Args().Len() > 0:
+		jql = cmd.Args().First()
+		description = jql
+	default:
+		jql = config.Search.DefaultJQL
+		if jql == "" {
+			return fmt.Errorf("JQLクエリが指定されていません。引数か--projectで指定するか、設定ファイルにdefault_jqlを設定してください")
 		}
+		description = jql
+		fmt.Printf("設定ファイルのデフォルトJQLを使用: %s\n", jql)
+	}
+
+	state, err := LoadSyncState(config.Output.SyncStateFile)
+	if err != nil {
+		return err
+	}
+	previous, synced := state.Queries[jql]
+	if cmd.Bool("full") {
+		synced = false
+	}
+
+	stopCtx, abortCtx, stop := handleSignals(ctx)
+	defer stop()
+
+	// JIRAクライアントの作成
+	jiraClient, err := NewJIRAClient(abortCtx, &config.JIRA)
+	if err != nil {
+		return fmt.Errorf("JIRAクライアントの作成に失敗しました: %w", err)
+	}
 
-		// Markdown出力
-		if err := mdWriter.WriteIssue(issue, attachmentFiles, fieldNameCache, devStatus, parentInfo, childIssues, remoteLinks); err != nil {
-			fmt.Printf("  警告: Markdownファイルの出力に失敗しました: %v\n", err)
+	// 検索前の時刻を次回の基準にする（同期中に更新された課題を次回に取りこぼさないように）
+	runStarted := time.Now()
+	var lastRun time.Time
+	if synced {
+		lastRun = previous.LastRun
+		fmt.Printf("前回の同期: %s\n", lastRun.Local().Format("2006-01-02 15:04:05"))
+	} else {
+		fmt.Println("前回の同期がないため、条件に一致するすべての課題を出力します")
+	}
+	syncJQL := buildSyncJQL(jql, lastRun, runStarted)
+	fmt.Printf("JQLで検索中: %s\n", syncJQL)
+
+	issueKeys, changedCount, err := collectSyncTargets(jiraClient, syncJQL, synced)
+	if err != nil {
+		return fmt.Errorf("更新された課題の検索に失敗しました: %w", err)
+	}
+	fmt.Printf("%d 件の課題が更新されています（親子課題を含めて %d 件を出力）\n", changedCount, len(issueKeys))
+
+	// 出力（課題のプロジェクトの_index.md・スプリント・リリースノートも再生成される）
+	var failedKeys []string
+	var exporter *IssueExporter
+	if len(issueKeys) > 0 || cmd.Bool("prune") {
+		exporter = NewIssueExporter(config, jiraClient)
+	}
+	if len(issueKeys) > 0 {
+		failedKeys = exporter.ExportIssues(stopCtx, issueKeys, workerCount(cmd, config))
+		printExportSummary(config, failedKeys)
+	}
+
+	// 削除・移動された課題は更新日時の条件では検出できないため、同期対象のJQL全体と突き合わせる
+	movedFailedKeys, err := pruneStaleIssues(stopCtx, cmd, config, jiraClient, exporter, jql, projectKeys)
+	if err != nil {
+		return err
+	}
+	failedKeys = append(failedKeys, movedFailedKeys...)
+
+	if stopCtx.Err() != nil {
+		return fmt.Errorf("処理を中断しました。同期時刻は更新していないため、次回の sync で再度出力します")
+	}
+	if len(failedKeys) > 0 {
+		fmt.Println("取得に失敗した課題があるため同期時刻は更新しません（次回の sync で再度出力します）")
+		return nil
+	}
+
+	state.Queries[jql] = SyncQueryState{
+		LastRun:     runStarted,
+		IssueCount:  len(issueKeys),
+		Description: description,
+	}
+	if err := state.Save(config.Output.SyncStateFile); err != nil {
+		return err
+	}
+	fmt.Printf("同期時刻を記録しました: %s\n", config.Output.SyncStateFile)
+	return nil
+}
+
+// pruneStaleIssues は--pruneが指定された場合に、出力済みの課題をJQLの検索結果と突き合わせて
+// 削除・移動された課題のファイルを整理し、整理結果を表示する
+// 移動先の課題が今回出力されていない場合は出力し（移動前のURLがaliasesに含まれる）、取得に失敗した課題キーを返す
+// 中断された場合（ctxがキャンセルされた場合）は何もしない
+func pruneStaleIssues(ctx context.Context, cmd *cli.Command, config *Config, jiraClient *JIRAClient, exporter *IssueExporter, jql string, projectKeys []string) ([]string, error) {
+	if !cmd.Bool("prune") || ctx.Err() != nil {
+		return nil, nil
+	}
+
+	fmt.Printf("\n出力済みの課題をJiraと突き合わせています: %s\n", jql)
+	report, err := NewPruner(config, jiraClient).Prune(ctx, jql, projectKeys)
+	if report != nil {
+		report.Print(config.Prune.ArchiveDir)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil
 		}
+		return nil, fmt.Errorf("削除・移動された課題の確認に失敗しました: %w", err)
 	}
 
+	movedKeys := report.UnexportedMovedKeys()
+	if len(movedKeys) == 0 {
+		return nil, nil
+	}
+	fmt.Printf("\n移動先の課題を出力します: %d 件\n", len(movedKeys))
+	failedKeys := exporter.ExportIssues(ctx, movedKeys, workerCount(cmd, config))
+	if len(failedKeys) > 0 {
+		fmt.Printf("- 取得失敗: %d 件 (%s)\n", len(failedKeys), strings.Join(failedKeys, ", "))
+	}
+	return failedKeys, nil
+}
+
+// workerCount は並行処理のワーカー数を決定する（--workers フラグが設定ファイルより優先）
+func workerCount(cmd *cli.Command, config *Config) int {
+	if workers := cmd.Int("workers"); workers > 0 {
+		return workers
+	}
+	return config.Performance.Workers
+}
+
+// printExportSummary は出力先と取得に失敗した課題のサマリーを表示する
+func printExportSummary(config *Config, failedKeys []string) {
 	fmt.Printf("\n処理が完了しました\n")
 	fmt.Printf("- Markdown: %s\n", config.Output.MarkdownDir)
 	fmt.Printf("- 添付ファイル: %s\n", config.Output.AttachmentsDir)
 	if config.Output.JSONDir != "" {
 		fmt.Printf("- JSON: %s\n", config.Output.JSONDir)
 	}
-
-	return nil
+	if len(failedKeys) > 0 {
+		fmt.Printf("- 取得失敗: %d 件 (%s)\n", len(failedKeys), strings.Join(failedKeys, ", "))
+	}
 }
 
 // convertFromJSON はJSONファイルからMarkdownを生成する
 func convertFromJSON(ctx context.Context, cmd *cli.Command) error {
 	inputPath := cmd.String("input")
 	outputDir := cmd.String("output")
-	configPath := cmd.Root().String("config")
 
 	// 設定読み込み（Markdown出力設定用）
-	config, err := LoadConfig(configPath)
+	config, err := loadConfig(cmd)
 	if err != nil {
 		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
 	}
@@ -667,7 +865,15 @@ func convertFromJSON(ctx context.Context, cmd *cli.Command) error {
 
 	// 各JSONファイルを処理
 	successCount := 0
+	// Ctrl-Cで変換中のファイルを出力してから終了する
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
 	for i, jsonFile := range jsonFiles {
+		if ctx.Err() != nil {
+			fmt.Printf("\n中断しました\n")
+			break
+		}
 		fmt.Printf("[%d/%d] 変換中: %s\n", i+1, len(jsonFiles), jsonFile)
 
 		data, err := jsonSaver.LoadIssue(jsonFile)
@@ -684,7 +890,7 @@ func convertFromJSON(ctx context.Context, cmd *cli.Command) error {
 		BuildUserMappingFromIssue(data.Issue, userMapping)
 
 		// Markdown生成
-		mdWriter := NewMarkdownWriter(outputDir, config.Output.AttachmentsDir, userMapping, config)
+		mdWriter := NewMarkdownWriter(context.WithoutCancel(ctx), outputDir, config.Output.AttachmentsDir, userMapping, config)
 
 		// 添付ファイルのパスを構築（既にダウンロード済みと仮定）
 		var attachmentFiles []string
@@ -695,7 +901,7 @@ func convertFromJSON(ctx context.Context, cmd *cli.Command) error {
 			}
 		}
 
-		if err := mdWriter.WriteIssue(data.Issue, attachmentFiles, fieldNameCache, data.DevStatus, data.ParentInfo, data.ChildIssues, data.RemoteLinks); err != nil {
+		if err := mdWriter.WriteIssue(data, attachmentFiles, fieldNameCache); err != nil {
 			fmt.Printf("  エラー: Markdown生成に失敗しました: %v\n", err)
 			continue
 		}
@@ -711,3 +917,49 @@ func convertFromJSON(ctx context.Context, cmd *cli.Command) error {
 
 	return nil
 }
+
+// authorizeOAuth2 はOAuth 2.0の認可コードフローを実行し、トークンをトークンキャッシュに保存する
+func authorizeOAuth2(ctx context.Context, cmd *cli.Command) error {
+	// 設定ファイルの読み込み
+	config, err := loadConfig(cmd)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+	if config.JIRA.Auth != "oauth2" {
+		return fmt.Errorf("authコマンドは jira.auth = \"oauth2\" の場合のみ使用できます（現在: %s）", config.JIRA.Auth)
+	}
+
+	retryTransport := NewRetryTransport(http.DefaultTransport, config.JIRA.Retry)
+	auth := NewOAuth2Authenticator(config.JIRA.OAuth2, &http.Client{Transport: retryTransport})
+
+	code := cmd.String("code")
+	if code == "" {
+		state, err := newOAuth2State()
+		if err != nil {
+			return err
+		}
+		fmt.Println("以下のURLをブラウザで開いて認可してください:")
+		fmt.Printf("\n%s\n\n", auth.AuthCodeURL(state))
+		fmt.Printf("リダイレクト先URL（%s?code=...&state=...）をすべて貼り付けてください: ", config.JIRA.OAuth2.RedirectURL)
+
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("リダイレクト先URLの読み込みに失敗しました: %w", err)
+		}
+		if strings.TrimSpace(line) == "" {
+			return fmt.Errorf("リダイレクト先URLが入力されていません")
+		}
+		// stateが一致することを確認してから認可コードを交換する
+		code, err = parseOAuth2Redirect(line, state)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := auth.Exchange(code); err != nil {
+		return err
+	}
+
+	fmt.Printf("認可が完了しました。トークンを保存しました: %s\n", config.JIRA.OAuth2.TokenCache)
+	return nil
+}
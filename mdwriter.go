@@ -1,16 +1,17 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
+	"html"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
-	"strconv"
 	"strings"
+	"text/template"
 	"time"
-	"unicode/utf8"
 
 	"github.com/andygrunwald/go-jira/v2/cloud"
 )
@@ -68,7 +69,30 @@ type ChildIssueInfo struct {
 	Rank    string // Rankフィールド（customfield_10019）
 }
 
-// getIssueTypeIcon は課題タイプに応じたアイコンを返す
+// SprintReport はスプリントページに出力するスプリントと課題の情報を保持する
+type SprintReport struct {
+	Sprint    SprintInfo
+	BoardName string
+	Issues    []SprintIssueInfo // スプリントにコミットした課題
+}
+
+// SprintIssueInfo はスプリントに含まれる課題の情報を保持する
+type SprintIssueInfo struct {
+	Key       string
+	Project   string // プロジェクトキー（ボードが複数プロジェクトにまたがる場合のリンク生成に使用）
+	Summary   string
+	Status    string
+	Type      string
+	Completed bool // スプリント中に完了した課題
+}
+
+// ReleaseNote はリリースノートに出力するバージョンと課題の情報を保持する
+type ReleaseNote struct {
+	Version cloud.Version
+	Issues  []cloud.Issue // 修正バージョンに指定された課題（一覧表示に必要なフィールドのみ）
+}
+
+// getIssueTypeIcon は課題タイプに応じた組み込みのアイコンを返す（[display.icons.issue_types] に無い課題タイプに使用する）
 func getIssueTypeIcon(issueType string) string {
 	switch issueType {
 	case "Epic", "エピック":
@@ -88,27 +112,40 @@ func getIssueTypeIcon(issueType string) string {
 
 // MarkdownWriter はMarkdown形式で課題を出力する
 type MarkdownWriter struct {
+	ctx            context.Context
 	outputDir      string
 	attachmentsDir string
 	userMapping    UserMapping
 	config         *Config
+	template       *template.Template // 課題ページのテンプレート
+	messages       Messages           // 見出し・ラベルの文言（display.language）
+	jiraIcons      map[string]string  // ダウンロードしたJiraのアイコン（ファイル名（拡張子を除く） → ファイル名）
 }
 
 // NewMarkdownWriter は新しいMarkdownWriterを作成する
-func NewMarkdownWriter(outputDir, attachmentsDir string, userMapping UserMapping, config *Config) *MarkdownWriter {
+// ctxがキャンセルされた後はファイルを書き込まずにエラーを返す
+func NewMarkdownWriter(ctx context.Context, outputDir, attachmentsDir string, userMapping UserMapping, config *Config) *MarkdownWriter {
 	if userMapping == nil {
 		userMapping = make(UserMapping)
 	}
+	messages := messagesFromConfig(config)
 	return &MarkdownWriter{
+		ctx:            ctx,
 		outputDir:      outputDir,
 		attachmentsDir: attachmentsDir,
 		userMapping:    userMapping,
 		config:         config,
+		template:       issueTemplateFromConfig(config, messages),
+		messages:       messages,
+		jiraIcons:      loadJiraIcons(attachmentsDir, config),
 	}
 }
 
 // WriteIssue は課題をMarkdownファイルに出力する
-func (mw *MarkdownWriter) WriteIssue(issue *cloud.Issue, attachmentFiles []string, fieldNameCache FieldNameCache, devStatus *DevStatusDetail, parentInfo *ParentIssueInfo, childIssues []ChildIssueInfo, remoteLinks []cloud.RemoteLink) error {
+// 課題本体と付随情報（開発情報、親子課題、リモートリンク、コメント等）はIssueDataで受け取る
+func (mw *MarkdownWriter) WriteIssue(data *IssueData, attachmentFiles []string, fieldNameCache FieldNameCache) error {
+	issue := data.Issue
+
 	// プロジェクトキーを取得
 	projectKey := issue.Fields.Project.Key
 
@@ -119,20 +156,59 @@ func (mw *MarkdownWriter) WriteIssue(issue *cloud.Issue, attachmentFiles []strin
 	}
 
 	// Markdownコンテンツの生成
-	content := mw.generateMarkdown(issue, attachmentFiles, fieldNameCache, devStatus, parentInfo, childIssues, remoteLinks)
+	content, err := mw.generateMarkdown(data, attachmentFiles, fieldNameCache)
+	if err != nil {
+		return fmt.Errorf("Markdownコンテンツの生成に失敗しました: %w", err)
+	}
 
 	// ファイルパスの作成
 	filename := fmt.Sprintf("%s.md", issue.Key)
 	outputPath := filepath.Join(projectDir, filename)
 
 	// ファイルの書き込み
-	if err := os.WriteFile(outputPath, []byte(content), 0644); err != nil {
+	if err := mw.writeFile(outputPath, content); err != nil {
 		return fmt.Errorf("Markdownファイルの書き込みに失敗しました: %w", err)
 	}
 
 	return nil
 }
 
+// writeFile はファイルを書き込む（ctxがキャンセルされている場合は書き込まない）
+func (mw *MarkdownWriter) writeFile(path, content string) error {
+	if mw.ctx != nil && mw.ctx.Err() != nil {
+		return mw.ctx.Err()
+	}
+	return writeFileAtomic(path, []byte(content), 0644)
+}
+
+// writeFileAtomic は一時ファイルに書き込んでからリネームする
+// 書き込み中に強制終了しても、書きかけのファイルで既存のファイルを上書きしない
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
+
 // WriteProjectIndex はプロジェクトの_index.mdを生成する
 func (mw *MarkdownWriter) WriteProjectIndex(project *cloud.Project) error {
 	// プロジェクト別の出力ディレクトリの作成
@@ -144,13 +220,20 @@ func (mw *MarkdownWriter) WriteProjectIndex(project *cloud.Project) error {
 	var sb strings.Builder
 
 	// Front Matter
-	sb.WriteString("+++\n")
-	projectIcon := "📦"
-	sb.WriteString(fmt.Sprintf("title = \"%s%s\"\n", projectIcon, escapeTOMLString(project.Name)))
-	sb.WriteString(fmt.Sprintf("project_key = \"%s\"\n", project.Key))
-	sb.WriteString(fmt.Sprintf("project_name = \"%s\"\n", escapeTOMLString(project.Name)))
-	sb.WriteString("type = \"project\"\n")
-	sb.WriteString("+++\n\n")
+	// タイトルには文字列のアイコンのみ付ける（画像のアイコンはタイトルに含めない）
+	title := project.Name
+	if icon := mw.projectIcon(project.Name); !isImageIcon(icon) {
+		title = icon + project.Name
+	}
+	fm := newFrontMatter()
+	fm.Set("title", title)
+	fm.Set("project_key", project.Key)
+	fm.Set("project_name", project.Name)
+	fm.Set("type", "project")
+	if err := mw.writeFrontMatter(&sb, fm); err != nil {
+		return err
+	}
+	sb.WriteString("\n")
 
 	// 本文
 	sb.WriteString(fmt.Sprintf("# %s\n\n", project.Name))
@@ -159,32 +242,403 @@ func (mw *MarkdownWriter) WriteProjectIndex(project *cloud.Project) error {
 		sb.WriteString("\n\n")
 	}
 
+	// プロジェクト情報（リーダー、コンポーネント、バージョン）
+	mw.generateProjectInfo(&sb, project)
+	mw.generateProjectComponents(&sb, project.Components)
+	mw.generateProjectVersions(&sb, project.Versions)
+
 	// ファイルパスの作成
 	indexPath := filepath.Join(projectDir, "_index.md")
 
 	// ファイルの書き込み
-	if err := os.WriteFile(indexPath, []byte(sb.String()), 0644); err != nil {
+	if err := mw.writeFile(indexPath, sb.String()); err != nil {
 		return fmt.Errorf("_index.mdファイルの書き込みに失敗しました: %w", err)
 	}
 
 	return nil
 }
 
-// generateFrontMatter はHugoのフロントマター（TOML形式）を生成する
-func (mw *MarkdownWriter) generateFrontMatter(sb *strings.Builder, issue *cloud.Issue, parentInfo *ParentIssueInfo) {
-	sb.WriteString("+++\n")
-	sb.WriteString(fmt.Sprintf("title = \"%s\"\n", escapeTOMLString(issue.Fields.Summary)))
-	sb.WriteString(fmt.Sprintf("date = %s\n", mw.formatTimeISO8601(issue.Fields.Created)))
-	sb.WriteString(fmt.Sprintf("lastmod = %s\n", mw.formatTimeISO8601(issue.Fields.Updated)))
-	sb.WriteString(fmt.Sprintf("project = \"%s\"\n", issue.Fields.Project.Key))
-	sb.WriteString(fmt.Sprintf("issue_key = \"%s\"\n", issue.Key))
-	sb.WriteString(fmt.Sprintf("type = \"page\"\n"))
-	sb.WriteString(fmt.Sprintf("issue_type = \"%s\"\n", escapeTOMLString(issue.Fields.Type.Name)))
+// generateProjectInfo はプロジェクト情報セクションを生成する
+func (mw *MarkdownWriter) generateProjectInfo(sb *strings.Builder, project *cloud.Project) {
+	sb.WriteString(fmt.Sprintf("## %s\n\n", mw.msg("project_info")))
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("project_key"), project.Key))
+	if project.Lead.DisplayName != "" || project.Lead.AccountID != "" {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("project_lead"), mw.getUser(&project.Lead)))
+	}
+	if project.ProjectCategory.Name != "" {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("project_category"), project.ProjectCategory.Name))
+	}
+	sb.WriteString("\n")
+}
+
+// generateProjectComponents はコンポーネントセクションを生成する
+func (mw *MarkdownWriter) generateProjectComponents(sb *strings.Builder, components []cloud.ProjectComponent) {
+	if len(components) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("## %s\n\n", mw.msg("components")))
+	for _, component := range components {
+		sb.WriteString(fmt.Sprintf("- **%s**", component.Name))
+		if component.Description != "" {
+			sb.WriteString(fmt.Sprintf(": %s", component.Description))
+		}
+		if component.Lead.DisplayName != "" {
+			sb.WriteString(mw.msg("component_lead", mw.getUser(&component.Lead)))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+}
+
+// generateProjectVersions はバージョンセクションを生成する
+func (mw *MarkdownWriter) generateProjectVersions(sb *strings.Builder, versions []cloud.Version) {
+	if len(versions) == 0 {
+		return
+	}
+
+	sb.WriteString(fmt.Sprintf("## %s\n\n", mw.msg("versions")))
+	for _, version := range versions {
+		// リリースノートを生成する設定の場合はリンクにする
+		if mw.config != nil && mw.config.ReleaseNotes.Enabled && version.ID != "" {
+			sb.WriteString(fmt.Sprintf("- **[%s](releases/%s/)**", version.Name, releaseNotePageName(version)))
+		} else {
+			sb.WriteString(fmt.Sprintf("- **%s**", version.Name))
+		}
+
+		// 状態とリリース日
+		var attrs []string
+		if version.Archived != nil && *version.Archived {
+			attrs = append(attrs, mw.msg("version_archived"))
+		}
+		if version.Released != nil && *version.Released {
+			attrs = append(attrs, mw.msg("version_released"))
+		} else {
+			attrs = append(attrs, mw.msg("version_unreleased"))
+		}
+		if version.ReleaseDate != "" {
+			attrs = append(attrs, version.ReleaseDate)
+		}
+		sb.WriteString(fmt.Sprintf(" (%s)", strings.Join(attrs, ", ")))
+
+		if version.Description != "" {
+			sb.WriteString(fmt.Sprintf(": %s", version.Description))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+}
+
+// WriteReleaseNotes はプロジェクトのリリース一覧（releases/_index.md）とバージョンごとのリリースノートを生成する
+func (mw *MarkdownWriter) WriteReleaseNotes(projectKey string, notes []ReleaseNote) error {
+	releasesDir := filepath.Join(mw.outputDir, projectKey, "releases")
+	if err := os.MkdirAll(releasesDir, 0755); err != nil {
+		return fmt.Errorf("リリースノートディレクトリの作成に失敗しました: %w", err)
+	}
+
+	// リリース一覧
+	var sb strings.Builder
+	fm := newFrontMatter()
+	fm.Set("title", "🚀"+mw.msg("release_notes"))
+	fm.Set("project", projectKey)
+	fm.Set("type", "releases")
+	if err := mw.writeFrontMatter(&sb, fm); err != nil {
+		return err
+	}
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("# %s\n\n", mw.msg("release_notes")))
+	if len(notes) > 0 {
+		writeTableHeader(&sb, mw.msg("version"), mw.msg("state"), mw.msg("release_date"), mw.msg("issue_count"))
+		for _, note := range notes {
+			sb.WriteString(fmt.Sprintf("| [%s](%s/) | %s | %s | %d |\n",
+				escapeTableCell(note.Version.Name), releaseNotePageName(note.Version),
+				mw.versionStateLabel(note.Version), note.Version.ReleaseDate, len(note.Issues)))
+		}
+		sb.WriteString("\n")
+	}
+
+	indexPath := filepath.Join(releasesDir, "_index.md")
+	if err := mw.writeFile(indexPath, sb.String()); err != nil {
+		return fmt.Errorf("リリース一覧の書き込みに失敗しました: %w", err)
+	}
+
+	// バージョンごとのリリースノート
+	for _, note := range notes {
+		content, err := mw.generateReleaseNote(projectKey, note)
+		if err != nil {
+			return err
+		}
+		pagePath := filepath.Join(releasesDir, releaseNotePageName(note.Version)+".md")
+		if err := mw.writeFile(pagePath, content); err != nil {
+			return fmt.Errorf("リリースノートの書き込みに失敗しました（%s）: %w", note.Version.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// generateReleaseNote はバージョン1件分のリリースノートを生成する（課題タイプ別の課題一覧）
+func (mw *MarkdownWriter) generateReleaseNote(projectKey string, note ReleaseNote) (string, error) {
+	var sb strings.Builder
+	version := note.Version
+
+	// Front Matter
+	fm := newFrontMatter()
+	fm.Set("title", version.Name)
+	fm.Set("project", projectKey)
+	fm.Set("version_id", version.ID)
+	fm.Set("released", version.Released != nil && *version.Released)
+	fm.Set("archived", version.Archived != nil && *version.Archived)
+	if version.StartDate != "" {
+		fm.Set("startdate", version.StartDate)
+	}
+	if version.ReleaseDate != "" {
+		fm.Set("releasedate", version.ReleaseDate)
+	}
+	fm.Set("type", "release")
+	if err := mw.writeFrontMatter(&sb, fm); err != nil {
+		return "", err
+	}
+	sb.WriteString("\n")
+
+	// パンくずナビゲーション
+	sb.WriteString(fmt.Sprintf("[📦 %s](../../) / [🚀%s](../)\n\n", projectKey, mw.msg("release_notes")))
+	sb.WriteString(fmt.Sprintf("# %s\n\n", version.Name))
+
+	// バージョン情報
+	sb.WriteString(fmt.Sprintf("## %s\n\n", mw.msg("version_info")))
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("state"), mw.versionStateLabel(version)))
+	if version.StartDate != "" {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("start_date"), version.StartDate))
+	}
+	if version.ReleaseDate != "" {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("release_date"), version.ReleaseDate))
+	}
+	if version.Description != "" {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("description"), version.Description))
+	}
+	sb.WriteString("\n")
+
+	// 課題タイプ別の課題一覧（課題タイプは最初に出現した順）
+	sb.WriteString(fmt.Sprintf("## %s\n\n", mw.msg("count", mw.msg("issues"), len(note.Issues))))
+	var types []string
+	issuesByType := make(map[string][]cloud.Issue)
+	for _, issue := range note.Issues {
+		if issue.Fields == nil {
+			continue
+		}
+		issueType := issue.Fields.Type.Name
+		if _, exists := issuesByType[issueType]; !exists {
+			types = append(types, issueType)
+		}
+		issuesByType[issueType] = append(issuesByType[issueType], issue)
+	}
+	for _, issueType := range types {
+		issues := issuesByType[issueType]
+		icon := mw.icon(iconKindIssueType, issueType, subPageAttachments)
+		sb.WriteString(fmt.Sprintf("### %s\n\n", withIcon(icon, mw.msg("count", issueType, len(issues)))))
+		for _, issue := range issues {
+			sb.WriteString(fmt.Sprintf("- [%s](../../%s/) %s", issue.Key, issue.Key, issue.Fields.Summary))
+			if status := issue.Fields.Status; status != nil {
+				sb.WriteString(mw.msg("parenthesized", withIcon(mw.icon(iconKindStatus, status.Name, subPageAttachments), status.Name)))
+			}
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
+// releaseNotePageName はリリースノートのファイル名（拡張子なし）を返す
+func releaseNotePageName(version cloud.Version) string {
+	return fmt.Sprintf("version-%s", version.ID)
+}
+
+// versionStateLabel はバージョンの状態を表示用の文字列に変換する
+func (mw *MarkdownWriter) versionStateLabel(version cloud.Version) string {
+	state := mw.msg("version_unreleased")
+	if version.Released != nil && *version.Released {
+		state = mw.msg("version_released")
+	}
+	if version.Archived != nil && *version.Archived {
+		state = mw.msg("version_state_archived", state)
+	}
+	return state
+}
+
+// WriteSprintPages はプロジェクトのスプリント一覧（sprints/_index.md）とスプリントごとのページを生成する
+func (mw *MarkdownWriter) WriteSprintPages(projectKey string, reports []SprintReport) error {
+	sprintsDir := filepath.Join(mw.outputDir, projectKey, "sprints")
+	if err := os.MkdirAll(sprintsDir, 0755); err != nil {
+		return fmt.Errorf("スプリントディレクトリの作成に失敗しました: %w", err)
+	}
+
+	// スプリント一覧
+	var sb strings.Builder
+	fm := newFrontMatter()
+	fm.Set("title", "🏃"+mw.msg("sprints"))
+	fm.Set("project", projectKey)
+	fm.Set("type", "sprints")
+	if err := mw.writeFrontMatter(&sb, fm); err != nil {
+		return err
+	}
+	sb.WriteString("\n")
+	sb.WriteString(fmt.Sprintf("# %s\n\n", mw.msg("sprints")))
+	if len(reports) > 0 {
+		writeTableHeader(&sb, mw.msg("sprint"), mw.msg("board"), mw.msg("state"), mw.msg("period"), mw.msg("completed_committed"))
+		for _, report := range reports {
+			completed := 0
+			for _, issue := range report.Issues {
+				if issue.Completed {
+					completed++
+				}
+			}
+			sb.WriteString(fmt.Sprintf("| [%s](%s/) | %s | %s | %s | %d / %d |\n",
+				escapeTableCell(report.Sprint.Name), sprintPageName(report.Sprint),
+				escapeTableCell(report.BoardName), mw.sprintStateLabel(report.Sprint.State),
+				mw.formatSprintPeriod(report.Sprint), completed, len(report.Issues)))
+		}
+		sb.WriteString("\n")
+	}
+
+	indexPath := filepath.Join(sprintsDir, "_index.md")
+	if err := mw.writeFile(indexPath, sb.String()); err != nil {
+		return fmt.Errorf("スプリント一覧の書き込みに失敗しました: %w", err)
+	}
+
+	// スプリントごとのページ
+	for _, report := range reports {
+		content, err := mw.generateSprintPage(projectKey, report)
+		if err != nil {
+			return err
+		}
+		pagePath := filepath.Join(sprintsDir, sprintPageName(report.Sprint)+".md")
+		if err := mw.writeFile(pagePath, content); err != nil {
+			return fmt.Errorf("スプリントページの書き込みに失敗しました（%s）: %w", report.Sprint.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// generateSprintPage はスプリント1件分のページを生成する（コミットした課題と完了した課題の一覧）
+func (mw *MarkdownWriter) generateSprintPage(projectKey string, report SprintReport) (string, error) {
+	var sb strings.Builder
+	sprint := report.Sprint
+
+	// Front Matter
+	fm := newFrontMatter()
+	fm.Set("title", sprint.Name)
+	fm.Set("project", projectKey)
+	fm.Set("sprint_id", sprint.ID)
+	fm.Set("sprint_state", sprint.State)
+	if report.BoardName != "" {
+		fm.Set("board", report.BoardName)
+	}
+	if sprint.StartDate != "" {
+		fm.Set("startdate", formatSprintDate(sprint.StartDate))
+	}
+	if sprint.EndDate != "" {
+		fm.Set("enddate", formatSprintDate(sprint.EndDate))
+	}
+	fm.Set("type", "sprint")
+	if err := mw.writeFrontMatter(&sb, fm); err != nil {
+		return "", err
+	}
+	sb.WriteString("\n")
+
+	// パンくずナビゲーション
+	sb.WriteString(fmt.Sprintf("[📦 %s](../../) / [🏃%s](../)\n\n", projectKey, mw.msg("sprints")))
+	sb.WriteString(fmt.Sprintf("# %s\n\n", sprint.Name))
+
+	// スプリント情報
+	sb.WriteString(fmt.Sprintf("## %s\n\n", mw.msg("sprint_info")))
+	if report.BoardName != "" {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("board"), report.BoardName))
+	}
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("state"), mw.sprintStateLabel(sprint.State)))
+	if period := mw.formatSprintPeriod(sprint); period != "" {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("period"), period))
+	}
+	if sprint.CompleteDate != "" {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("complete_date"), formatSprintDate(sprint.CompleteDate)))
+	}
+	if sprint.Goal != "" {
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("goal"), sprint.Goal))
+	}
+	sb.WriteString("\n")
+
+	// コミットした課題
+	var completed []SprintIssueInfo
+	sb.WriteString(fmt.Sprintf("## %s\n\n", mw.msg("count", mw.msg("committed_issues"), len(report.Issues))))
+	if len(report.Issues) > 0 {
+		writeTableHeader(&sb, mw.msg("issue"), mw.msg("type"), mw.msg("summary"), mw.msg("status"), mw.msg("completed"))
+		for _, issue := range report.Issues {
+			mark := ""
+			if issue.Completed {
+				mark = "✅"
+				completed = append(completed, issue)
+			}
+			sb.WriteString(fmt.Sprintf("| [%s](%s) | %s | %s | %s | %s |\n",
+				issue.Key, sprintIssueLink(projectKey, issue),
+				withIcon(mw.icon(iconKindIssueType, issue.Type, subPageAttachments), issue.Type),
+				escapeTableCell(issue.Summary),
+				withIcon(mw.icon(iconKindStatus, issue.Status, subPageAttachments), issue.Status), mark))
+		}
+		sb.WriteString("\n")
+	}
+
+	// 完了した課題
+	sb.WriteString(fmt.Sprintf("## %s\n\n", mw.msg("count", mw.msg("completed_issues"), len(completed))))
+	for _, issue := range completed {
+		icon := mw.icon(iconKindIssueType, issue.Type, subPageAttachments)
+		sb.WriteString(fmt.Sprintf("- [%s](%s) %s\n", issue.Key, sprintIssueLink(projectKey, issue), withIcon(icon, issue.Summary)))
+	}
+	if len(completed) > 0 {
+		sb.WriteString("\n")
+	}
+
+	return sb.String(), nil
+}
+
+// sprintIssueLink はスプリントページから課題ページへの相対リンクを返す
+// 別プロジェクトの課題（複数プロジェクトにまたがるボード）はプロジェクトのディレクトリを含めてリンクする
+func sprintIssueLink(projectKey string, issue SprintIssueInfo) string {
+	if issue.Project == "" || issue.Project == projectKey {
+		return fmt.Sprintf("../../%s/", issue.Key)
+	}
+	return fmt.Sprintf("../../../%s/%s/", issue.Project, issue.Key)
+}
+
+// generateFrontMatter は課題のフロントマター（output.front_matterの形式）を生成する
+func (mw *MarkdownWriter) generateFrontMatter(sb *strings.Builder, data *IssueData, fieldNameCache FieldNameCache) error {
+	return mw.writeFrontMatter(sb, mw.issueFrontMatter(data, fieldNameCache))
+}
+
+// issueFrontMatter は課題のフロントマターの項目を作成する
+// [front_matter.fields] で指定されたフィールドは最後に追加する（既定の項目と同じキーの場合は値を置き換える）
+func (mw *MarkdownWriter) issueFrontMatter(data *IssueData, fieldNameCache FieldNameCache) *frontMatter {
+	issue := data.Issue
+	parentInfo := data.ParentInfo
+	fm := newFrontMatter()
+	fm.Set("title", issue.Fields.Summary)
+	fm.Set("date", frontMatterTime(time.Time(issue.Fields.Created)))
+	fm.Set("lastmod", frontMatterTime(time.Time(issue.Fields.Updated)))
+	fm.Set("project", issue.Fields.Project.Key)
+	fm.Set("issue_key", issue.Key)
+	fm.Set("type", "page")
+	fm.Set("issue_type", issue.Fields.Type.Name)
+
+	// 移動前のキーのURL（Hugoのaliasesで古いURLから転送する）
+	if aliases := issueAliases(issue); len(aliases) > 0 {
+		fm.Set("aliases", aliases)
+	}
 
 	// 親課題情報を追加
 	if parentInfo != nil && parentInfo.Key != "" {
-		sb.WriteString(fmt.Sprintf("parent = \"%s\"\n", parentInfo.Key))
-		sb.WriteString(fmt.Sprintf("parent_issue_type = \"%s\"\n", escapeTOMLString(parentInfo.Type)))
+		fm.Set("parent", parentInfo.Key)
+		fm.Set("parent_issue_type", parentInfo.Type)
 	}
 
 	// rank を追加（設定されたRankフィールドIDから取得）
@@ -192,55 +646,88 @@ func (mw *MarkdownWriter) generateFrontMatter(sb *strings.Builder, issue *cloud.
 	if rank, exists := customFields[mw.config.Display.RankFieldId]; exists && !IsCustomFieldEmpty(rank) {
 		rankValue := FormatCustomFieldValue(rank)
 		if rankValue != "" {
-			sb.WriteString(fmt.Sprintf("rank = \"%s\"\n", escapeTOMLString(rankValue)))
+			fm.Set("rank", rankValue)
 		}
 	}
 
 	// ラベルをtagsとして追加（Hugo taxonomy）
 	if len(issue.Fields.Labels) > 0 {
-		tags := make([]string, len(issue.Fields.Labels))
-		for i, label := range issue.Fields.Labels {
-			tags[i] = fmt.Sprintf("\"%s\"", escapeTOMLString(label))
-		}
-		sb.WriteString(fmt.Sprintf("tags = [%s]\n", strings.Join(tags, ", ")))
+		fm.Set("tags", issue.Fields.Labels)
 	}
 
 	// ステータス、担当者
-	sb.WriteString(fmt.Sprintf("status =  \"%s\"\n", issue.Fields.Status.Name))
-	sb.WriteString(fmt.Sprintf("assignee = \"%s\"\n", mw.getUser(issue.Fields.Assignee)))
+	fm.Set("status", issue.Fields.Status.Name)
+	fm.Set("assignee", mw.getUser(issue.Fields.Assignee))
 	// Start date
 	if startDate, exists := customFields["customfield_10015"]; exists && !IsCustomFieldEmpty(startDate) {
 		fieldValue := FormatCustomFieldValue(startDate)
 		if fieldValue != "" {
-			sb.WriteString(fmt.Sprintf("startdate = \"%s\"\n", fieldValue))
+			fm.Set("startdate", fieldValue)
 		}
 	}
 	// 期限
 	duedate := time.Time(issue.Fields.Duedate)
 	if !duedate.IsZero() {
-		sb.WriteString(fmt.Sprintf("duedate = \"%s\"\n", duedate.Format("2006-01-02")))
+		fm.Set("duedate", duedate.Format("2006-01-02"))
 	}
 
 	// 修正バージョン（Fix Versions）
 	if len(issue.Fields.FixVersions) > 0 {
 		versions := make([]string, len(issue.Fields.FixVersions))
 		for i, v := range issue.Fields.FixVersions {
-			versions[i] = fmt.Sprintf("\"%s\"", escapeTOMLString(v.Name))
+			versions[i] = v.Name
 		}
-		sb.WriteString(fmt.Sprintf("fix_versions = [%s]\n", strings.Join(versions, ", ")))
+		fm.Set("fix_versions", versions)
 	}
 
 	// 影響バージョン（Affected Versions）
 	if len(issue.Fields.AffectsVersions) > 0 {
 		versions := make([]string, len(issue.Fields.AffectsVersions))
 		for i, v := range issue.Fields.AffectsVersions {
-			versions[i] = fmt.Sprintf("\"%s\"", escapeTOMLString(v.Name))
+			versions[i] = v.Name
 		}
-		sb.WriteString(fmt.Sprintf("affected_versions = [%s]\n", strings.Join(versions, ", ")))
+		fm.Set("affected_versions", versions)
 	}
 
-	sb.WriteString("+++\n\n")
+	// スプリント（所属したスプリントの履歴順）
+	if sprints := mw.issueSprints(issue); len(sprints) > 0 {
+		fm.Set("sprint", sprintNames(sprints))
+	}
+
+	// 作業ログの合計時間（設定で有効な場合のみ）
+	if mw.config != nil && mw.config.Display.WorklogFrontMatter {
+		if total := sumWorklogSeconds(data.Worklogs); total > 0 {
+			fm.Set("worklog_total", mw.formatTimeSeconds(total))
+		}
+	}
 
+	mw.setMappedFields(fm, issue, fieldNameCache, BuildFieldSchemaCache(data.Fields))
+	return fm
+}
+
+// issueAliases は課題が別プロジェクトへ移動される前のキーのページのURL（/<プロジェクト>/<キー>/）を返す
+// 移動前のキーは変更履歴の「Key」項目から取得する
+func issueAliases(issue *cloud.Issue) []string {
+	if issue.Changelog == nil {
+		return nil
+	}
+	var aliases []string
+	seen := map[string]bool{issue.Key: true}
+	for _, history := range issue.Changelog.Histories {
+		for _, item := range history.Items {
+			oldKey := item.FromString
+			if !strings.EqualFold(item.Field, "key") || oldKey == "" || seen[oldKey] {
+				continue
+			}
+			seen[oldKey] = true
+			projectKey := oldKey
+			if i := strings.LastIndex(oldKey, "-"); i > 0 {
+				projectKey = oldKey[:i]
+			}
+			aliases = append(aliases, fmt.Sprintf("/%s/%s/", projectKey, oldKey))
+		}
+	}
+	return aliases
 }
 
 // isHiddenCustomField は指定されたカスタムフィールドIDが非表示設定になっているかチェックする
@@ -256,40 +743,45 @@ func (mw *MarkdownWriter) isHiddenCustomField(fieldID string) bool {
 	return false
 }
 
-// generateTitle は課題のタイトルを生成する
-func (mw *MarkdownWriter) generateTitle(sb *strings.Builder, issue *cloud.Issue, parentInfo *ParentIssueInfo) {
-	projectIcon := "📦"
-	projectLink := fmt.Sprintf("[%s %s](../)", projectIcon, issue.Fields.Project.Name)
-	issueIcon := getIssueTypeIcon(issue.Fields.Type.Name)
-	issueLink := fmt.Sprintf("[%s %s](../%s/)", issueIcon, issue.Key, issue.Key)
+// generateBreadcrumb は課題のパンくずリスト（プロジェクト / 親課題 / 課題）を生成する
+func (mw *MarkdownWriter) generateBreadcrumb(sb *strings.Builder, issue *cloud.Issue, parentInfo *ParentIssueInfo) {
+	projectIcon := mw.projectIcon(issue.Fields.Project.Name)
+	projectLink := fmt.Sprintf("[%s](../)", withIcon(projectIcon, issue.Fields.Project.Name))
+	issueIcon := mw.icon(iconKindIssueType, issue.Fields.Type.Name, issuePageAttachments)
+	issueLink := fmt.Sprintf("[%s](../%s/)", withIcon(issueIcon, issue.Key), issue.Key)
 
 	if parentInfo != nil && parentInfo.Key != "" {
-		parentIcon := getIssueTypeIcon(parentInfo.Type)
-		parentLink := fmt.Sprintf("[%s %s](../%s/)", parentIcon, parentInfo.Key, parentInfo.Key)
-		sb.WriteString(fmt.Sprintf("%s / %s / %s\n\n", projectLink, parentLink, issueLink))
+		parentIcon := mw.icon(iconKindIssueType, parentInfo.Type, issuePageAttachments)
+		parentLink := fmt.Sprintf("[%s](../%s/)", withIcon(parentIcon, parentInfo.Key), parentInfo.Key)
+		sb.WriteString(fmt.Sprintf("%s / %s / %s", projectLink, parentLink, issueLink))
 	} else {
-		sb.WriteString(fmt.Sprintf("%s / %s\n\n", projectLink, issueLink))
+		sb.WriteString(fmt.Sprintf("%s / %s", projectLink, issueLink))
 	}
-	sb.WriteString(fmt.Sprintf("# %s\n\n", issue.Fields.Summary))
 }
 
-// generateBasicInfo は基本情報セクションを生成する
-func (mw *MarkdownWriter) generateBasicInfo(sb *strings.Builder, issue *cloud.Issue, fieldNameCache FieldNameCache, devStatus *DevStatusDetail) {
-	sb.WriteString("## 基本情報\n\n")
-	sb.WriteString(fmt.Sprintf("- **課題キー**: %s\n", issue.Key))
-	sb.WriteString(fmt.Sprintf("- **課題タイプ**: %s\n", issue.Fields.Type.Name))
-	sb.WriteString(fmt.Sprintf("- **ステータス**: %s\n", issue.Fields.Status.Name))
-	sb.WriteString(fmt.Sprintf("- **優先度**: %s\n", mw.getFieldString(issue.Fields.Priority)))
-	sb.WriteString(fmt.Sprintf("- **担当者**: %s\n", mw.getUser(issue.Fields.Assignee)))
-	sb.WriteString(fmt.Sprintf("- **報告者**: %s\n", mw.getUser(issue.Fields.Reporter)))
-	sb.WriteString(fmt.Sprintf("- **作成日**: %s\n", mw.formatTime(issue.Fields.Created)))
-	sb.WriteString(fmt.Sprintf("- **更新日**: %s\n", mw.formatTime(issue.Fields.Updated)))
+// generateBasicInfo は基本情報セクションの本文を生成する
+// カスタムフィールドの値はフィールドのスキーマ（fieldSchemas）に従って変換する
+func (mw *MarkdownWriter) generateBasicInfo(sb *strings.Builder, issue *cloud.Issue, fieldNameCache FieldNameCache, fieldSchemas FieldSchemaCache, devStatus *DevStatusDetail) {
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("issue_key"), issue.Key))
+	typeIcon := mw.configuredIcon(iconKindIssueType, issue.Fields.Type.Name, issuePageAttachments)
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("issue_type"), withIcon(typeIcon, issue.Fields.Type.Name)))
+	statusIcon := mw.icon(iconKindStatus, issue.Fields.Status.Name, issuePageAttachments)
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("status"), withIcon(statusIcon, issue.Fields.Status.Name)))
+	priority := mw.getFieldString(issue.Fields.Priority)
+	if issue.Fields.Priority != nil {
+		priority = withIcon(mw.icon(iconKindPriority, issue.Fields.Priority.Name, issuePageAttachments), priority)
+	}
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("priority"), priority))
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("assignee"), mw.getUser(issue.Fields.Assignee)))
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("reporter"), mw.getUser(issue.Fields.Reporter)))
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("created"), mw.formatTime(issue.Fields.Created)))
+	sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("updated"), mw.formatTime(issue.Fields.Updated)))
 
 	// Start date（カスタムフィールド）をここに表示
 	customFields := GetAllCustomFields(issue)
 	if startDate, exists := customFields["customfield_10015"]; exists && !IsCustomFieldEmpty(startDate) {
-		fieldName := fieldNameCache.GetFieldName("customfield_10015")
-		fieldValue := FormatCustomFieldValue(startDate)
+		fieldName := fieldNameCache.fieldName("customfield_10015", mw.messages)
+		fieldValue := mw.formatFieldValue("customfield_10015", startDate, fieldSchemas, fieldNameCache)
 		if fieldValue != "" {
 			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", fieldName, fieldValue))
 		}
@@ -298,12 +790,12 @@ func (mw *MarkdownWriter) generateBasicInfo(sb *strings.Builder, issue *cloud.Is
 	// 期限が設定されている場合のみ出力
 	duedate := time.Time(issue.Fields.Duedate)
 	if !duedate.IsZero() {
-		sb.WriteString(fmt.Sprintf("- **期限**: %s\n", duedate.Format("2006-01-02")))
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("due_date"), duedate.Format("2006-01-02")))
 	}
 
 	// ラベルが設定されている場合のみ出力
 	if len(issue.Fields.Labels) > 0 {
-		sb.WriteString(fmt.Sprintf("- **ラベル**: %s\n", strings.Join(issue.Fields.Labels, ", ")))
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("labels"), strings.Join(issue.Fields.Labels, ", ")))
 	}
 
 	// 修正バージョンが設定されている場合のみ出力
@@ -312,7 +804,7 @@ func (mw *MarkdownWriter) generateBasicInfo(sb *strings.Builder, issue *cloud.Is
 		for i, v := range issue.Fields.FixVersions {
 			versions[i] = v.Name
 		}
-		sb.WriteString(fmt.Sprintf("- **修正バージョン**: %s\n", strings.Join(versions, ", ")))
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("fix_versions"), strings.Join(versions, ", ")))
 	}
 
 	// 影響バージョンが設定されている場合のみ出力
@@ -321,12 +813,12 @@ func (mw *MarkdownWriter) generateBasicInfo(sb *strings.Builder, issue *cloud.Is
 		for i, v := range issue.Fields.AffectsVersions {
 			versions[i] = v.Name
 		}
-		sb.WriteString(fmt.Sprintf("- **影響バージョン**: %s\n", strings.Join(versions, ", ")))
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("affects_versions"), strings.Join(versions, ", ")))
 	}
 
 	// 親課題が設定されている場合のみ出力
 	if issue.Fields.Parent != nil && issue.Fields.Parent.Key != "" {
-		sb.WriteString(fmt.Sprintf("- **親課題**: [%s](../%s/)\n", issue.Fields.Parent.Key, issue.Fields.Parent.Key))
+		sb.WriteString(fmt.Sprintf("- **%s**: [%s](../%s/)\n", mw.msg("parent"), issue.Fields.Parent.Key, issue.Fields.Parent.Key))
 	}
 
 	// 時間管理情報（値がある場合のみ出力）
@@ -335,15 +827,15 @@ func (mw *MarkdownWriter) generateBasicInfo(sb *strings.Builder, issue *cloud.Is
 
 		if tt.OriginalEstimateSeconds > 0 {
 			timeStr := mw.formatTimeSeconds(tt.OriginalEstimateSeconds)
-			sb.WriteString(fmt.Sprintf("- **初期見積り**: %s\n", timeStr))
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("original_estimate"), timeStr))
 		}
 		if tt.RemainingEstimateSeconds > 0 {
 			timeStr := mw.formatTimeSeconds(tt.RemainingEstimateSeconds)
-			sb.WriteString(fmt.Sprintf("- **残り時間**: %s\n", timeStr))
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("remaining_estimate"), timeStr))
 		}
 		if tt.TimeSpentSeconds > 0 {
 			timeStr := mw.formatTimeSeconds(tt.TimeSpentSeconds)
-			sb.WriteString(fmt.Sprintf("- **作業時間**: %s\n", timeStr))
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("time_spent"), timeStr))
 		}
 	}
 
@@ -351,20 +843,20 @@ func (mw *MarkdownWriter) generateBasicInfo(sb *strings.Builder, issue *cloud.Is
 	if aggTime := extractAggregateTimeFields(issue); aggTime != nil {
 		if aggTime.AggregateTimeOriginalEstimate > 0 {
 			timeStr := mw.formatTimeSeconds(aggTime.AggregateTimeOriginalEstimate)
-			sb.WriteString(fmt.Sprintf("- **Σ初期見積り**: %s\n", timeStr))
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("aggregate_original_estimate"), timeStr))
 		}
 		if aggTime.AggregateTimeEstimate > 0 {
 			timeStr := mw.formatTimeSeconds(aggTime.AggregateTimeEstimate)
-			sb.WriteString(fmt.Sprintf("- **Σ残り時間**: %s\n", timeStr))
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("aggregate_remaining_estimate"), timeStr))
 		}
 		if aggTime.AggregateTimeSpent > 0 {
 			timeStr := mw.formatTimeSeconds(aggTime.AggregateTimeSpent)
-			sb.WriteString(fmt.Sprintf("- **Σ作業時間**: %s\n", timeStr))
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("aggregate_time_spent"), timeStr))
 		}
 	}
 
 	if issue.Fields.Resolution != nil {
-		sb.WriteString(fmt.Sprintf("- **解決状況**: %s\n", issue.Fields.Resolution.Name))
+		sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("resolution"), issue.Fields.Resolution.Name))
 	}
 
 	// カスタムフィールド（Start dateとRankを除外、値があるもののみ表示）
@@ -380,14 +872,20 @@ func (mw *MarkdownWriter) generateBasicInfo(sb *strings.Builder, issue *cloud.Is
 			if IsCustomFieldEmpty(customFields[key]) {
 				continue
 			}
-			fieldName := fieldNameCache.GetFieldName(key)
+			fieldName := fieldNameCache.fieldName(key, mw.messages)
 
 			// 開発フィールドの場合は詳細情報付きでフォーマット
 			var fieldValue string
 			if fieldMap, ok := customFields[key].(map[string]interface{}); ok && isDevelopmentField(fieldMap) {
 				fieldValue = FormatDevelopmentFieldWithDetails(fieldMap, devStatus)
+			} else if doc, ok := parseADF(customFields[key]); ok {
+				// 複数行テキストのカスタムフィールド（ADF）は変換して、2行目以降をリスト項目の続きとして字下げする
+				fieldValue = indentContinuation(mw.convertADFToMarkdown(doc, nil), 2)
+			} else if mw.config != nil && key == mw.config.Display.SprintFieldId {
+				// Sprintフィールドはスプリント名のみ表示（詳細はスプリントセクションに出力）
+				fieldValue = strings.Join(sprintNames(ParseSprintField(customFields[key])), ", ")
 			} else {
-				fieldValue = FormatCustomFieldValue(customFields[key])
+				fieldValue = mw.formatFieldValue(key, customFields[key], fieldSchemas, fieldNameCache)
 			}
 
 			// 値が空の場合はスキップ（開発フィールドの詳細表示が空の場合も含む）
@@ -399,90 +897,168 @@ func (mw *MarkdownWriter) generateBasicInfo(sb *strings.Builder, issue *cloud.Is
 			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", fieldName, fieldValue))
 		}
 	}
-
-	sb.WriteString("\n")
 }
 
-// generateDevelopmentInfo は開発情報セクションを生成する
+// generateDevelopmentInfo は開発情報セクションの本文を生成する
 func (mw *MarkdownWriter) generateDevelopmentInfo(sb *strings.Builder, devStatus *DevStatusDetail) {
 	// 開発情報セクション（devStatusがある場合のみ）
 	if devStatus != nil && len(devStatus.Detail) > 0 {
-		sb.WriteString("## 開発情報\n\n")
-
+		separator := ""
 		for _, detail := range devStatus.Detail {
 			// ブランチ（最初に出力、JIRA仕様に合わせる）
 			if len(detail.Branches) > 0 {
-				sb.WriteString("### ブランチ\n\n")
+				sb.WriteString(fmt.Sprintf("%s### %s\n\n", separator, mw.msg("branches")))
 				for _, branch := range detail.Branches {
 					sb.WriteString(fmt.Sprintf("- [`%s`](%s)\n", branch.Name, branch.URL))
 				}
-				sb.WriteString("\n")
+				separator = "\n"
 			}
 
 			// プルリクエスト（最後に出力、JIRA仕様に合わせる）
 			if len(detail.PullRequests) > 0 {
-				sb.WriteString("### プルリクエスト\n\n")
+				sb.WriteString(fmt.Sprintf("%s### %s\n\n", separator, mw.msg("pull_requests")))
 				for _, pr := range detail.PullRequests {
 					sb.WriteString(fmt.Sprintf("- [%s](%s)\n", pr.Name, pr.URL))
 					if pr.Author.Name != "" {
-						sb.WriteString(fmt.Sprintf("  - 作成者: %s\n", pr.Author.Name))
+						sb.WriteString(fmt.Sprintf("  - %s: %s\n", mw.msg("author"), pr.Author.Name))
 					}
 					if pr.Source.Branch != "" {
-						sb.WriteString(fmt.Sprintf("  - ブランチ: `%s`\n", pr.Source.Branch))
+						sb.WriteString(fmt.Sprintf("  - %s: `%s`\n", mw.msg("branch"), pr.Source.Branch))
 					}
 					if pr.Status != "" {
-						sb.WriteString(fmt.Sprintf("  - 状態: %s\n", pr.Status))
+						sb.WriteString(fmt.Sprintf("  - %s: %s\n", mw.msg("state"), pr.Status))
 					}
 				}
-				sb.WriteString("\n")
+				separator = "\n"
 			}
 		}
 	}
 }
 
-// generateDescription は説明セクションを生成する
-func (mw *MarkdownWriter) generateDescription(sb *strings.Builder, issue *cloud.Issue, attachmentMap map[string]string) {
+// generateDescription は説明セクションの本文を生成する
+// 説明がADFの場合（display.adfで取得した場合、または値がADFの場合）はADFから変換する
+// useRenderedの場合（display.sourceの判定結果）はレンダリング済みのHTMLから変換する
+func (mw *MarkdownWriter) generateDescription(sb *strings.Builder, data *IssueData, attachmentMap map[string]string, useRendered bool) {
+	issue := data.Issue
+	if data.ADF != nil && data.ADF.Description != nil {
+		if description := mw.convertADFToMarkdown(data.ADF.Description, attachmentMap); description != "" {
+			sb.WriteString(description)
+			sb.WriteString("\n")
+		}
+		return
+	}
 	if issue.Fields.Description != "" {
-		sb.WriteString("## 説明\n\n")
-		description := issue.Fields.Description
-		// JIRAマークアップをMarkdownに変換
-		description = mw.convertJIRAMarkupToMarkdown(description)
-		// 画像参照を変換
-		description = mw.replaceImageReferences(description, attachmentMap)
-		sb.WriteString(description)
-		sb.WriteString("\n\n")
+		renderedHTML := ""
+		if issue.RenderedFields != nil {
+			renderedHTML = issue.RenderedFields.Description
+		}
+		sb.WriteString(mw.convertIssueText(issue.Fields.Description, renderedHTML, useRendered, issue, attachmentMap))
+		sb.WriteString("\n")
 	}
 }
 
-// generateComments はコメントセクションを生成する（昇順：古いコメントが先）
-func (mw *MarkdownWriter) generateComments(sb *strings.Builder, issue *cloud.Issue, attachmentMap map[string]string) {
-	if issue.Fields.Comments != nil && len(issue.Fields.Comments.Comments) > 0 {
-		sb.WriteString("## コメント\n\n")
-		comments := issue.Fields.Comments.Comments
-		// 昇順（古い順）で出力
-		for _, comment := range comments {
-			authorName := mw.getUser(comment.Author)
-			dateStr := mw.formatCommentDate(comment.Created)
+// convertText はWiki記法またはADFのテキストをMarkdownに変換する
+func (mw *MarkdownWriter) convertText(text string, attachmentMap map[string]string) string {
+	if doc, ok := parseADF(text); ok {
+		return mw.convertADFToMarkdown(doc, attachmentMap)
+	}
+	// JIRAマークアップをMarkdownに変換
+	text = mw.convertJIRAMarkupToMarkdown(text)
+	// 画像参照を変換
+	return mw.replaceImageReferences(text, attachmentMap)
+}
 
-			// 返信かどうかを判定（本文が[~accountid:で始まる場合）
-			isReply := strings.HasPrefix(comment.Body, "[~accountid:")
+// generateComments はコメントセクションの本文を生成する（昇順：古いコメントが先）
+// 公開範囲が制限されたコメントはdisplay.restricted_commentsの設定に従って印を付けるか除外する
+// useRenderedの場合（display.sourceの判定結果）はレンダリング済みのHTMLから変換する
+func (mw *MarkdownWriter) generateComments(sb *strings.Builder, data *IssueData, attachmentMap map[string]string, useRendered bool) {
+	comments := data.Comments
+	if comments == nil {
+		comments = issueComments(data.Issue)
+	}
 
-			// タイトル: 投稿者名 投稿日（返信の場合は↩️を付ける）
-			if isReply {
-				sb.WriteString(fmt.Sprintf("↩️ %s %s\n\n---\n\n", authorName, dateStr))
-			} else {
-				sb.WriteString(fmt.Sprintf("%s %s\n\n---\n\n", authorName, dateStr))
-			}
+	restrictedMode := "mark"
+	if mw.config != nil && mw.config.Display.RestrictedComments != "" {
+		restrictedMode = mw.config.Display.RestrictedComments
+	}
 
-			commentBody := comment.Body
-			// JIRAマークアップをMarkdownに変換
-			commentBody = mw.convertJIRAMarkupToMarkdown(commentBody)
-			// 画像参照を変換
-			commentBody = mw.replaceImageReferences(commentBody, attachmentMap)
-			sb.WriteString(commentBody)
-			sb.WriteString("\n\n")
+	separator := ""
+	// 昇順（古い順）で出力
+	for _, comment := range comments {
+		restriction := mw.commentRestriction(comment)
+		if restriction != "" && restrictedMode == "omit" {
+			continue
 		}
+
+		authorName := mw.getUser(comment.Author)
+		dateStr := mw.formatCommentDate(comment.Created)
+
+		// 本文のADF（display.adfで取得した場合、または本文がADFの場合）
+		var bodyADF *ADFNode
+		if data.ADF != nil {
+			bodyADF = data.ADF.Comments[comment.ID]
+		}
+		if bodyADF == nil {
+			bodyADF, _ = parseADF(comment.Body)
+		}
+
+		// 返信かどうかを判定（本文がメンションで始まる場合）
+		isReply := strings.HasPrefix(comment.Body, "[~accountid:")
+		if bodyADF != nil {
+			isReply = adfStartsWithMention(bodyADF)
+		}
+
+		// タイトル: 投稿者名 投稿日（返信の場合は↩️を付ける）
+		title := fmt.Sprintf("%s %s", authorName, dateStr)
+		if isReply {
+			title = "↩️ " + title
+		}
+		// 公開範囲が制限されている場合は🔒と公開範囲を付ける
+		if restriction != "" && restrictedMode == "mark" {
+			title = mw.msg("comment_restricted", title, restriction)
+		}
+		sb.WriteString(fmt.Sprintf("%s%s\n\n---\n\n", separator, title))
+		separator = "\n"
+
+		if bodyADF != nil {
+			sb.WriteString(mw.convertADFToMarkdown(bodyADF, attachmentMap))
+		} else {
+			sb.WriteString(mw.convertIssueText(comment.Body, renderedCommentBody(data.Issue, comment), useRendered, data.Issue, attachmentMap))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// issueComments は課題に含まれるコメントをIssueCommentのリストに変換する
+func issueComments(issue *cloud.Issue) []IssueComment {
+	if issue.Fields == nil || issue.Fields.Comments == nil {
+		return nil
+	}
+	comments := make([]IssueComment, 0, len(issue.Fields.Comments.Comments))
+	for _, comment := range issue.Fields.Comments.Comments {
+		if comment != nil {
+			comments = append(comments, IssueComment{Comment: *comment})
+		}
+	}
+	return comments
+}
+
+// commentRestriction はコメントの公開範囲の説明を返す（制限されていない場合は空文字列）
+func (mw *MarkdownWriter) commentRestriction(comment IssueComment) string {
+	if comment.Visibility != nil && comment.Visibility.Value != "" {
+		switch comment.Visibility.Type {
+		case "role":
+			return mw.msg("comment_role", comment.Visibility.Value)
+		case "group":
+			return mw.msg("comment_group", comment.Visibility.Value)
+		default:
+			return comment.Visibility.Value
+		}
+	}
+	if comment.JSDPublic != nil && !*comment.JSDPublic {
+		return mw.msg("comment_internal")
 	}
+	return ""
 }
 
 // formatCommentDate はコメント用の日付フォーマット（yyyy-mm-dd hh:mm）
@@ -499,38 +1075,38 @@ func (mw *MarkdownWriter) formatCommentDate(timeStr string) string {
 	return t.Format("2006-01-02 15:04")
 }
 
-// generateSubtasks はサブタスクセクションを生成する
+// generateSubtasks はサブタスクセクションの本文を生成する
 func (mw *MarkdownWriter) generateSubtasks(sb *strings.Builder, issue *cloud.Issue) {
 	if len(issue.Fields.Subtasks) > 0 {
-		sb.WriteString("## サブタスク\n\n")
 		for _, subtask := range issue.Fields.Subtasks {
-			sb.WriteString(fmt.Sprintf("- **[%s](../%s/)**: %s", subtask.Key, subtask.Key, subtask.Fields.Summary))
-			if subtask.Fields.Status != nil {
-				sb.WriteString(fmt.Sprintf(" [%s]", subtask.Fields.Status.Name))
+			link := fmt.Sprintf("**[%s](../%s/)**", subtask.Key, subtask.Key)
+			if subtask.Fields.Type.Name != "" {
+				link = withIcon(mw.configuredIcon(iconKindIssueType, subtask.Fields.Type.Name, issuePageAttachments), link)
+			}
+			sb.WriteString(fmt.Sprintf("- %s: %s", link, subtask.Fields.Summary))
+			if status := subtask.Fields.Status; status != nil {
+				sb.WriteString(fmt.Sprintf(" [%s]", withIcon(mw.icon(iconKindStatus, status.Name, issuePageAttachments), status.Name)))
 			}
 			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
 	}
 }
 
-// generateChildIssues は子作業項目セクションを生成する
+// generateChildIssues は子作業項目セクションの本文を生成する
 func (mw *MarkdownWriter) generateChildIssues(sb *strings.Builder, childIssues []ChildIssueInfo) {
 	if len(childIssues) > 0 {
-		sb.WriteString("## 子作業項目\n\n")
 		for _, child := range childIssues {
-			icon := getIssueTypeIcon(child.Type)
-			sb.WriteString(fmt.Sprintf("- %s **[%s](../%s/)**: %s", icon, child.Key, child.Key, child.Summary))
+			icon := mw.icon(iconKindIssueType, child.Type, issuePageAttachments)
+			sb.WriteString(fmt.Sprintf("- %s: %s", withIcon(icon, fmt.Sprintf("**[%s](../%s/)**", child.Key, child.Key)), child.Summary))
 			if child.Status != "" {
-				sb.WriteString(fmt.Sprintf(" [%s]", child.Status))
+				sb.WriteString(fmt.Sprintf(" [%s]", withIcon(mw.icon(iconKindStatus, child.Status, issuePageAttachments), child.Status)))
 			}
 			sb.WriteString("\n")
 		}
-		sb.WriteString("\n")
 	}
 }
 
-// generateConfluenceLinks はConfluenceコンテンツセクションを生成する
+// generateConfluenceLinks はConfluenceコンテンツセクションの本文を生成する
 func (mw *MarkdownWriter) generateConfluenceLinks(sb *strings.Builder, remoteLinks []cloud.RemoteLink) {
 	// Confluenceリンクのみフィルタ
 	var confluenceLinks []cloud.RemoteLink
@@ -545,55 +1121,52 @@ func (mw *MarkdownWriter) generateConfluenceLinks(sb *strings.Builder, remoteLin
 		return
 	}
 
-	sb.WriteString("## Confluenceコンテンツ\n\n")
 	for _, link := range confluenceLinks {
 		if link.Object != nil {
 			title := link.Object.Title
 			if title == "" {
-				title = "Confluence Page"
+				title = mw.msg("confluence_page")
 			}
 			sb.WriteString(fmt.Sprintf("- [%s](%s)\n", title, link.Object.URL))
 		}
 	}
-	sb.WriteString("\n")
 }
 
-// generateIssueLinks は関連リンクセクションを生成する
+// generateIssueLinks は関連リンクセクションの本文を生成する
 func (mw *MarkdownWriter) generateIssueLinks(sb *strings.Builder, issue *cloud.Issue) {
 	if len(issue.Fields.IssueLinks) > 0 {
-		sb.WriteString("## 関連リンク\n\n")
 		for _, link := range issue.Fields.IssueLinks {
 			if link.OutwardIssue != nil {
-				sb.WriteString(fmt.Sprintf("- **%s**: [%s](../%s/)", link.Type.Outward, link.OutwardIssue.Key, link.OutwardIssue.Key))
-				if link.OutwardIssue.Fields != nil {
-					sb.WriteString(fmt.Sprintf(" - %s", link.OutwardIssue.Fields.Summary))
-					if link.OutwardIssue.Fields.Status != nil {
-						sb.WriteString(fmt.Sprintf(" [%s]", link.OutwardIssue.Fields.Status.Name))
-					}
-				}
-				sb.WriteString("\n")
+				mw.writeIssueLink(sb, link.Type.Outward, link.OutwardIssue)
 			}
 
 			// Inward issue（他の課題がこの課題に対して持つ関連）
 			if link.InwardIssue != nil {
-				sb.WriteString(fmt.Sprintf("- **%s**: [%s](../%s/)", link.Type.Inward, link.InwardIssue.Key, link.InwardIssue.Key))
-				if link.InwardIssue.Fields != nil {
-					sb.WriteString(fmt.Sprintf(" - %s", link.InwardIssue.Fields.Summary))
-					if link.InwardIssue.Fields.Status != nil {
-						sb.WriteString(fmt.Sprintf(" [%s]", link.InwardIssue.Fields.Status.Name))
-					}
-				}
-				sb.WriteString("\n")
+				mw.writeIssueLink(sb, link.Type.Inward, link.InwardIssue)
 			}
 		}
-		sb.WriteString("\n")
 	}
 }
 
-// generateAttachments は添付ファイルセクションを生成する
+// writeIssueLink は関連リンクの1行（関連の種類: 課題タイプのアイコン 課題キー - 概要 [ステータス]）を出力する
+func (mw *MarkdownWriter) writeIssueLink(sb *strings.Builder, linkType string, linked *cloud.Issue) {
+	link := fmt.Sprintf("[%s](../%s/)", linked.Key, linked.Key)
+	if linked.Fields != nil && linked.Fields.Type.Name != "" {
+		link = withIcon(mw.configuredIcon(iconKindIssueType, linked.Fields.Type.Name, issuePageAttachments), link)
+	}
+	sb.WriteString(fmt.Sprintf("- **%s**: %s", linkType, link))
+	if linked.Fields != nil {
+		sb.WriteString(fmt.Sprintf(" - %s", linked.Fields.Summary))
+		if status := linked.Fields.Status; status != nil {
+			sb.WriteString(fmt.Sprintf(" [%s]", withIcon(mw.icon(iconKindStatus, status.Name, issuePageAttachments), status.Name)))
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// generateAttachments は添付ファイルセクションの本文を生成する
 func (mw *MarkdownWriter) generateAttachments(sb *strings.Builder, attachmentFiles []string) {
 	if len(attachmentFiles) > 0 {
-		sb.WriteString("## 添付ファイル\n\n")
 		for _, filename := range attachmentFiles {
 			// ファイル名をURLエンコーディング（スペース→%20）
 			encodedFilename := url.PathEscape(filename)
@@ -601,82 +1174,206 @@ func (mw *MarkdownWriter) generateAttachments(sb *strings.Builder, attachmentFil
 			relPath := fmt.Sprintf("../../attachments/%s", encodedFilename)
 			sb.WriteString(fmt.Sprintf("- [%s](%s)\n", filename, relPath))
 		}
-		sb.WriteString("\n")
 	}
 }
 
-// generateChangeHistory は変更履歴セクションを生成する
-func (mw *MarkdownWriter) generateChangeHistory(sb *strings.Builder, issue *cloud.Issue) {
-	if issue.Changelog != nil && len(issue.Changelog.Histories) > 0 {
-		sb.WriteString("## 変更履歴\n\n")
-		for i, history := range issue.Changelog.Histories {
-			sb.WriteString(fmt.Sprintf("### 変更 %d\n\n", i+1))
-			sb.WriteString(fmt.Sprintf("- **変更者**: %s\n", mw.getUser(&history.Author)))
-			sb.WriteString(fmt.Sprintf("- **変更日**: %s\n", mw.formatTimeString(history.Created)))
-			sb.WriteString("\n")
-
-			for _, item := range history.Items {
-				sb.WriteString(fmt.Sprintf("- **%s**: `%s` → `%s`\n", item.Field, item.FromString, item.ToString))
-			}
-			sb.WriteString("\n")
-		}
+// generateWorklogs は作業ログセクションの本文を生成する（作業ログの一覧と作業者別の合計）
+func (mw *MarkdownWriter) generateWorklogs(sb *strings.Builder, worklogs []cloud.WorklogRecord) {
+	if len(worklogs) == 0 {
+		return
 	}
-}
 
-// generateMarkdown は課題情報からMarkdownコンテンツを生成する
-func (mw *MarkdownWriter) generateMarkdown(issue *cloud.Issue, attachmentFiles []string, fieldNameCache FieldNameCache, devStatus *DevStatusDetail, parentInfo *ParentIssueInfo, childIssues []ChildIssueInfo, remoteLinks []cloud.RemoteLink) string {
-	var sb strings.Builder
+	writeTableHeader(sb, mw.msg("worklog_author"), mw.msg("worklog_started"), mw.msg("time_spent"), mw.msg("worklog_comment"))
 
-	// 添付ファイルのマッピングを作成（元のファイル名 → 保存されたファイル名）
-	attachmentMap := mw.buildAttachmentMap(issue, attachmentFiles)
+	// 作業者別の合計（最初に記録した順に並べる）
+	var authors []string
+	subtotals := make(map[string]int)
+	for _, worklog := range worklogs {
+		authorName := mw.getUser(worklog.Author)
+		started := ""
+		if worklog.Started != nil {
+			started = time.Time(*worklog.Started).Format("2006-01-02 15:04")
+		}
+		comment := mw.convertJIRAMarkupToMarkdown(worklog.Comment)
+		sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s |\n",
+			escapeTableCell(authorName), started, mw.formatTimeSeconds(worklog.TimeSpentSeconds), escapeTableCell(comment)))
 
-	// Front Matter
-	mw.generateFrontMatter(&sb, issue, parentInfo)
+		if _, exists := subtotals[authorName]; !exists {
+			authors = append(authors, authorName)
+		}
+		subtotals[authorName] += worklog.TimeSpentSeconds
+	}
+	sb.WriteString("\n")
 
-	// タイトル
-	mw.generateTitle(&sb, issue, parentInfo)
+	sb.WriteString(fmt.Sprintf("### %s\n\n", mw.msg("worklog_total_by_author")))
+	writeTableHeader(sb, mw.msg("worklog_author"), mw.msg("time_spent"))
+	for _, author := range authors {
+		sb.WriteString(fmt.Sprintf("| %s | %s |\n", escapeTableCell(author), mw.formatTimeSeconds(subtotals[author])))
+	}
+	sb.WriteString(fmt.Sprintf("| **%s** | **%s** |\n", mw.msg("total"), mw.formatTimeSeconds(sumWorklogSeconds(worklogs))))
+}
 
-	sb.WriteString("<!-- PAGE_RIGHT_START -->\n\n")
+// sumWorklogSeconds は作業ログの作業時間の合計（秒）を返す
+func sumWorklogSeconds(worklogs []cloud.WorklogRecord) int {
+	total := 0
+	for _, worklog := range worklogs {
+		total += worklog.TimeSpentSeconds
+	}
+	return total
+}
 
-	// 基本情報
-	mw.generateBasicInfo(&sb, issue, fieldNameCache, devStatus)
+// writeTableHeader はMarkdownテーブルの見出し行と区切り行を書き込む
+func writeTableHeader(sb *strings.Builder, labels ...string) {
+	header := "|"
+	separator := "|"
+	for _, label := range labels {
+		header += " " + label + " |"
+		// 区切り行は見出しの表示幅（全角文字は2文字分）に合わせる
+		separator += strings.Repeat("-", displayWidth(label)+2) + "|"
+	}
+	sb.WriteString(header + "\n" + separator + "\n")
+}
+
+// displayWidth は文字列の表示幅（全角文字を2、半角文字を1とする）を返す
+func displayWidth(s string) int {
+	width := 0
+	for _, r := range s {
+		if r >= 0x1100 {
+			width += 2
+		} else {
+			width++
+		}
+	}
+	return width
+}
 
-	// 開発情報
-	mw.generateDevelopmentInfo(&sb, devStatus)
+// tableCellLineBreakPattern はテーブルのセル内の改行（前後の空白を含む）にマッチする
+var tableCellLineBreakPattern = regexp.MustCompile(`[ \t]*\r?\n[ \t]*`)
 
-	sb.WriteString("<!-- PAGE_RIGHT_END -->\n\n")
+// escapeTableCell はMarkdownテーブルのセルに入れる文字列をエスケープする（パイプと改行）
+func escapeTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", "\\|")
+	s = strings.TrimSpace(s)
+	return tableCellLineBreakPattern.ReplaceAllString(s, "<br>")
+}
+
+// issueSprints は課題のSprintフィールドからスプリントの履歴を取得する
+func (mw *MarkdownWriter) issueSprints(issue *cloud.Issue) []SprintInfo {
+	if mw.config == nil || mw.config.Display.SprintFieldId == "" {
+		return nil
+	}
+	value, exists := GetAllCustomFields(issue)[mw.config.Display.SprintFieldId]
+	if !exists {
+		return nil
+	}
+	return ParseSprintField(value)
+}
 
-	// 説明
-	mw.generateDescription(&sb, issue, attachmentMap)
+// sprintNames はスプリント名のリストを返す
+func sprintNames(sprints []SprintInfo) []string {
+	names := make([]string, len(sprints))
+	for i, sprint := range sprints {
+		names[i] = sprint.Name
+	}
+	return names
+}
 
-	// 子作業項目（子課題が存在する場合）
-	mw.generateChildIssues(&sb, childIssues)
+// sprintStateLabel はスプリントの状態を表示用の文字列に変換する
+func (mw *MarkdownWriter) sprintStateLabel(state string) string {
+	switch state {
+	case "future":
+		return mw.msg("sprint_state_future")
+	case "active":
+		return mw.msg("sprint_state_active")
+	case "closed":
+		return mw.msg("sprint_state_closed")
+	default:
+		return state
+	}
+}
 
-	// Confluenceコンテンツ
-	mw.generateConfluenceLinks(&sb, remoteLinks)
+// formatSprintDate はスプリントの日時を日付（YYYY-MM-DD）に変換する
+func formatSprintDate(dateStr string) string {
+	t, err := time.Parse(time.RFC3339, dateStr)
+	if err != nil {
+		return dateStr
+	}
+	return t.Format("2006-01-02")
+}
 
-	// コメント
-	mw.generateComments(&sb, issue, attachmentMap)
+// formatSprintPeriod はスプリントの期間を「開始日 〜 終了日」形式で返す（未設定の場合は空文字列）
+func (mw *MarkdownWriter) formatSprintPeriod(sprint SprintInfo) string {
+	if sprint.StartDate == "" && sprint.EndDate == "" {
+		return ""
+	}
+	return mw.msg("sprint_period", formatSprintDate(sprint.StartDate), formatSprintDate(sprint.EndDate))
+}
 
-	// サブタスク
-	mw.generateSubtasks(&sb, issue)
+// sprintPageName はスプリントページのファイル名（拡張子なし）を返す
+func sprintPageName(sprint SprintInfo) string {
+	return fmt.Sprintf("sprint-%d", sprint.ID)
+}
 
-	// 関連リンク
-	mw.generateIssueLinks(&sb, issue)
+// generateSprints はスプリントセクションの本文を生成する（課題が所属したスプリントの履歴）
+func (mw *MarkdownWriter) generateSprints(sb *strings.Builder, sprints []SprintInfo) {
+	if len(sprints) == 0 {
+		return
+	}
 
-	// 添付ファイル
-	mw.generateAttachments(&sb, attachmentFiles)
+	for _, sprint := range sprints {
+		// スプリントページを生成する設定の場合はリンクにする
+		if mw.config != nil && mw.config.Agile.Enabled && sprint.ID != 0 {
+			sb.WriteString(fmt.Sprintf("- [%s](../sprints/%s/)", sprint.Name, sprintPageName(sprint)))
+		} else {
+			sb.WriteString(fmt.Sprintf("- **%s**", sprint.Name))
+		}
 
-	// 変更履歴
-	mw.generateChangeHistory(&sb, issue)
+		attrs := []string{mw.sprintStateLabel(sprint.State)}
+		if period := mw.formatSprintPeriod(sprint); period != "" {
+			attrs = append(attrs, period)
+		}
+		sb.WriteString(fmt.Sprintf(" (%s)", strings.Join(attrs, ", ")))
 
-	return sb.String()
+		if sprint.Goal != "" {
+			sb.WriteString(fmt.Sprintf(": %s", sprint.Goal))
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// generateChangeHistory は変更履歴セクションの本文を生成する
+func (mw *MarkdownWriter) generateChangeHistory(sb *strings.Builder, issue *cloud.Issue) {
+	if issue.Changelog != nil && len(issue.Changelog.Histories) > 0 {
+		for i, history := range issue.Changelog.Histories {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(fmt.Sprintf("### %s\n\n", mw.msg("change_number", i+1)))
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("changed_by"), mw.getUser(&history.Author)))
+			sb.WriteString(fmt.Sprintf("- **%s**: %s\n", mw.msg("changed_at"), mw.formatTimeString(history.Created)))
+			sb.WriteString("\n")
+
+			for _, item := range history.Items {
+				sb.WriteString(fmt.Sprintf("- **%s**: `%s` → `%s`\n", item.Field, item.FromString, item.ToString))
+			}
+		}
+	}
+}
+
+// generateMarkdown は課題情報からMarkdownコンテンツを生成する
+// ページの構成はテンプレート（output.template、未設定の場合は組み込みのデフォルトテンプレート）で決まる
+func (mw *MarkdownWriter) generateMarkdown(data *IssueData, attachmentFiles []string, fieldNameCache FieldNameCache) (string, error) {
+	var sb strings.Builder
+	if err := mw.issueTemplate().Execute(&sb, mw.newIssueTemplateData(data, attachmentFiles, fieldNameCache)); err != nil {
+		return "", fmt.Errorf("テンプレートの実行に失敗しました: %w", err)
+	}
+	return sb.String(), nil
 }
 
 // getUser はユーザー情報から表示名を取得する
 func (mw *MarkdownWriter) getUser(user *cloud.User) string {
 	if user == nil {
-		return "未設定"
+		return mw.msg("not_set")
 	}
 
 	// accountTypeが"unknown"の場合（削除済みユーザー）、設定からマッピングを検索
@@ -695,11 +1392,11 @@ func (mw *MarkdownWriter) getUser(user *cloud.User) string {
 // getFieldString はフィールド情報から文字列を取得する
 func (mw *MarkdownWriter) getFieldString(field interface{}) string {
 	if field == nil {
-		return "未設定"
+		return mw.msg("not_set")
 	}
 	if priority, ok := field.(*cloud.Priority); ok {
 		if priority == nil {
-			return "未設定"
+			return mw.msg("not_set")
 		}
 		return priority.Name
 	}
@@ -711,11 +1408,6 @@ func (mw *MarkdownWriter) formatTime(jiraTime cloud.Time) string {
 	return time.Time(jiraTime).Format("2006-01-02 15:04:05")
 }
 
-// formatTimeISO8601 は時刻をISO8601形式でフォーマットする（Front Matter用）
-func (mw *MarkdownWriter) formatTimeISO8601(jiraTime cloud.Time) string {
-	return time.Time(jiraTime).Format(time.RFC3339)
-}
-
 // formatTimeSeconds は秒数を小数点形式の時間（h）に変換する
 func (mw *MarkdownWriter) formatTimeSeconds(seconds int) string {
 	if seconds == 0 {
@@ -752,1016 +1444,126 @@ func (mw *MarkdownWriter) buildAttachmentMap(issue *cloud.Issue, attachmentFiles
 	return attachmentMap
 }
 
-// replaceImageReferences はJIRA形式の画像参照 !filename.png! をMarkdown形式に変換する
+// jiraImagePattern はJIRA形式の画像参照パターン: !filename.png! または !filename.png|属性!
+// 例: !screenshot.png!, !image.jpg|width=300!
+var jiraImagePattern = regexp.MustCompile(`!([^!|]+(?:\.[a-zA-Z0-9]+))(?:\|([^!]*))?!`)
+
+// jiraAttachmentLinkPattern はJIRA形式の添付ファイルへのリンクのパターン: [^filename.pdf] または [テキスト|^filename.pdf]
+var jiraAttachmentLinkPattern = regexp.MustCompile(`\[(?:([^|\]\n]*)\|)?\^([^\]\n]+)\]`)
+
+// replaceImageReferences はJIRA形式の画像参照 !filename.png! と添付ファイルへのリンク [^filename.pdf] をMarkdown形式に変換する
 func (mw *MarkdownWriter) replaceImageReferences(text string, attachmentMap map[string]string) string {
-	// JIRA形式の画像参照パターン: !filename.png! または !filename.png|属性!
-	// 例: !screenshot.png!, !image.jpg|width=300!
-	pattern := regexp.MustCompile(`!([^!|]+(?:\.[a-zA-Z0-9]+))(?:\|[^!]*)?!`)
-
-	result := pattern.ReplaceAllStringFunc(text, func(match string) string {
-		// マッチからファイル名を抽出
-		submatches := pattern.FindStringSubmatch(match)
-		if len(submatches) < 2 {
+	result := jiraImagePattern.ReplaceAllStringFunc(text, func(match string) string {
+		// マッチからファイル名と属性を抽出
+		submatches := jiraImagePattern.FindStringSubmatch(match)
+		if len(submatches) < 3 {
 			return match
 		}
 		originalFilename := submatches[1]
 
-		// 添付ファイルマップから保存されたファイル名を取得
-		savedFilename, exists := attachmentMap[originalFilename]
-		if !exists {
-			return match // 見つからない場合は元のまま
-		}
-
-		// ファイル名をURLエンコーディング（スペース→%20）
-		encodedFilename := url.PathEscape(savedFilename)
-		// 画像ファイルの場合は画像形式、それ以外はリンク形式
-		// Hugoで作成するときに、attachmentsディレクトリはプロジェクトディレクトリの直下になる
-		relPath := fmt.Sprintf("/attachments/%s", encodedFilename)
-		if IsImageFile(originalFilename) {
-			return fmt.Sprintf("![%s](%s)", originalFilename, relPath)
-		}
-		return fmt.Sprintf("[%s](%s)", originalFilename, relPath)
-	})
-
-	return result
-}
-
-// extractJIRATables はJIRAテーブルを抽出してプレースホルダーに置き換える
-// セル内改行を保持したままテーブル全体を抽出する
-func (mw *MarkdownWriter) extractJIRATables(text string) (string, []string) {
-	lines := strings.Split(text, "\n")
-	tables := []string{}
-	result := []string{}
-
-	i := 0
-	for i < len(lines) {
-		line := lines[i]
-
-		// ヘッダー行を検出
-		if strings.HasPrefix(line, "||") && strings.HasSuffix(line, "||") {
-			tableLines := []string{line}
-			i++
-
-			// データ行を収集
-			for i < len(lines) {
-				dataLine := lines[i]
-
-				// 次のテーブルヘッダーをチェック
-				if strings.HasPrefix(dataLine, "||") && strings.HasSuffix(dataLine, "||") {
-					// 次のテーブル開始 → 現在のテーブル終了
-					break
-				} else if strings.HasPrefix(dataLine, "|") && !strings.HasPrefix(dataLine, "||") {
-					// データ行の開始
-					completeLine := dataLine
-					i++
-
-					// |で終わるまで次の行と結合（セル内改行対応）
-					for !strings.HasSuffix(completeLine, "|") && i < len(lines) {
-						nextLine := lines[i]
-						// 次のテーブルヘッダーが来たら結合を中止
-						if strings.HasPrefix(nextLine, "||") && strings.HasSuffix(nextLine, "||") {
-							break
-						}
-						completeLine += "\n" + nextLine
-						i++
-					}
-
-					if strings.HasSuffix(completeLine, "|") {
-						tableLines = append(tableLines, completeLine)
-					}
-				} else if dataLine == "" {
-					// 空行 → テーブル終了
-					break
-				} else {
-					// テーブル外の行（|で始まらない） → テーブル終了
-					break
+		// サイズ等の属性付きの画像はimgタグにする
+		if IsImageFile(originalFilename) && submatches[2] != "" {
+			if relPath, exists := attachmentPath(originalFilename, attachmentMap); exists {
+				if tag, ok := imageTag(originalFilename, relPath, parseImageAttributes(submatches[2])); ok {
+					return tag
 				}
 			}
-
-			// テーブルをプレースホルダー化
-			tables = append(tables, strings.Join(tableLines, "\n"))
-			result = append(result, fmt.Sprintf("__TABLE_%d__", len(tables)-1))
-		} else if strings.HasPrefix(line, "|") && !strings.HasPrefix(line, "||") {
-			// ヘッダー無しテーブルを検出
-			tableLines := []string{}
-
-			// データ行を収集（セル内改行対応）
-			for i < len(lines) {
-				dataLine := lines[i]
-
-				if strings.HasPrefix(dataLine, "|") && !strings.HasPrefix(dataLine, "||") {
-					// データ行の開始
-					completeLine := dataLine
-					i++
-
-					// |で終わるまで次の行と結合（セル内改行対応）
-					for !strings.HasSuffix(completeLine, "|") && i < len(lines) {
-						nextLine := lines[i]
-						// 次のテーブルヘッダーが来たら結合を中止
-						if strings.HasPrefix(nextLine, "||") && strings.HasSuffix(nextLine, "||") {
-							break
-						}
-						// 次のデータ行が来たら結合を中止
-						if strings.HasPrefix(nextLine, "|") {
-							break
-						}
-						// 空行が来たら結合を中止
-						if nextLine == "" {
-							break
-						}
-						completeLine += "\n" + nextLine
-						i++
-					}
-
-					if strings.HasSuffix(completeLine, "|") {
-						tableLines = append(tableLines, completeLine)
-					}
-				} else if dataLine == "" {
-					// 空行 → テーブル終了
-					break
-				} else {
-					// テーブル外の行 → テーブル終了
-					break
-				}
-			}
-
-			// テーブルをプレースホルダー化
-			if len(tableLines) > 0 {
-				tables = append(tables, strings.Join(tableLines, "\n"))
-				result = append(result, fmt.Sprintf("__TABLE_%d__", len(tables)-1))
-			}
-		} else {
-			result = append(result, line)
-			i++
-		}
-	}
-
-	return strings.Join(result, "\n"), tables
-}
-
-// convertJIRATableToMarkdown 1つのJIRAテーブルをMarkdownテーブルに変換する
-func (mw *MarkdownWriter) convertJIRATableToMarkdown(table string) string {
-	lines := strings.Split(table, "\n")
-	var result []string
-
-	// ヘッダーの有無を判定
-	hasHeader := false
-	if len(lines) > 0 {
-		firstLine := lines[0]
-		hasHeader = strings.HasPrefix(firstLine, "||") && strings.HasSuffix(firstLine, "||")
-	}
-
-	// ヘッダー無しの場合、最初のデータ行からセル数を取得して空ヘッダーを生成
-	if !hasHeader && len(lines) > 0 {
-		// 最初のデータ行を取得（セル内改行対応）
-		firstLine := lines[0]
-		if strings.HasPrefix(firstLine, "|") && !strings.HasPrefix(firstLine, "||") {
-			// セル内改行を考慮して完全な行を取得
-			completeLine := firstLine
-			j := 1
-			for !strings.HasSuffix(completeLine, "|") && j < len(lines) {
-				nextLine := lines[j]
-				completeLine += "\n" + nextLine
-				j++
-			}
-
-			if strings.HasSuffix(completeLine, "|") {
-				content := strings.Trim(completeLine, "|")
-				cells := strings.Split(content, "|")
-				cellCount := len(cells)
-
-				// 空ヘッダー行を生成
-				emptyHeaders := make([]string, cellCount)
-				for k := range emptyHeaders {
-					emptyHeaders[k] = " "
-				}
-				header := "| " + strings.Join(emptyHeaders, " | ") + " |"
-				result = append(result, header)
-
-				// セパレーター行を生成
-				separators := make([]string, cellCount)
-				for k := range separators {
-					separators[k] = "------"
-				}
-				separator := "| " + strings.Join(separators, " | ") + " |"
-				result = append(result, separator)
-			}
 		}
-	}
-
-	i := 0
-	for i < len(lines) {
-		line := lines[i]
 
-		// ヘッダー行を変換（セル内改行対応）
-		if strings.HasPrefix(line, "||") {
-			completeLine := line
-			i++
-
-			// ||で終わるまで次の行と結合（セル内改行対応）
-			for !strings.HasSuffix(completeLine, "||") && i < len(lines) {
-				nextLine := lines[i]
-				completeLine += "\n" + nextLine
-				i++
-			}
-
-			if strings.HasSuffix(completeLine, "||") {
-				content := strings.Trim(completeLine, "|")
-				cells := strings.Split(content, "||")
-				// セル内改行を<br>に変換
-				for j, cell := range cells {
-					cells[j] = strings.ReplaceAll(cell, "\n", "<br>")
-				}
-				// Markdownテーブルヘッダー
-				header := "| " + strings.Join(cells, " | ") + " |"
-				result = append(result, header)
-				// セパレーター行
-				separators := make([]string, len(cells))
-				for j := range separators {
-					separators[j] = "------"
-				}
-				separator := "| " + strings.Join(separators, " | ") + " |"
-				result = append(result, separator)
-			}
-		} else if strings.HasPrefix(line, "|") && !strings.HasPrefix(line, "||") {
-			// データ行を変換（セル内改行対応）
-			completeLine := line
-			i++
-
-			// |で終わるまで次の行と結合（セル内改行対応）
-			for !strings.HasSuffix(completeLine, "|") && i < len(lines) {
-				nextLine := lines[i]
-				completeLine += "\n" + nextLine
-				i++
-			}
-
-			if strings.HasSuffix(completeLine, "|") {
-				content := strings.Trim(completeLine, "|")
-				cells := strings.Split(content, "|")
-				// セル内改行を<br>に変換
-				for j, cell := range cells {
-					cells[j] = strings.ReplaceAll(cell, "\n", "<br>")
-				}
-				// Markdownテーブルデータ行
-				row := "| " + strings.Join(cells, " | ") + " |"
-				result = append(result, row)
-			}
-		} else {
-			i++
+		link, exists := attachmentLink(originalFilename, attachmentMap)
+		if !exists {
+			return match // 見つからない場合は元のまま
 		}
-	}
-
-	return strings.Join(result, "\n")
-}
-
-// convertJIRAMarkupToMarkdown はJIRAマークアップをMarkdown形式に変換する
-func (mw *MarkdownWriter) convertJIRAMarkupToMarkdown(text string) string {
-	// プレースホルダーでコードブロックとインラインコードを保護
-	codeBlocks := []string{}
-	placeholderIndex := 0
-	inlineCodes := []string{}
-	inlineCodeIndex := 0
-
-	// 1. コードブロック（言語指定付き）を抽出して保護
-	codeWithLangPattern := regexp.MustCompile(`(?s)\{code:([^}]+)\}(.*?)\{code\}`)
-	text = codeWithLangPattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := codeWithLangPattern.FindStringSubmatch(match)
-		if len(submatches) >= 3 {
-			lang := submatches[1]
-			code := submatches[2]
-			// Markdownのコードブロック形式に変換
-			mdCodeBlock := fmt.Sprintf("```%s\n%s\n```", lang, code)
-			placeholder := fmt.Sprintf("__CODE_BLOCK_%d__", placeholderIndex)
-			codeBlocks = append(codeBlocks, mdCodeBlock)
-			placeholderIndex++
-			return placeholder
-		}
-		return match
-	})
-
-	// 2. コードブロック（言語指定なし）を抽出して保護
-	codePattern := regexp.MustCompile(`(?s)\{code\}(.*?)\{code\}`)
-	text = codePattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := codePattern.FindStringSubmatch(match)
-		if len(submatches) >= 2 {
-			code := submatches[1]
-			mdCodeBlock := fmt.Sprintf("```\n%s\n```", code)
-			placeholder := fmt.Sprintf("__CODE_BLOCK_%d__", placeholderIndex)
-			codeBlocks = append(codeBlocks, mdCodeBlock)
-			placeholderIndex++
-			return placeholder
-		}
-		return match
+		return link
 	})
 
-	// 3. フォーマット済みテキストを抽出して保護
-	noformatPattern := regexp.MustCompile(`(?s)\{noformat\}(.*?)\{noformat\}`)
-	text = noformatPattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := noformatPattern.FindStringSubmatch(match)
-		if len(submatches) >= 2 {
-			content := submatches[1]
-			mdCodeBlock := fmt.Sprintf("```\n%s\n```", content)
-			placeholder := fmt.Sprintf("__CODE_BLOCK_%d__", placeholderIndex)
-			codeBlocks = append(codeBlocks, mdCodeBlock)
-			placeholderIndex++
-			return placeholder
-		}
-		return match
-	})
-
-	// 4. インラインコード: {{text}} → `text`
-	inlineCodePattern := regexp.MustCompile(`\{\{([^}]+)\}\}`)
-	text = inlineCodePattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := inlineCodePattern.FindStringSubmatch(match)
-		if len(submatches) >= 2 {
-			code := submatches[1]
-			inlineCode := fmt.Sprintf("`%s`", code)
-			placeholder := fmt.Sprintf("__INLINE_CODE_%d__", inlineCodeIndex)
-			inlineCodes = append(inlineCodes, inlineCode)
-			inlineCodeIndex++
-			return placeholder
-		}
-		return match
-	})
-
-	// 5. ブレース記法の変換（{quote}, {color}, {status}, {panel}, {note}等）
-	// コードブロック保護後、テーブル変換前に処理する
-	text = mw.convertQuoteMarkup(text)
-	text = mw.convertStatusLabelMarkup(text) // カスタムステータスラベルを先に変換（より具体的なパターン）
-	text = mw.convertColorMarkup(text)
-	text = mw.convertStatusMarkup(text)
-	text = mw.convertPanelMarkup(text)
-	text = mw.convertAdmonitionMarkup(text)
-
-	// 6. テーブルを直接変換（プレースホルダー化せず）
-	text, tables := mw.extractJIRATables(text)
-	for i, table := range tables {
-		placeholder := fmt.Sprintf("__TABLE_%d__", i)
-		markdownTable := mw.convertJIRATableToMarkdown(table)
-		text = strings.ReplaceAll(text, placeholder, markdownTable)
-	}
-
-	// 7. メンション変換: [~accountid:xxx] → <span class="mention">@ユーザー名</span>
-	mentionPattern := regexp.MustCompile(`\[~accountid:([^\]]+)\]`)
-	text = mentionPattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := mentionPattern.FindStringSubmatch(match)
-		if len(submatches) >= 2 {
-			accountID := submatches[1]
-
-			// account IDからユーザー名を取得
-			if userName, exists := mw.userMapping[accountID]; exists && userName != "" {
-				return `<span class="mention">@` + userName + `</span>`
-			}
-
-			// マッピングが見つからない場合はaccount IDを表示
-			return `<span class="mention">@` + accountID + `</span>`
+	// 添付ファイルへのリンクは画像の場合もリンクにする
+	// 見つからない場合はADFの添付ファイルと同様にファイル名を表示する
+	result = jiraAttachmentLinkPattern.ReplaceAllStringFunc(result, func(match string) string {
+		submatches := jiraAttachmentLinkPattern.FindStringSubmatch(match)
+		linkText, originalFilename := submatches[1], strings.TrimSpace(submatches[2])
+		if linkText == "" {
+			linkText = originalFilename
 		}
-		return match
-	})
-
-	// 7. リンク変換: [text|url] → [text](url)
-	linkPattern := regexp.MustCompile(`\[([^\]|]+)\|([^\]]+)\]`)
-	text = linkPattern.ReplaceAllString(text, `[$1]($2)`)
-
-	// 8-1. 見出し変換: h1. - h6. → # - ######（行単位処理）
-	// 見出しをプレースホルダーで保護してからリスト変換を実行
-	headings := []string{}
-	headingIndex := 0
-	headingPattern := regexp.MustCompile(`^h([1-6])\.\s+(.+)$`)
-	lines := strings.Split(text, "\n")
-	var processedLines []string
-	for _, line := range lines {
-		if matches := headingPattern.FindStringSubmatch(line); matches != nil {
-			levelStr := matches[1]
-			title := matches[2]
-			level, _ := strconv.Atoi(levelStr)
-			hashes := strings.Repeat("#", level)
-			heading := hashes + " " + title
-			placeholder := fmt.Sprintf("__HEADING_%d__", headingIndex)
-			headings = append(headings, heading)
-			processedLines = append(processedLines, placeholder)
-			headingIndex++
-		} else {
-			processedLines = append(processedLines, line)
+		relPath, exists := attachmentPath(originalFilename, attachmentMap)
+		if !exists {
+			return "📎 " + linkText
 		}
-	}
-	text = strings.Join(processedLines, "\n")
-
-	// 8-2. リスト変換: * → -、# → 1.（行単位処理）
-	text = mw.convertJIRAListsToMarkdown(text)
-
-	// 8-3. 見出しプレースホルダーを復元
-	for i, heading := range headings {
-		placeholder := fmt.Sprintf("__HEADING_%d__", i)
-		text = strings.ReplaceAll(text, placeholder, heading)
-	}
-
-	// 8-4. リスト行を保護（装飾変換時の衝突回避）
-	text, protectedLists := mw.protectListLines(text)
-
-	// 9. 太字: *text* → **text**（日本語対応版）
-	// Go の regexp は negative lookahead/lookbehind を サポートしないため、簡略版を使用
-	// 単語境界の厳密な要件を緩和し、行頭・行末の * をサポート
-	text = convertBoldMarkup(text)
-
-	// 10. イタリック: _text_ → *text*（日本語対応版）
-	text = convertItalicMarkup(text)
-
-	// 11. 削除線: -text- → ~~text~~（日付・URL対応版）
-	text = convertStrikethroughMarkup(text)
-
-	// 12. 上付き: ^text^ → <sup>text</sup>
-	supPattern := regexp.MustCompile(`\^([^\^]+)\^`)
-	text = supPattern.ReplaceAllString(text, `<sup>$1</sup>`)
-
-	// 13. 下付き: ~text~ → <sub>text</sub>
-	// ~~は取り消し線なので除外する必要がある
-	// ~~で囲まれた部分を一時的に保護する
-	strikeProtectPattern := regexp.MustCompile(`~~[^~]*~~`)
-	strikes := strikeProtectPattern.FindAllString(text, -1)
-	strikeProtectIndex := 0
-	text = strikeProtectPattern.ReplaceAllStringFunc(text, func(match string) string {
-		placeholder := fmt.Sprintf("___STRIKE_PROTECT_%d___", strikeProtectIndex)
-		strikeProtectIndex++
-		return placeholder
+		return fmt.Sprintf("[%s](%s)", linkText, relPath)
 	})
 
-	// 下付き処理
-	subPattern := regexp.MustCompile(`~([^~]+?)~`)
-	text = subPattern.ReplaceAllString(text, `<sub>$1</sub>`)
-
-	// 取り消し線を復元
-	for i, strike := range strikes {
-		placeholder := fmt.Sprintf("___STRIKE_PROTECT_%d___", i)
-		text = strings.Replace(text, placeholder, strike, 1)
-	}
-
-	// 8-5. リスト行を復元
-	text = mw.restoreListLines(text, protectedLists)
-
-	// 14. プレースホルダーを元のコードブロックとインラインコードに戻す
-	for i, codeBlock := range codeBlocks {
-		placeholder := fmt.Sprintf("__CODE_BLOCK_%d__", i)
-		text = strings.ReplaceAll(text, placeholder, codeBlock)
-	}
-	for i, inlineCode := range inlineCodes {
-		placeholder := fmt.Sprintf("__INLINE_CODE_%d__", i)
-		text = strings.ReplaceAll(text, placeholder, inlineCode)
-	}
-
-	// 15. 改行: text\n → text  \n（スペース2個挿入）
-	// 古いチケットと新しいチケットで改行処理が違っていたため、明示的にスペース2個を挿入する方式に統一
-	newlinePattern := regexp.MustCompile(`(.+)\n`)
-	text = newlinePattern.ReplaceAllString(text, "$1  \n")
-
-	return text
-}
-
-// convertJIRAHeadingsToMarkdown は JIRA の見出しマークアップを Markdown に変換する
-// h1. 見出し → # 見出し
-// h2. 見出し → ## 見出し
-func (mw *MarkdownWriter) convertJIRAHeadingsToMarkdown(text string) string {
-	lines := strings.Split(text, "\n")
-	result := make([]string, 0, len(lines))
-
-	headingPattern := regexp.MustCompile(`^h([1-6])\.\s+(.+)$`)
-
-	for _, line := range lines {
-		matches := headingPattern.FindStringSubmatch(line)
-		if len(matches) == 3 {
-			levelStr := matches[1]
-			title := matches[2]
-			level, _ := strconv.Atoi(levelStr)
-			hashes := strings.Repeat("#", level)
-			converted := hashes + " " + title
-			result = append(result, converted)
-		} else {
-			result = append(result, line)
-		}
-	}
-
-	return strings.Join(result, "\n")
-}
-
-// convertJIRAListsToMarkdown は JIRA のリストマークアップを Markdown に変換する
-// * リスト → - リスト
-// ** りすと2 → (4スペース)- りすと2
-// # リスト → 1. リスト
-// ## りすと2 → (4スペース)1. りすと2
-func (mw *MarkdownWriter) convertJIRAListsToMarkdown(text string) string {
-	lines := strings.Split(text, "\n")
-	result := make([]string, 0, len(lines))
-
-	// 古いJIRAでは先頭にスペースが入ることがあるため、^\s* で先頭の空白を許容
-	bulletListPattern := regexp.MustCompile(`^\s*(\*{1,6})\s+(.+)$`)
-	numberedListPattern := regexp.MustCompile(`^\s*(#{1,6})\s+(.+)$`)
-
-	for _, line := range lines {
-		// 番号なしリスト（*）の処理
-		matches := bulletListPattern.FindStringSubmatch(line)
-		if len(matches) == 3 {
-			asterisks := matches[1]
-			content := matches[2]
-			level := len(asterisks) - 1
-			indent := strings.Repeat("    ", level)
-			converted := indent + "- " + content
-			result = append(result, converted)
-		} else {
-			// 番号付きリスト（#）の処理
-			matches := numberedListPattern.FindStringSubmatch(line)
-			if len(matches) == 3 {
-				hashes := matches[1]
-				content := matches[2]
-				level := len(hashes) - 1
-				indent := strings.Repeat("    ", level)
-				converted := indent + "1. " + content
-				result = append(result, converted)
-			} else {
-				result = append(result, line)
-			}
-		}
-	}
-
-	return strings.Join(result, "\n")
-}
-
-// protectListLines はリスト行を一時的にプレースホルダーに置き換えて保護します
-// 装飾記号の変換時にリストマーカー（*）との衝突を防ぐために使用します
-func (mw *MarkdownWriter) protectListLines(text string) (string, []string) {
-	lines := strings.Split(text, "\n")
-	var result []string
-	var protected []string
-
-	// JIRA リストパターン（番号なし * と番号付き #）
-	// 古いJIRAでは先頭にスペースが入ることがあるため、^\s* で先頭の空白を許容
-	bulletListPattern := regexp.MustCompile(`^\s*(\*{1,6})\s+(.+)$`)
-	numberedListPattern := regexp.MustCompile(`^\s*(#{1,6})\s+(.+)$`)
-
-	for i, line := range lines {
-		if bulletListPattern.MatchString(line) || numberedListPattern.MatchString(line) {
-			// リスト行をプレースホルダーに置き換え
-			placeholder := fmt.Sprintf("___LIST_PLACEHOLDER_%d___", i)
-			result = append(result, placeholder)
-			protected = append(protected, line)
-		} else {
-			result = append(result, line)
-		}
-	}
-
-	return strings.Join(result, "\n"), protected
-}
-
-// restoreListLines はプレースホルダーを元のリスト行に戻します
-func (mw *MarkdownWriter) restoreListLines(text string, protected []string) string {
-	result := text
-	for i, line := range protected {
-		placeholder := fmt.Sprintf("___LIST_PLACEHOLDER_%d___", i)
-		result = strings.Replace(result, placeholder, line, 1)
-	}
 	return result
 }
 
-// convertBoldMarkup は*text*を**text**に変換します（日本語対応）
-// 既に**で囲まれている場合は誤変換を避けます
-func convertBoldMarkup(text string) string {
-	lines := strings.Split(text, "\n")
-	var result []string
-
-	for _, line := range lines {
-		// 既に**で囲まれている部分を保護するため、複数回のマッチングを試行
-		// パターン：*text*（**textではない）
-		converted := line
-
-		// 簡単なパターン：*text*の形式（*の間に0個以上の非*文字）
-		pattern := regexp.MustCompile(`\*([^\*\n]+?)\*`)
-
-		for {
-			prev := converted
-			// マッチする部分を検出
-			matches := pattern.FindAllStringSubmatchIndex(converted, -1)
-			if len(matches) == 0 {
-				break
-			}
-
-			// 後ろから処理（インデックスを保つため）
-			for i := len(matches) - 1; i >= 0; i-- {
-				match := matches[i]
-				// マッチ位置から、既に**で囲まれていないかチェック
-				start := match[0]
-				end := match[1]
-
-				// 前後の文字をチェック
-				isBold := false
-				if start > 0 && converted[start-1] == '*' {
-					// 既に**で囲まれている可能性
-					isBold = true
-				}
-				if end < len(converted) && converted[end] == '*' {
-					// 既に**で囲まれている
-					isBold = true
-				}
-
-				if !isBold {
-					// *text* → **text**に変換
-					matchText := converted[match[2]:match[3]]
-					replacement := fmt.Sprintf("**%s**", matchText)
-					converted = converted[:start] + replacement + converted[end:]
-					break
-				}
-			}
-
-			if converted == prev {
-				break // 変更がなければ終了
-			}
-		}
-
-		result = append(result, converted)
+// attachmentLink は添付ファイルへのMarkdownのリンク（画像ファイルの場合は画像）を返す
+// 添付ファイルマップに見つからない場合はfalseを返す
+func attachmentLink(originalFilename string, attachmentMap map[string]string) (string, bool) {
+	relPath, exists := attachmentPath(originalFilename, attachmentMap)
+	if !exists {
+		return "", false
 	}
 
-	return strings.Join(result, "\n")
-}
-
-// convertItalicMarkup は_text_を*text*に変換します（日本語対応）
-func convertItalicMarkup(text string) string {
-	lines := strings.Split(text, "\n")
-	var result []string
-
-	for _, line := range lines {
-		converted := line
-
-		// パターン：_text_の形式（_の間に1個以上の非_文字）
-		pattern := regexp.MustCompile(`_([^_\n]+?)_`)
-
-		for {
-			prev := converted
-			matches := pattern.FindAllStringSubmatchIndex(converted, -1)
-			if len(matches) == 0 {
-				break
-			}
-
-			// 後ろから処理
-			for i := len(matches) - 1; i >= 0; i-- {
-				match := matches[i]
-				start := match[0]
-				end := match[1]
-
-				// 前後の文字をチェック（既に*で囲まれているかチェック）
-				isItalic := false
-				if start > 0 && converted[start-1] == '_' {
-					isItalic = true
-				}
-				if end < len(converted) && converted[end] == '_' {
-					isItalic = true
-				}
-
-				if !isItalic {
-					// _text_ → *text*に変換
-					matchText := converted[match[2]:match[3]]
-					replacement := fmt.Sprintf("*%s*", matchText)
-					converted = converted[:start] + replacement + converted[end:]
-					break
-				}
-			}
-
-			if converted == prev {
-				break
-			}
-		}
-
-		result = append(result, converted)
+	// 画像ファイルの場合は画像形式、それ以外はリンク形式
+	if IsImageFile(originalFilename) {
+		return fmt.Sprintf("![%s](%s)", originalFilename, relPath), true
 	}
-
-	return strings.Join(result, "\n")
+	return fmt.Sprintf("[%s](%s)", originalFilename, relPath), true
 }
 
-// convertStrikethroughMarkup は-text-を~~text~~に変換します（日付・URL・リストアイテム対応）
-func convertStrikethroughMarkup(text string) string {
-	lines := strings.Split(text, "\n")
-	var result []string
-
-	for _, line := range lines {
-		converted := line
-
-		// パターン：-text-の形式（-の間に1個以上の非-文字、空白のみは除外、リスト要素（-空白）も除去）
-		pattern := regexp.MustCompile(`-([^- \n]+?)-`)
-
-		for {
-			prev := converted
-			matches := pattern.FindAllStringSubmatchIndex(converted, -1)
-			if len(matches) == 0 {
-				break
-			}
-
-			// 後ろから処理
-			for i := len(matches) - 1; i >= 0; i-- {
-				match := matches[i]
-				start := match[0]
-				end := match[1]
-
-				// 前後の文字をチェック（マルチバイト文字対応）
-				shouldSkip := false
-
-				// キャプチャグループの内容をチェック（空白のみは変換しない）
-				matchContent := converted[match[2]:match[3]]
-				if strings.TrimSpace(matchContent) == "" {
-					shouldSkip = true
-				}
-
-				// リストアイテムのマーカー（行頭の "- "）は変換しない
-				if start == 0 && len(matchContent) > 0 && matchContent[0] == ' ' {
-					shouldSkip = true
-				}
-
-				// 前の文字をチェック
-				if !shouldSkip && start > 0 {
-					prevRune, _ := utf8.DecodeLastRuneInString(converted[:start])
-					if prevRune != utf8.RuneError {
-						// ASCII英数字または記号(-/:)の場合のみスキップ
-						// 日本語などのマルチバイト文字は変換を許可
-						if (prevRune >= '0' && prevRune <= '9') ||
-							(prevRune >= 'a' && prevRune <= 'z') ||
-							(prevRune >= 'A' && prevRune <= 'Z') ||
-							prevRune == '-' || prevRune == '/' || prevRune == ':' {
-							shouldSkip = true
-						}
-					}
-				}
-
-				// 後の文字をチェック
-				if !shouldSkip && end < len(converted) {
-					nextRune, _ := utf8.DecodeRuneInString(converted[end:])
-					if nextRune != utf8.RuneError {
-						// ASCII英数字または記号(-/:)の場合のみスキップ
-						// 日本語などのマルチバイト文字は変換を許可
-						if (nextRune >= '0' && nextRune <= '9') ||
-							(nextRune >= 'a' && nextRune <= 'z') ||
-							(nextRune >= 'A' && nextRune <= 'Z') ||
-							nextRune == '-' || nextRune == '/' || nextRune == ':' {
-							shouldSkip = true
-						}
-					}
-				}
-
-				// 既に~~で囲まれているかチェック
-				if !shouldSkip && start > 1 && converted[start-1:start] == "~" && converted[start-2:start-1] == "~" {
-					shouldSkip = true
-				}
-				if !shouldSkip && end+1 < len(converted) && converted[end:end+1] == "~" && end+2 < len(converted) && converted[end+1:end+2] == "~" {
-					shouldSkip = true
-				}
-
-				if !shouldSkip {
-					// -text- → ~~text~~に変換
-					replacement := fmt.Sprintf("~~%s~~", matchContent)
-					converted = converted[:start] + replacement + converted[end:]
-					break
-				}
-			}
-
-			if converted == prev {
-				break
-			}
-		}
-
-		result = append(result, converted)
+// attachmentPath は保存された添付ファイルのパスを返す
+// 添付ファイルマップに見つからない場合はfalseを返す
+func attachmentPath(originalFilename string, attachmentMap map[string]string) (string, bool) {
+	// 添付ファイルマップから保存されたファイル名を取得
+	savedFilename, exists := attachmentMap[originalFilename]
+	if !exists {
+		return "", false
 	}
 
-	return strings.Join(result, "\n")
-}
-
-// mapStatusColor はJIRAの色名をCSSクラス名にマッピング
-func mapStatusColor(color string) string {
-	colorMap := map[string]string{
-		"green":     "status-green",
-		"yellow":    "status-yellow",
-		"red":       "status-red",
-		"blue":      "status-blue",
-		"blue-gray": "status-blue",
-		"grey":      "status-gray",
-		"gray":      "status-gray",
-	}
-	return colorMap[color]
+	// ファイル名をURLエンコーディング（スペース→%20）
+	// Hugoで作成するときに、attachmentsディレクトリはプロジェクトディレクトリの直下になる
+	return fmt.Sprintf("/attachments/%s", url.PathEscape(savedFilename)), true
 }
 
-// statusLabelColorMap はカスタムステータスラベルの16進数カラーコードをCSSクラス名にマッピング
-var statusLabelColorMap = map[string]string{
-	"#ff991f": "status-label-warning", // オレンジ/警告
-	"#00b8d9": "status-label-teal",    // ティール/OK
-	"#36b37e": "status-label-success", // 緑/成功
-	"#ff5630": "status-label-danger",  // 赤/危険
-	"#6554c0": "status-label-purple",  // 紫
-	"#97a0af": "status-label-gray",    // グレー
-}
-
-// convertStatusLabelMarkup はカスタムステータスラベルをHTMLスパンに変換
-// パターン: {color:#XXX}*[ text ]*{color}
-func (mw *MarkdownWriter) convertStatusLabelMarkup(text string) string {
-	// 正規表現: {color:#HEXCODE}*[ text ]*{color}
-	pattern := regexp.MustCompile(`(?i)\{color:(#[0-9a-fA-F]{6})\}\*\[\s*([^\]]+?)\s*\]\*\{color\}`)
-
-	return pattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := pattern.FindStringSubmatch(match)
-		if len(submatches) < 3 {
-			return match
-		}
-
-		colorCode := strings.ToLower(submatches[1])
-		labelText := submatches[2]
-
-		if className, ok := statusLabelColorMap[colorCode]; ok {
-			return fmt.Sprintf(`<span class="status-label %s">%s</span>`, className, labelText)
-		}
-		// 未知の色はデフォルトクラス
-		return fmt.Sprintf(`<span class="status-label">%s</span>`, labelText)
-	})
-}
-
-// convertStatusMarkup は{status}マクロをHTMLスパンに変換
-func (mw *MarkdownWriter) convertStatusMarkup(content string) string {
-	// パターン: {status:colour=Green}text{status} または {status:color=Green}text{status}
-	pattern := regexp.MustCompile(`(?i)\{status(?::colou?r=([^}]+))?\}([^{]*)\{status\}`)
-
-	return pattern.ReplaceAllStringFunc(content, func(match string) string {
-		submatches := pattern.FindStringSubmatch(match)
-		if len(submatches) < 3 {
-			return match
-		}
-
-		color := strings.ToLower(submatches[1])
-		text := submatches[2]
-
-		// 色をCSSクラスにマッピング
-		colorClass := mapStatusColor(color)
-
-		if colorClass != "" {
-			return fmt.Sprintf(`<span class="status %s">%s</span>`, colorClass, text)
-		}
-		return fmt.Sprintf(`<span class="status">%s</span>`, text)
-	})
-}
-
-// convertQuoteMarkup は{quote}...{quote}をMarkdownの引用に変換
-func (mw *MarkdownWriter) convertQuoteMarkup(text string) string {
-	quotePattern := regexp.MustCompile(`(?s)\{quote\}(.*?)\{quote\}`)
-	return quotePattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := quotePattern.FindStringSubmatch(match)
-		if len(submatches) < 2 {
-			return match
-		}
-
-		content := submatches[1]
-		lines := strings.Split(content, "\n")
-		var result []string
-
-		for _, line := range lines {
-			// 各行を> で始める
-			if strings.TrimSpace(line) != "" {
-				result = append(result, "> "+line)
-			} else {
-				result = append(result, ">")
-			}
-		}
-
-		return strings.Join(result, "\n")
-	})
-}
-
-// convertColorMarkup は{color:...}...{color}をHTMLのspanタグに変換
-// JIRAの色指定をそのままインラインスタイルとして出力
-func (mw *MarkdownWriter) convertColorMarkup(text string) string {
-	colorPattern := regexp.MustCompile(`(?s)\{color:([^}]+)\}(.*?)\{color\}`)
-	return colorPattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := colorPattern.FindStringSubmatch(match)
-		if len(submatches) < 3 {
-			return match
-		}
-
-		colorValue := submatches[1] // 元のカラーコードをそのまま使用
-		content := submatches[2]
-
-		return fmt.Sprintf(`<span style="color:%s">%s</span>`, colorValue, content)
-	})
-}
-
-// getPanelClass はbgColorからCSSクラスを判別
-func getPanelClass(bgColor string) string {
-	bgColor = strings.ToLower(strings.TrimSpace(bgColor))
-	if !strings.HasPrefix(bgColor, "#") {
-		bgColor = "#" + bgColor
-	}
-
-	switch bgColor {
-	case "#ffebe6":
-		return "panel-error"
-	case "#e3fcef":
-		return "panel-success"
-	case "#fffae6":
-		return "panel-warning"
-	case "#deebff":
-		return "panel-info"
-	default:
-		return "panel-info"
-	}
-}
-
-// parsePanelParams はpanelのパラメータ文字列を解析
-func parsePanelParams(paramStr string) map[string]string {
-	params := make(map[string]string)
-	paramPattern := regexp.MustCompile(`(\w+)=([^|]+)`)
-	matches := paramPattern.FindAllStringSubmatch(paramStr, -1)
-
-	for _, match := range matches {
-		if len(match) >= 3 {
-			key := strings.TrimSpace(match[1])
-			value := strings.TrimSpace(match[2])
-			params[key] = value
+// parseImageAttributes は画像の属性（thumbnail、width=300,height=200 等のカンマ区切り）を解析する
+// 値のない属性（thumbnail）は空文字列を値とする
+func parseImageAttributes(attrs string) map[string]string {
+	result := make(map[string]string)
+	for _, attr := range strings.Split(attrs, ",") {
+		key, value, _ := strings.Cut(attr, "=")
+		if key = strings.ToLower(strings.TrimSpace(key)); key != "" {
+			result[key] = strings.TrimSpace(value)
 		}
 	}
-
-	return params
+	return result
 }
 
-// convertPanelMarkup は{panel:...}...{panel}をHTMLのdivタグに変換
-func (mw *MarkdownWriter) convertPanelMarkup(text string) string {
-	// パラメータ付きpanel
-	panelWithParamsPattern := regexp.MustCompile(`(?s)\{panel:([^}]+)\}(.*?)\{panel\}`)
-	text = panelWithParamsPattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := panelWithParamsPattern.FindStringSubmatch(match)
-		if len(submatches) < 3 {
-			return match
-		}
-
-		paramStr := submatches[1]
-		content := submatches[2]
-		params := parsePanelParams(paramStr)
-
-		bgColor := params["bgColor"]
-		title := params["title"]
-		panelClass := getPanelClass(bgColor)
-
-		var result string
-		if title != "" {
-			result = fmt.Sprintf(`<div class="panel %s"><div class="panel-title">%s</div><div class="panel-body">%s</div></div>`,
-				panelClass, title, content)
-		} else {
-			result = fmt.Sprintf(`<div class="panel %s"><div class="panel-body">%s</div></div>`,
-				panelClass, content)
-		}
-
-		return result
-	})
-
-	// パラメータなしpanel
-	panelSimplePattern := regexp.MustCompile(`(?s)\{panel\}(.*?)\{panel\}`)
-	text = panelSimplePattern.ReplaceAllStringFunc(text, func(match string) string {
-		submatches := panelSimplePattern.FindStringSubmatch(match)
-		if len(submatches) < 2 {
-			return match
+// imageTag はサイズ等の属性付きの画像をimgタグに変換する
+// サムネイルはthumbnailクラス、幅・高さ・代替テキスト・タイトルは属性にする
+// 対応する属性がない場合はfalseを返す（Markdownの画像にする）
+func imageTag(originalFilename, relPath string, attrs map[string]string) (string, bool) {
+	var sb strings.Builder
+	alt := originalFilename
+	if value := attrs["alt"]; value != "" {
+		alt = value
+	}
+	sb.WriteString(fmt.Sprintf(`<img src="%s" alt="%s"`, relPath, html.EscapeString(alt)))
+	found := false
+	if _, ok := attrs["thumbnail"]; ok {
+		sb.WriteString(` class="thumbnail"`)
+		found = true
+	}
+	for _, key := range []string{"width", "height", "title"} {
+		if value := attrs[key]; value != "" {
+			sb.WriteString(fmt.Sprintf(` %s="%s"`, key, html.EscapeString(value)))
+			found = true
 		}
-
-		content := submatches[1]
-		return fmt.Sprintf(`<div class="panel panel-info"><div class="panel-body">%s</div></div>`, content)
-	})
-
-	return text
-}
-
-// getAdmonitionClass はadmonitionタイプからCSSクラスを取得
-func getAdmonitionClass(admonitionType string) string {
-	switch strings.ToLower(admonitionType) {
-	case "note":
-		return "panel-note"
-	case "info":
-		return "panel-info"
-	case "warning":
-		return "panel-warning"
-	case "tip":
-		return "panel-success"
-	default:
-		return "panel-info"
 	}
+	sb.WriteString(">")
+	return sb.String(), found
 }
 
-// convertAdmonitionMarkup は{note}等のadmonitionをpanelに変換
-func (mw *MarkdownWriter) convertAdmonitionMarkup(text string) string {
-	// Goのregexpはバックリファレンスをサポートしないため、各タイプ別に処理
-	admonitionTypes := []string{"note", "info", "warning", "tip"}
-
-	for _, adType := range admonitionTypes {
-		pattern := regexp.MustCompile(`(?s)\{` + adType + `\}(.*?)\{` + adType + `\}`)
-		text = pattern.ReplaceAllStringFunc(text, func(match string) string {
-			submatches := pattern.FindStringSubmatch(match)
-			if len(submatches) < 2 {
-				return match
-			}
-
-			content := submatches[1]
-			panelClass := getAdmonitionClass(adType)
-
-			return fmt.Sprintf(`<div class="panel %s"><div class="panel-body">%s</div></div>`,
-				panelClass, content)
-		})
-	}
-
-	return text
+// convertJIRAMarkupToMarkdown はJIRAマークアップをMarkdown形式に変換する
+// Wiki記法を構文木に解析して変換し（wiki.go）、空行以外の行末に改行を保持するスペースを付ける
+func (mw *MarkdownWriter) convertJIRAMarkupToMarkdown(text string) string {
+	return addHardLineBreaks(mw.renderWikiMarkup(text))
 }
@@ -1,8 +1,8 @@
 package main
 
 import (
+	"context"
 	"os"
-	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -23,121 +23,95 @@ func createTestConfig() *Config {
 	}
 }
 
-func TestExtractJIRATables(t *testing.T) {
-	mw := NewMarkdownWriter("", "", nil, createTestConfig())
+// TestRenderWikiMarkup_TableBoundaries はテーブルの範囲（セル内改行・空行・前後のテキスト）の変換をテスト
+func TestRenderWikiMarkup_TableBoundaries(t *testing.T) {
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
 
 	tests := []struct {
-		name           string
-		input          string
-		expectedText   string
-		expectedTables []string
+		name     string
+		input    string
+		expected string
 	}{
 		{
-			name:           "ヘッダー付きテーブル（基本）",
-			input:          "||Header 1||Header 2||\n|Data 1|Data 2|",
-			expectedText:   "__TABLE_0__",
-			expectedTables: []string{"||Header 1||Header 2||\n|Data 1|Data 2|"},
+			name:     "ヘッダー付きテーブル（基本）",
+			input:    "||Header 1||Header 2||\n|Data 1|Data 2|",
+			expected: "| Header 1 | Header 2 |\n| ------ | ------ |\n| Data 1 | Data 2 |",
 		},
 		{
-			name:           "ヘッダー無しテーブル（1行）",
-			input:          "|Data 1|Data 2|",
-			expectedText:   "__TABLE_0__",
-			expectedTables: []string{"|Data 1|Data 2|"},
+			name:     "ヘッダー無しテーブル（1行）",
+			input:    "|Data 1|Data 2|",
+			expected: "|   |   |\n| ------ | ------ |\n| Data 1 | Data 2 |",
 		},
 		{
-			name:           "ヘッダー無しテーブル（複数行）",
-			input:          "|Data 1|Data 2|\n|Data 3|Data 4|",
-			expectedText:   "__TABLE_0__",
-			expectedTables: []string{"|Data 1|Data 2|\n|Data 3|Data 4|"},
+			name:     "ヘッダー無しテーブル（複数行）",
+			input:    "|Data 1|Data 2|\n|Data 3|Data 4|",
+			expected: "|   |   |\n| ------ | ------ |\n| Data 1 | Data 2 |\n| Data 3 | Data 4 |",
 		},
 		{
-			name:           "セル内改行を含むヘッダー付きテーブル",
-			input:          "||Header||\n|Line1\nLine2|",
-			expectedText:   "__TABLE_0__",
-			expectedTables: []string{"||Header||\n|Line1\nLine2|"},
+			name:     "セル内改行を含むヘッダー付きテーブル",
+			input:    "||Header||\n|Line1\nLine2|",
+			expected: "| Header |\n| ------ |\n| Line1<br>Line2 |",
 		},
 		{
-			name:           "セル内改行を含むヘッダー無しテーブル",
-			input:          "|Line1\nLine2|",
-			expectedText:   "__TABLE_0__",
-			expectedTables: []string{"|Line1\nLine2|"},
+			name:     "セル内改行を含むヘッダー無しテーブル",
+			input:    "|Line1\nLine2|",
+			expected: "|   |\n| ------ |\n| Line1<br>Line2 |",
 		},
 		{
-			name:           "ヘッダー付きとヘッダー無しが混在",
-			input:          "||Header||\n|Data 1|\n\n|Data 2|\n|Data 3|",
-			expectedText:   "__TABLE_0__\n\n__TABLE_1__",
-			expectedTables: []string{"||Header||\n|Data 1|", "|Data 2|\n|Data 3|"},
+			name:     "ヘッダー付きとヘッダー無しが混在",
+			input:    "||Header||\n|Data 1|\n\n|Data 2|\n|Data 3|",
+			expected: "| Header |\n| ------ |\n| Data 1 |\n\n|   |\n| ------ |\n| Data 2 |\n| Data 3 |",
 		},
 		{
-			name:           "テーブルが無い場合",
-			input:          "This is normal text",
-			expectedText:   "This is normal text",
-			expectedTables: []string{},
+			name:     "テーブルが無い場合",
+			input:    "This is normal text",
+			expected: "This is normal text",
 		},
 		{
-			name:           "空の入力",
-			input:          "",
-			expectedText:   "",
-			expectedTables: []string{},
+			name:     "空の入力",
+			input:    "",
+			expected: "",
 		},
 		{
-			name:           "テーブルの前後にテキストがある場合",
-			input:          "Text before\n|Data|\nText after",
-			expectedText:   "Text before\n__TABLE_0__\nText after",
-			expectedTables: []string{"|Data|"},
+			name:     "テーブルの前後にテキストがある場合",
+			input:    "Text before\n|Data|\nText after",
+			expected: "Text before\n|   |\n| ------ |\n| Data |\nText after",
 		},
 		{
-			name:           "空行で区切られた複数のテーブル",
-			input:          "|Table 1|\n\n|Table 2|",
-			expectedText:   "__TABLE_0__\n\n__TABLE_1__",
-			expectedTables: []string{"|Table 1|", "|Table 2|"},
+			name:     "空行で区切られた複数のテーブル",
+			input:    "|Table 1|\n\n|Table 2|",
+			expected: "|   |\n| ------ |\n| Table 1 |\n\n|   |\n| ------ |\n| Table 2 |",
 		},
 		{
-			name:           "ヘッダー付きテーブル（複数行）",
-			input:          "||H1||H2||\n|A1|A2|\n|B1|B2|",
-			expectedText:   "__TABLE_0__",
-			expectedTables: []string{"||H1||H2||\n|A1|A2|\n|B1|B2|"},
+			name:     "ヘッダー付きテーブル（複数行）",
+			input:    "||H1||H2||\n|A1|A2|\n|B1|B2|",
+			expected: "| H1 | H2 |\n| ------ | ------ |\n| A1 | A2 |\n| B1 | B2 |",
 		},
 		{
-			name:           "複数のヘッダー無しテーブル",
-			input:          "|T1 R1|\n|T1 R2|\n\n|T2 R1|\n|T2 R2|",
-			expectedText:   "__TABLE_0__\n\n__TABLE_1__",
-			expectedTables: []string{"|T1 R1|\n|T1 R2|", "|T2 R1|\n|T2 R2|"},
+			name:     "複数のヘッダー無しテーブル",
+			input:    "|T1 R1|\n|T1 R2|\n\n|T2 R1|\n|T2 R2|",
+			expected: "|   |\n| ------ |\n| T1 R1 |\n| T1 R2 |\n\n|   |\n| ------ |\n| T2 R1 |\n| T2 R2 |",
 		},
 		{
-			name:           "テーブルとテキストが混在",
-			input:          "Start\n||Header||\n|Data|\nMiddle\n|Row1|\n|Row2|\nEnd",
-			expectedText:   "Start\n__TABLE_0__\nMiddle\n__TABLE_1__\nEnd",
-			expectedTables: []string{"||Header||\n|Data|", "|Row1|\n|Row2|"},
+			name:     "テーブルとテキストが混在",
+			input:    "Start\n||Header||\n|Data|\nMiddle\n|Row1|\n|Row2|\nEnd",
+			expected: "Start\n| Header |\n| ------ |\n| Data |\nMiddle\n|   |\n| ------ |\n| Row1 |\n| Row2 |\nEnd",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			text, tables := mw.extractJIRATables(tt.input)
-
-			if text != tt.expectedText {
-				t.Errorf("expected text:\n%q\ngot:\n%q", tt.expectedText, text)
-			}
+			result := mw.renderWikiMarkup(tt.input)
 
-			if len(tables) != len(tt.expectedTables) {
-				t.Errorf("expected %d tables, got %d", len(tt.expectedTables), len(tables))
-				t.Errorf("expected tables: %v", tt.expectedTables)
-				t.Errorf("got tables: %v", tables)
-				return
-			}
-
-			for i, expectedTable := range tt.expectedTables {
-				if tables[i] != expectedTable {
-					t.Errorf("table[%d]:\nexpected:\n%q\ngot:\n%q", i, expectedTable, tables[i])
-				}
+			if result != tt.expected {
+				t.Errorf("expected:\n%q\ngot:\n%q", tt.expected, result)
 			}
 		})
 	}
 }
 
 func TestConvertJIRATableToMarkdown(t *testing.T) {
-	mw := NewMarkdownWriter("", "", nil, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
 
 	tests := []struct {
 		name     string
@@ -221,7 +195,7 @@ func TestConvertJIRATableToMarkdown(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := mw.convertJIRATableToMarkdown(tt.input)
+			result := mw.renderWikiMarkup(tt.input)
 
 			if result != tt.expected {
 				t.Errorf("expected:\n%s\n\ngot:\n%s", tt.expected, result)
@@ -285,7 +259,7 @@ func TestConvertJIRAMention(t *testing.T) {
 }
 
 func TestDuedateField(t *testing.T) {
-	mw := NewMarkdownWriter("", "", nil, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
 
 	tests := []struct {
 		name           string
@@ -341,7 +315,10 @@ func TestDuedateField(t *testing.T) {
 			}
 
 			// generateMarkdownを呼び出し
-			result := mw.generateMarkdown(issue, []string{}, make(FieldNameCache), nil, nil, []ChildIssueInfo{}, []cloud.RemoteLink{})
+			result, err := mw.generateMarkdown(&IssueData{Issue: issue}, []string{}, make(FieldNameCache))
+			if err != nil {
+				t.Fatalf("generateMarkdown() error = %v", err)
+			}
 
 			// 期限フィールドの有無を確認
 			if tt.expectDuedate {
@@ -358,7 +335,7 @@ func TestDuedateField(t *testing.T) {
 }
 
 func TestTimeTrackingFields(t *testing.T) {
-	mw := NewMarkdownWriter("", "", nil, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
 
 	tests := []struct {
 		name          string
@@ -454,7 +431,10 @@ func TestTimeTrackingFields(t *testing.T) {
 			}
 
 			// generateMarkdownを呼び出し
-			result := mw.generateMarkdown(issue, []string{}, make(FieldNameCache), nil, nil, []ChildIssueInfo{}, []cloud.RemoteLink{})
+			result, err := mw.generateMarkdown(&IssueData{Issue: issue}, []string{}, make(FieldNameCache))
+			if err != nil {
+				t.Fatalf("generateMarkdown() error = %v", err)
+			}
 
 			// 期待される文字列が含まれているか確認
 			for _, expected := range tt.expectStrings {
@@ -533,7 +513,7 @@ func TestFormatTimeSeconds(t *testing.T) {
 	}
 
 	// MarkdownWriterのインスタンスを作成
-	mw := NewMarkdownWriter("test_output", "test_attachments", nil, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "test_output", "test_attachments", nil, createTestConfig())
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -624,7 +604,7 @@ func TestLabelsAndParentFields(t *testing.T) {
 	}
 
 	// MarkdownWriterのインスタンスを作成
-	mw := NewMarkdownWriter("test_output", "test_attachments", nil, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "test_output", "test_attachments", nil, createTestConfig())
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -661,7 +641,10 @@ func TestLabelsAndParentFields(t *testing.T) {
 			}
 
 			// generateMarkdownを呼び出し
-			result := mw.generateMarkdown(issue, []string{}, make(FieldNameCache), nil, nil, []ChildIssueInfo{}, []cloud.RemoteLink{})
+			result, err := mw.generateMarkdown(&IssueData{Issue: issue}, []string{}, make(FieldNameCache))
+			if err != nil {
+				t.Fatalf("generateMarkdown() error = %v", err)
+			}
 
 			// 期待される文字列が含まれているか確認
 			for _, expected := range tt.expectStrings {
@@ -739,7 +722,7 @@ func TestSubtasksField(t *testing.T) {
 	}
 
 	// MarkdownWriterのインスタンスを作成
-	mw := NewMarkdownWriter("test_output", "test_attachments", nil, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "test_output", "test_attachments", nil, createTestConfig())
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -775,7 +758,10 @@ func TestSubtasksField(t *testing.T) {
 			}
 
 			// generateMarkdownを呼び出し
-			result := mw.generateMarkdown(issue, []string{}, make(FieldNameCache), nil, nil, []ChildIssueInfo{}, []cloud.RemoteLink{})
+			result, err := mw.generateMarkdown(&IssueData{Issue: issue}, []string{}, make(FieldNameCache))
+			if err != nil {
+				t.Fatalf("generateMarkdown() error = %v", err)
+			}
 
 			// 期待される文字列が含まれているか確認
 			for _, expected := range tt.expectStrings {
@@ -909,7 +895,7 @@ func TestIssueLinksField(t *testing.T) {
 	}
 
 	// MarkdownWriterのインスタンスを作成
-	mw := NewMarkdownWriter("test_output", "test_attachments", nil, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "test_output", "test_attachments", nil, createTestConfig())
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -945,7 +931,10 @@ func TestIssueLinksField(t *testing.T) {
 			}
 
 			// generateMarkdownを呼び出し
-			result := mw.generateMarkdown(issue, []string{}, make(FieldNameCache), nil, nil, []ChildIssueInfo{}, []cloud.RemoteLink{})
+			result, err := mw.generateMarkdown(&IssueData{Issue: issue}, []string{}, make(FieldNameCache))
+			if err != nil {
+				t.Fatalf("generateMarkdown() error = %v", err)
+			}
 
 			// 期待される文字列が含まれているか確認
 			for _, expected := range tt.expectStrings {
@@ -968,7 +957,7 @@ func TestIssueLinksField(t *testing.T) {
 // このテストは、リファクタリング後も同じ出力が生成されることを保証する
 func TestGenerateMarkdown_Golden(t *testing.T) {
 	// テスト用のMarkdownWriterを作成
-	mw := NewMarkdownWriter("", "", nil, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
 
 	// 完全な課題データを作成（すべてのフィールドを含む）
 	issue := &cloud.Issue{
@@ -1172,7 +1161,10 @@ func TestGenerateMarkdown_Golden(t *testing.T) {
 	}
 
 	// generateMarkdownを実行
-	got := mw.generateMarkdown(issue, attachmentFiles, fieldNameCache, devStatus, nil, []ChildIssueInfo{}, []cloud.RemoteLink{})
+	got, err := mw.generateMarkdown(&IssueData{Issue: issue, DevStatus: devStatus}, attachmentFiles, fieldNameCache)
+	if err != nil {
+		t.Fatalf("generateMarkdown() error = %v", err)
+	}
 
 	// ゴールデンファイルのパス
 	goldenFile := "testdata/generate-markdown.golden"
@@ -1230,9 +1222,9 @@ func TestGenerateBasicInfo_StartDatePosition(t *testing.T) {
 	cache["customfield_10015"] = "Start date"
 
 	userMapping := make(UserMapping)
-	mw := NewMarkdownWriter("", "", userMapping, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "", "", userMapping, createTestConfig())
 	var sb strings.Builder
-	mw.generateBasicInfo(&sb, issue, cache, nil)
+	mw.generateBasicInfo(&sb, issue, cache, nil, nil)
 
 	result := sb.String()
 
@@ -1270,9 +1262,9 @@ func TestGenerateBasicInfo_RankHidden(t *testing.T) {
 	cache["customfield_10019"] = "Rank"
 
 	userMapping := make(UserMapping)
-	mw := NewMarkdownWriter("", "", userMapping, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "", "", userMapping, createTestConfig())
 	var sb strings.Builder
-	mw.generateBasicInfo(&sb, issue, cache, nil)
+	mw.generateBasicInfo(&sb, issue, cache, nil, nil)
 
 	result := sb.String()
 
@@ -1284,7 +1276,7 @@ func TestGenerateBasicInfo_RankHidden(t *testing.T) {
 
 func TestConvertJIRAListsToMarkdown(t *testing.T) {
 	userMapping := make(UserMapping)
-	mw := NewMarkdownWriter("", "", userMapping, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "", "", userMapping, createTestConfig())
 
 	tests := []struct {
 		name     string
@@ -1320,7 +1312,7 @@ func TestConvertJIRAListsToMarkdown(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := mw.convertJIRAListsToMarkdown(tt.input)
+			result := mw.renderWikiMarkup(tt.input)
 			if result != tt.expected {
 				t.Errorf("期待値と異なります\n期待: %q\n結果: %q", tt.expected, result)
 			}
@@ -1330,7 +1322,7 @@ func TestConvertJIRAListsToMarkdown(t *testing.T) {
 
 func TestConvertJIRAMarkupToMarkdown_Headings(t *testing.T) {
 	userMapping := make(UserMapping)
-	mw := NewMarkdownWriter("", "", userMapping, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "", "", userMapping, createTestConfig())
 
 	tests := []struct {
 		name     string
@@ -1371,7 +1363,7 @@ func TestConvertJIRAMarkupToMarkdown_Headings(t *testing.T) {
 
 func TestConvertJIRAMarkupToMarkdown_ListAndHeadingIntegration(t *testing.T) {
 	userMapping := make(UserMapping)
-	mw := NewMarkdownWriter("", "", userMapping, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "", "", userMapping, createTestConfig())
 
 	// リストと見出しが正しく変換されることを確認
 	input := "h2. リストの例\n* リスト1\n** サブリスト1\n* リスト2"
@@ -1394,7 +1386,7 @@ func TestConvertJIRAMarkupToMarkdown_ListAndHeadingIntegration(t *testing.T) {
 
 func TestConvertJIRAListsToMarkdown_NumberedLists(t *testing.T) {
 	userMapping := make(UserMapping)
-	mw := NewMarkdownWriter("", "", userMapping, createTestConfig())
+	mw := NewMarkdownWriter(context.Background(), "", "", userMapping, createTestConfig())
 
 	tests := []struct {
 		name     string
@@ -1435,9 +1427,9 @@ func TestConvertJIRAListsToMarkdown_NumberedLists(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result := mw.convertJIRAListsToMarkdown(tt.input)
+			result := mw.renderWikiMarkup(tt.input)
 			if result != tt.expected {
-				t.Errorf("convertJIRAListsToMarkdown() got:\n%s\n\nwant:\n%s", result, tt.expected)
+				t.Errorf("renderWikiMarkup() got:\n%s\n\nwant:\n%s", result, tt.expected)
 			}
 		})
 	}
@@ -1470,13 +1462,13 @@ func TestChildIssuesField(t *testing.T) {
 				},
 			},
 			expectedOutput: true,
-			expectedText:   "## 子作業項目",
+			expectedText:   "[STORY-1](../STORY-1/)",
 		},
 		{
 			name:           "子課題が設定されていない場合",
 			childIssues:    []ChildIssueInfo{},
 			expectedOutput: false,
-			expectedText:   "## 子作業項目",
+			expectedText:   "",
 		},
 		{
 			name: "複数の課題タイプが混在する場合",
@@ -1524,7 +1516,7 @@ func TestChildIssuesField(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mw := NewMarkdownWriter("", "", nil, createTestConfig())
+			mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
 			var sb strings.Builder
 
 			// generateChildIssuesを呼び出し
@@ -1537,7 +1529,7 @@ func TestChildIssuesField(t *testing.T) {
 					t.Errorf("期待するテキストが出力されていません\n期待: %q\n実際: %s", tt.expectedText, result)
 				}
 			} else {
-				if strings.Contains(result, "## 子作業項目") {
+				if result != "" {
 					t.Errorf("子作業項目セクションが出力されるべきではありません\n実際: %s", result)
 				}
 			}
@@ -1555,83 +1547,6 @@ func TestChildIssuesField(t *testing.T) {
 	}
 }
 
-// TestProtectListLines はリスト行を保護する機能をテストします
-func TestProtectListLines(t *testing.T) {
-	tests := []struct {
-		name              string
-		input             string
-		expectedText      string
-		expectedProtected []string
-	}{
-		{
-			name:              "番号なしリスト行を保護",
-			input:             "* リスト項目1\nテキスト\n** リスト項目2",
-			expectedText:      "___LIST_PLACEHOLDER_0___\nテキスト\n___LIST_PLACEHOLDER_2___",
-			expectedProtected: []string{"* リスト項目1", "** リスト項目2"},
-		},
-		{
-			name:              "番号付きリスト行を保護",
-			input:             "# 番号付き項目\nテキスト",
-			expectedText:      "___LIST_PLACEHOLDER_0___\nテキスト",
-			expectedProtected: []string{"# 番号付き項目"},
-		},
-		{
-			name:              "リスト行が存在しない",
-			input:             "通常のテキストです。",
-			expectedText:      "通常のテキストです。",
-			expectedProtected: nil,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mw := &MarkdownWriter{}
-			gotText, gotProtected := mw.protectListLines(tt.input)
-
-			if gotText != tt.expectedText {
-				t.Errorf("protectListLines() text = %v, want %v", gotText, tt.expectedText)
-			}
-			if !reflect.DeepEqual(gotProtected, tt.expectedProtected) {
-				t.Errorf("protectListLines() protected = %v, want %v", gotProtected, tt.expectedProtected)
-			}
-		})
-	}
-}
-
-// TestRestoreListLines はリスト行を復元する機能をテストします
-func TestRestoreListLines(t *testing.T) {
-	tests := []struct {
-		name      string
-		input     string
-		protected []string
-		expected  string
-	}{
-		{
-			name:      "プレースホルダーを復元",
-			input:     "___LIST_PLACEHOLDER_0___\nテキスト\n___LIST_PLACEHOLDER_1___",
-			protected: []string{"* リスト項目1", "** リスト項目2"},
-			expected:  "* リスト項目1\nテキスト\n** リスト項目2",
-		},
-		{
-			name:      "復元対象が存在しない",
-			input:     "通常のテキスト",
-			protected: []string{},
-			expected:  "通常のテキスト",
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			mw := &MarkdownWriter{}
-			got := mw.restoreListLines(tt.input, tt.protected)
-
-			if got != tt.expected {
-				t.Errorf("restoreListLines() = %v, want %v", got, tt.expected)
-			}
-		})
-	}
-}
-
 // TestConvertJIRAMarkupToMarkdown_BoldJapanese は日本語テキストの太字変換をテストします
 func TestConvertJIRAMarkupToMarkdown_BoldJapanese(t *testing.T) {
 	tests := []struct {
@@ -1970,10 +1885,10 @@ func TestConvertQuoteMarkup(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mw := &MarkdownWriter{}
-			got := mw.convertQuoteMarkup(tt.input)
+			got := mw.renderWikiMarkup(tt.input)
 
 			if got != tt.expected {
-				t.Errorf("convertQuoteMarkup() = %q, want %q", got, tt.expected)
+				t.Errorf("renderWikiMarkup() = %q, want %q", got, tt.expected)
 			}
 		})
 	}
@@ -2021,11 +1936,11 @@ func TestConvertColorMarkup(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mw := NewMarkdownWriter("", "", nil, createTestConfig())
-			got := mw.convertColorMarkup(tt.input)
+			mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
+			got := mw.renderWikiMarkup(tt.input)
 
 			if got != tt.expected {
-				t.Errorf("convertColorMarkup() = %q, want %q", got, tt.expected)
+				t.Errorf("renderWikiMarkup() = %q, want %q", got, tt.expected)
 			}
 		})
 	}
@@ -2090,19 +2005,19 @@ func TestConvertStatusLabelMarkup(t *testing.T) {
 			expected: "通常のテキスト",
 		},
 		{
-			name:     "ステータスラベルではない色マークアップはそのまま",
+			name:     "ステータスラベルではない色マークアップは色指定として変換",
 			input:    "{color:#FF5630}普通の赤文字{color}",
-			expected: "{color:#FF5630}普通の赤文字{color}",
+			expected: `<span style="color:#FF5630">普通の赤文字</span>`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mw := NewMarkdownWriter("", "", nil, createTestConfig())
-			got := mw.convertStatusLabelMarkup(tt.input)
+			mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
+			got := mw.renderWikiMarkup(tt.input)
 
 			if got != tt.expected {
-				t.Errorf("convertStatusLabelMarkup() = %q, want %q", got, tt.expected)
+				t.Errorf("renderWikiMarkup() = %q, want %q", got, tt.expected)
 			}
 		})
 	}
@@ -2233,10 +2148,10 @@ func TestConvertPanelMarkup(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mw := &MarkdownWriter{}
-			got := mw.convertPanelMarkup(tt.input)
+			got := mw.renderWikiMarkup(tt.input)
 
 			if got != tt.expected {
-				t.Errorf("convertPanelMarkup() = %q, want %q", got, tt.expected)
+				t.Errorf("renderWikiMarkup() = %q, want %q", got, tt.expected)
 			}
 		})
 	}
@@ -2319,10 +2234,10 @@ func TestConvertAdmonitionMarkup(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mw := &MarkdownWriter{}
-			got := mw.convertAdmonitionMarkup(tt.input)
+			got := mw.renderWikiMarkup(tt.input)
 
 			if got != tt.expected {
-				t.Errorf("convertAdmonitionMarkup() = %q, want %q", got, tt.expected)
+				t.Errorf("renderWikiMarkup() = %q, want %q", got, tt.expected)
 			}
 		})
 	}
@@ -2350,9 +2265,7 @@ func TestBraceNotationIntegration(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			mw := &MarkdownWriter{}
-			got := mw.convertQuoteMarkup(tt.input)
-			got = mw.convertColorMarkup(got)
-			got = mw.convertAdmonitionMarkup(got)
+			got := mw.renderWikiMarkup(tt.input)
 
 			if got != tt.expected {
 				t.Errorf("Integration test = %q, want %q", got, tt.expected)
@@ -2392,7 +2305,7 @@ func TestGenerateFrontMatter_NewFields(t *testing.T) {
 			},
 			parentInfo: nil,
 			expectStrings: []string{
-				`status =  "進行中"`,
+				`status = "進行中"`,
 				`assignee = "テスト担当者"`,
 				`startdate = "2025-01-10"`,
 				`duedate = "2025-02-01"`,
@@ -2415,7 +2328,7 @@ func TestGenerateFrontMatter_NewFields(t *testing.T) {
 			},
 			parentInfo: nil,
 			expectStrings: []string{
-				`status =  "未着手"`,
+				`status = "未着手"`,
 				`assignee = "未設定"`,
 			},
 			notExpect: []string{
@@ -2442,7 +2355,7 @@ func TestGenerateFrontMatter_NewFields(t *testing.T) {
 			},
 			parentInfo: nil,
 			expectStrings: []string{
-				`status =  "完了"`,
+				`status = "完了"`,
 				`assignee = "テスト担当者"`,
 			},
 			notExpect: []string{
@@ -2498,20 +2411,52 @@ func TestGenerateFrontMatter_NewFields(t *testing.T) {
 			},
 			parentInfo: nil,
 			expectStrings: []string{
-				`status =  "進行中"`,
+				`status = "進行中"`,
 			},
 			notExpect: []string{
 				"fix_versions",
 				"affected_versions",
+				"aliases",
+			},
+		},
+		{
+			name: "別プロジェクトから移動された課題の場合",
+			issue: &cloud.Issue{
+				Key: "NEW-5",
+				Fields: &cloud.IssueFields{
+					Summary: "移動された課題",
+					Type:    cloud.IssueType{Name: "タスク"},
+					Status:  &cloud.Status{Name: "進行中"},
+					Created: cloud.Time(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+					Updated: cloud.Time(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)),
+					Project: cloud.Project{Key: "NEW", Name: "新プロジェクト"},
+				},
+				Changelog: &cloud.Changelog{
+					Histories: []cloud.ChangelogHistory{
+						{Items: []cloud.ChangelogItems{
+							{Field: "status", FromString: "未着手", ToString: "進行中"},
+						}},
+						{Items: []cloud.ChangelogItems{
+							{Field: "project", FromString: "旧プロジェクト", ToString: "中間プロジェクト"},
+							{Field: "Key", FromString: "OLD-1", ToString: "MID-3"},
+						}},
+						{Items: []cloud.ChangelogItems{
+							{Field: "Key", FromString: "MID-3", ToString: "NEW-5"},
+						}},
+					},
+				},
+			},
+			expectStrings: []string{
+				`aliases = ["/OLD/OLD-1/", "/MID/MID-3/"]`,
 			},
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mw := NewMarkdownWriter("", "", nil, createTestConfig())
+			mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
 			var sb strings.Builder
-			mw.generateFrontMatter(&sb, tt.issue, tt.parentInfo)
+			mw.generateFrontMatter(&sb, &IssueData{Issue: tt.issue, ParentInfo: tt.parentInfo}, nil)
 			result := sb.String()
 
 			// 期待される文字列が含まれているか確認
@@ -2596,10 +2541,471 @@ func TestConvertStatusMarkup(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			mw := NewMarkdownWriter("", "", nil, createTestConfig())
-			result := mw.convertStatusMarkup(tt.input)
+			mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
+			result := mw.renderWikiMarkup(tt.input)
 			if result != tt.expected {
-				t.Errorf("convertStatusMarkup() = %q, want %q", result, tt.expected)
+				t.Errorf("renderWikiMarkup() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+// TestWriteProjectIndex はプロジェクトの_index.mdにリーダー、コンポーネント、バージョンが出力されることを確認
+func TestWriteProjectIndex(t *testing.T) {
+	released := true
+	archived := true
+	notReleased := false
+
+	tests := []struct {
+		name          string
+		project       *cloud.Project
+		expectStrings []string
+		notExpect     []string
+	}{
+		{
+			name: "リーダー・コンポーネント・バージョンあり",
+			project: &cloud.Project{
+				Key:         "PROJ",
+				Name:        "テストプロジェクト",
+				Description: "プロジェクトの説明",
+				Lead:        cloud.User{DisplayName: "プロジェクトリーダー"},
+				Components: []cloud.ProjectComponent{
+					{Name: "Backend", Description: "サーバー側", Lead: cloud.User{DisplayName: "担当者A"}},
+					{Name: "Frontend"},
+				},
+				Versions: []cloud.Version{
+					{Name: "v1.0", Released: &released, ReleaseDate: "2025-01-31", Description: "初回リリース"},
+					{Name: "v0.9", Released: &released, Archived: &archived},
+					{Name: "v2.0", Released: &notReleased},
+				},
+			},
+			expectStrings: []string{
+				`title = "📦テストプロジェクト"`,
+				`project_key = "PROJ"`,
+				"# テストプロジェクト\n\nプロジェクトの説明\n\n",
+				"## プロジェクト情報\n\n- **プロジェクトキー**: PROJ\n- **リーダー**: プロジェクトリーダー\n",
+				"## コンポーネント\n\n",
+				"- **Backend**: サーバー側（リーダー: 担当者A）\n",
+				"- **Frontend**\n",
+				"## バージョン\n\n",
+				"- **v1.0** (リリース済み, 2025-01-31): 初回リリース\n",
+				"- **v0.9** (アーカイブ済み, リリース済み)\n",
+				"- **v2.0** (未リリース)\n",
+			},
+		},
+		{
+			name: "コンポーネント・バージョンなし",
+			project: &cloud.Project{
+				Key:  "EMPTY",
+				Name: "空のプロジェクト",
+			},
+			expectStrings: []string{
+				"## プロジェクト情報\n\n- **プロジェクトキー**: EMPTY\n\n",
+			},
+			notExpect: []string{
+				"## コンポーネント",
+				"## バージョン",
+				"**リーダー**",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			mw := NewMarkdownWriter(context.Background(), tmpDir, "", nil, createTestConfig())
+
+			if err := mw.WriteProjectIndex(tt.project); err != nil {
+				t.Fatalf("WriteProjectIndex() error = %v", err)
+			}
+
+			content, err := os.ReadFile(tmpDir + "/" + tt.project.Key + "/_index.md")
+			if err != nil {
+				t.Fatalf("_index.mdの読み込みに失敗しました: %v", err)
+			}
+			result := string(content)
+
+			for _, expected := range tt.expectStrings {
+				if !strings.Contains(result, expected) {
+					t.Errorf("期待される文字列が含まれていません\n期待: %q\n実際の出力:\n%s", expected, result)
+				}
+			}
+			for _, notExpected := range tt.notExpect {
+				if strings.Contains(result, notExpected) {
+					t.Errorf("出力されるべきでない文字列が含まれています\n含まれてはいけない: %q\n実際の出力:\n%s", notExpected, result)
+				}
+			}
+		})
+	}
+}
+
+// TestGenerateComments_Restricted は公開範囲が制限されたコメントの表示設定のテスト
+func TestGenerateComments_Restricted(t *testing.T) {
+	internal := false
+	public := true
+	data := &IssueData{
+		Issue: &cloud.Issue{Key: "TEST-1", Fields: &cloud.IssueFields{}},
+		Comments: []IssueComment{
+			{Comment: cloud.Comment{Author: &cloud.User{DisplayName: "公開ユーザー"}, Created: "2025-01-05T12:00:00.000+0900", Body: "公開コメント"}, JSDPublic: &public},
+			{Comment: cloud.Comment{Author: &cloud.User{DisplayName: "開発者"}, Created: "2025-01-06T12:00:00.000+0900", Body: "ロール限定コメント",
+				Visibility: &cloud.CommentVisibility{Type: "role", Value: "Developers"}}},
+			{Comment: cloud.Comment{Author: &cloud.User{DisplayName: "エージェント"}, Created: "2025-01-07T12:00:00.000+0900", Body: "内部メモ"}, JSDPublic: &internal},
+		},
+	}
+
+	tests := []struct {
+		name          string
+		mode          string
+		expectStrings []string
+		notExpect     []string
+	}{
+		{
+			name: "mark: 制限付きコメントに印を付ける",
+			mode: "mark",
+			expectStrings: []string{
+				"公開ユーザー 2025-01-05 12:00\n\n---\n\n公開コメント",
+				"🔒 開発者 2025-01-06 12:00（ロール: Developers）\n\n---\n\nロール限定コメント",
+				"🔒 エージェント 2025-01-07 12:00（内部コメント）\n\n---\n\n内部メモ",
+			},
+		},
+		{
+			name: "show: 印を付けずに表示する",
+			mode: "show",
+			expectStrings: []string{
+				"開発者 2025-01-06 12:00\n\n---\n\nロール限定コメント",
+				"エージェント 2025-01-07 12:00\n\n---\n\n内部メモ",
+			},
+			notExpect: []string{"🔒"},
+		},
+		{
+			name:          "omit: 制限付きコメントを除外する",
+			mode:          "omit",
+			expectStrings: []string{"公開ユーザー"},
+			notExpect:     []string{"ロール限定コメント", "内部メモ"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createTestConfig()
+			config.Display.RestrictedComments = tt.mode
+			mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+
+			var sb strings.Builder
+			mw.generateComments(&sb, data, map[string]string{}, false)
+			result := sb.String()
+
+			for _, expected := range tt.expectStrings {
+				if !strings.Contains(result, expected) {
+					t.Errorf("期待される文字列が含まれていません\n期待: %q\n実際の出力:\n%s", expected, result)
+				}
+			}
+			for _, notExpected := range tt.notExpect {
+				if strings.Contains(result, notExpected) {
+					t.Errorf("出力されるべきでない文字列が含まれています\n含まれてはいけない: %q\n実際の出力:\n%s", notExpected, result)
+				}
+			}
+		})
+	}
+
+	// すべてのコメントが除外された場合はセクション自体を出力しない
+	config := createTestConfig()
+	config.Display.RestrictedComments = "omit"
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+	var sb strings.Builder
+	mw.generateComments(&sb, &IssueData{Issue: data.Issue, Comments: data.Comments[1:]}, map[string]string{}, false)
+	if sb.Len() != 0 {
+		t.Errorf("コメントセクションが出力されました:\n%s", sb.String())
+	}
+}
+
+// TestGenerateWorklogs は作業ログセクションと作業者別合計のテスト
+func TestGenerateWorklogs(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	started := func(day int) *cloud.Time {
+		t := cloud.Time(time.Date(2025, 1, day, 10, 0, 0, 0, jst))
+		return &t
+	}
+	userA := &cloud.User{DisplayName: "作業者A"}
+	userB := &cloud.User{DisplayName: "作業者B"}
+	worklogs := []cloud.WorklogRecord{
+		{Author: userA, Started: started(5), TimeSpentSeconds: 3600, Comment: "調査"},
+		{Author: userB, Started: started(6), TimeSpentSeconds: 5400, Comment: "実装|レビュー対応\n修正"},
+		{Author: userA, Started: started(7), TimeSpentSeconds: 1800},
+	}
+
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
+	var sb strings.Builder
+	mw.generateWorklogs(&sb, worklogs)
+	result := sb.String()
+
+	expectStrings := []string{
+		"| 作業者 | 開始日時 | 作業時間 | コメント |\n",
+		"| 作業者A | 2025-01-05 10:00 | 1.00h | 調査 |\n",
+		"| 作業者B | 2025-01-06 10:00 | 1.50h | 実装\\|レビュー対応<br>修正 |\n",
+		"| 作業者A | 2025-01-07 10:00 | 0.50h |  |\n",
+		"### 作業者別合計\n\n| 作業者 | 作業時間 |\n|--------|----------|\n| 作業者A | 1.50h |\n| 作業者B | 1.50h |\n| **合計** | **3.00h** |\n",
+	}
+	for _, expected := range expectStrings {
+		if !strings.Contains(result, expected) {
+			t.Errorf("期待される文字列が含まれていません\n期待: %q\n実際の出力:\n%s", expected, result)
+		}
+	}
+
+	// 作業ログがない場合はセクションを出力しない
+	sb.Reset()
+	mw.generateWorklogs(&sb, nil)
+	if sb.Len() != 0 {
+		t.Errorf("作業ログセクションが出力されました:\n%s", sb.String())
+	}
+
+	// フロントマターの合計時間は設定で有効な場合のみ出力する
+	issue := &cloud.Issue{
+		Key: "TEST-1",
+		Fields: &cloud.IssueFields{
+			Type:    cloud.IssueType{Name: "Task"},
+			Status:  &cloud.Status{Name: "進行中"},
+			Project: cloud.Project{Key: "TEST"},
+		},
+	}
+	sb.Reset()
+	mw.generateFrontMatter(&sb, &IssueData{Issue: issue, Worklogs: worklogs}, nil)
+	if strings.Contains(sb.String(), "worklog_total") {
+		t.Errorf("worklog_totalが出力されました:\n%s", sb.String())
+	}
+
+	config := createTestConfig()
+	config.Display.WorklogFrontMatter = true
+	mw = NewMarkdownWriter(context.Background(), "", "", nil, config)
+	sb.Reset()
+	mw.generateFrontMatter(&sb, &IssueData{Issue: issue, Worklogs: worklogs}, nil)
+	if !strings.Contains(sb.String(), "worklog_total = \"3.00h\"\n") {
+		t.Errorf("worklog_totalが出力されていません:\n%s", sb.String())
+	}
+}
+
+// TestGenerateSprints はSprintフィールドからスプリントのフロントマターとセクションを生成することを確認する
+func TestGenerateSprints(t *testing.T) {
+	config := createTestConfig()
+	config.Display.SprintFieldId = "customfield_10020"
+	config.Agile.Enabled = true
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+
+	issue := &cloud.Issue{
+		Key: "TEST-1",
+		Fields: &cloud.IssueFields{
+			Type:    cloud.IssueType{Name: "Story"},
+			Status:  &cloud.Status{Name: "完了"},
+			Project: cloud.Project{Key: "TEST"},
+			Unknowns: map[string]interface{}{
+				"customfield_10020": []interface{}{
+					map[string]interface{}{
+						"id": float64(1), "name": "Sprint 1", "state": "closed", "goal": "ログイン機能",
+						"startDate": "2025-01-06T00:00:00.000Z", "endDate": "2025-01-19T00:00:00.000Z",
+					},
+					map[string]interface{}{"id": float64(2), "name": "Sprint 2", "state": "future"},
+				},
+			},
+		},
+	}
+
+	var sb strings.Builder
+	mw.generateFrontMatter(&sb, &IssueData{Issue: issue}, nil)
+	if !strings.Contains(sb.String(), "sprint = [\"Sprint 1\", \"Sprint 2\"]\n") {
+		t.Errorf("フロントマターにsprintが含まれていません:\n%s", sb.String())
+	}
+
+	sb.Reset()
+	mw.generateSprints(&sb, mw.issueSprints(issue))
+	want := "- [Sprint 1](../sprints/sprint-1/) (完了, 2025-01-06 〜 2025-01-19): ログイン機能\n" +
+		"- [Sprint 2](../sprints/sprint-2/) (未開始)\n"
+	if sb.String() != want {
+		t.Errorf("スプリントセクションが期待と異なります\n期待:\n%s\n実際:\n%s", want, sb.String())
+	}
+
+	// 基本情報ではSprintフィールドをスプリント名で表示する
+	sb.Reset()
+	mw.generateBasicInfo(&sb, issue, FieldNameCache{"customfield_10020": "スプリント"}, nil, nil)
+	if !strings.Contains(sb.String(), "- **スプリント**: Sprint 1, Sprint 2\n") {
+		t.Errorf("基本情報のスプリント表示が期待と異なります:\n%s", sb.String())
+	}
+}
+
+// TestWriteSprintPages はスプリント一覧とスプリントごとのページの出力を確認する
+func TestWriteSprintPages(t *testing.T) {
+	tmpDir := t.TempDir()
+	mw := NewMarkdownWriter(context.Background(), tmpDir, "", nil, createTestConfig())
+
+	reports := []SprintReport{
+		{
+			Sprint:    SprintInfo{ID: 10, Name: "Sprint 1", State: "closed", StartDate: "2025-01-06T00:00:00.000Z", EndDate: "2025-01-19T00:00:00.000Z", Goal: "ログイン機能"},
+			BoardName: "TEST ボード",
+			Issues: []SprintIssueInfo{
+				{Key: "TEST-1", Project: "TEST", Summary: "ログイン画面", Status: "完了", Type: "Story", Completed: true},
+				{Key: "OTHER-2", Project: "OTHER", Summary: "認証API", Status: "進行中", Type: "Task"},
+			},
+		},
+	}
+	if err := mw.WriteSprintPages("TEST", reports); err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+
+	index, err := os.ReadFile(tmpDir + "/TEST/sprints/_index.md")
+	if err != nil {
+		t.Fatalf("スプリント一覧の読み込みに失敗: %v", err)
+	}
+	if !strings.Contains(string(index), "| [Sprint 1](sprint-10/) | TEST ボード | 完了 | 2025-01-06 〜 2025-01-19 | 1 / 2 |\n") {
+		t.Errorf("スプリント一覧が期待と異なります:\n%s", index)
+	}
+
+	page, err := os.ReadFile(tmpDir + "/TEST/sprints/sprint-10.md")
+	if err != nil {
+		t.Fatalf("スプリントページの読み込みに失敗: %v", err)
+	}
+	expectStrings := []string{
+		"sprint_id = 10\n",
+		"startdate = \"2025-01-06\"\n",
+		"- **ゴール**: ログイン機能\n",
+		"## コミットした課題（2件）\n",
+		"| [TEST-1](../../TEST-1/) | 📗 Story | ログイン画面 | 完了 | ✅ |\n",
+		"| [OTHER-2](../../../OTHER/OTHER-2/) | ☑️ Task | 認証API | 進行中 |  |\n",
+		"## 完了した課題（1件）\n\n- [TEST-1](../../TEST-1/) 📗 ログイン画面\n",
+	}
+	for _, expected := range expectStrings {
+		if !strings.Contains(string(page), expected) {
+			t.Errorf("期待される文字列が含まれていません\n期待: %q\n実際の出力:\n%s", expected, page)
+		}
+	}
+}
+
+// TestWriteReleaseNotes はリリース一覧とバージョンごとのリリースノート（課題タイプ別）の出力を確認する
+func TestWriteReleaseNotes(t *testing.T) {
+	tmpDir := t.TempDir()
+	mw := NewMarkdownWriter(context.Background(), tmpDir, "", nil, createTestConfig())
+
+	released := true
+	newIssue := func(key, summary, issueType, status string) cloud.Issue {
+		return cloud.Issue{Key: key, Fields: &cloud.IssueFields{
+			Summary: summary,
+			Type:    cloud.IssueType{Name: issueType},
+			Status:  &cloud.Status{Name: status},
+		}}
+	}
+	notes := []ReleaseNote{
+		{
+			Version: cloud.Version{ID: "10001", Name: "1.0.0", Released: &released, ReleaseDate: "2025-02-01", Description: "初回リリース"},
+			Issues: []cloud.Issue{
+				newIssue("TEST-1", "ログイン機能", "Story", "完了"),
+				newIssue("TEST-2", "ログアウトできない", "Bug", "完了"),
+				newIssue("TEST-3", "パスワード再設定", "Story", "完了"),
+			},
+		},
+	}
+	if err := mw.WriteReleaseNotes("TEST", notes); err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+
+	index, err := os.ReadFile(tmpDir + "/TEST/releases/_index.md")
+	if err != nil {
+		t.Fatalf("リリース一覧の読み込みに失敗: %v", err)
+	}
+	if !strings.Contains(string(index), "| [1.0.0](version-10001/) | リリース済み | 2025-02-01 | 3 |\n") {
+		t.Errorf("リリース一覧が期待と異なります:\n%s", index)
+	}
+
+	page, err := os.ReadFile(tmpDir + "/TEST/releases/version-10001.md")
+	if err != nil {
+		t.Fatalf("リリースノートの読み込みに失敗: %v", err)
+	}
+	expectStrings := []string{
+		"released = true\n",
+		"releasedate = \"2025-02-01\"\n",
+		"- **説明**: 初回リリース\n",
+		"## 課題（3件）\n\n### 📗 Story（2件）\n\n- [TEST-1](../../TEST-1/) ログイン機能（完了）\n- [TEST-3](../../TEST-3/) パスワード再設定（完了）\n\n",
+		"### 🐞 Bug（1件）\n\n- [TEST-2](../../TEST-2/) ログアウトできない（完了）\n",
+	}
+	for _, expected := range expectStrings {
+		if !strings.Contains(string(page), expected) {
+			t.Errorf("期待される文字列が含まれていません\n期待: %q\n実際の出力:\n%s", expected, page)
+		}
+	}
+}
+
+// BenchmarkConvertJIRAMarkupToMarkdown は大きな説明のWiki記法の変換のベンチマーク
+func BenchmarkConvertJIRAMarkupToMarkdown(b *testing.B) {
+	section := "h2. 概要\n\nこれは*太字*と_斜体_と-取り消し-を含む説明です。[リンク|https://example.com/path-to-page] {{inline_code}} [~accountid:user-1]\n" +
+		"* 項目1\n** 項目1-1 {color:red}赤い文字{color}\n# 手順1\n# 手順2 {status:colour=Green}完了{status}\n\n" +
+		"||項目||値||\n|*A*|1|\n|B|2\n続き|\n\n" +
+		"{code:go}\nfunc main() {\n\tfmt.Println(\"*not bold*\")\n}\n{code}\n\n" +
+		"{panel:title=注意|bgColor=#fffae6}\nパネルの中身 !screenshot_1.png|thumbnail!\n{panel}\n\n{quote}引用文{quote}\n\n"
+	input := strings.Repeat(section, 200)
+	mw := NewMarkdownWriter(context.Background(), "", "", UserMapping{"user-1": "山田 太郎"}, createTestConfig())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		mw.convertJIRAMarkupToMarkdown(input)
+	}
+}
+
+// TestReplaceImageReferences は画像参照と添付ファイルへのリンクの置き換えをテスト
+func TestReplaceImageReferences(t *testing.T) {
+	attachmentMap := map[string]string{
+		"screen shot.png": "TEST-1_screen shot.png",
+		"spec.pdf":        "TEST-1_spec.pdf",
+	}
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "画像",
+			input:    "!screen shot.png!",
+			expected: "![screen shot.png](/attachments/TEST-1_screen%20shot.png)",
+		},
+		{
+			name:     "サムネイル",
+			input:    "!screen shot.png|thumbnail!",
+			expected: `<img src="/attachments/TEST-1_screen%20shot.png" alt="screen shot.png" class="thumbnail">`,
+		},
+		{
+			name:     "幅・高さ・代替テキスト",
+			input:    "!screen shot.png|width=300, height=200,alt=画面!",
+			expected: `<img src="/attachments/TEST-1_screen%20shot.png" alt="画面" width="300" height="200">`,
+		},
+		{
+			name:     "対応していない属性のみの場合はMarkdownの画像",
+			input:    "!screen shot.png|border=1!",
+			expected: "![screen shot.png](/attachments/TEST-1_screen%20shot.png)",
+		},
+		{
+			name:     "見つからない画像はそのまま",
+			input:    "!missing.png|thumbnail!",
+			expected: "!missing.png|thumbnail!",
+		},
+		{
+			name:     "添付ファイルへのリンク",
+			input:    "[^spec.pdf] と [仕様書|^spec.pdf]",
+			expected: "[spec.pdf](/attachments/TEST-1_spec.pdf) と [仕様書](/attachments/TEST-1_spec.pdf)",
+		},
+		{
+			name:     "画像の添付ファイルへのリンクは画像にしない",
+			input:    "[^screen shot.png]",
+			expected: "[screen shot.png](/attachments/TEST-1_screen%20shot.png)",
+		},
+		{
+			name:     "見つからない添付ファイルへのリンク",
+			input:    "[^missing.pdf]",
+			expected: "📎 missing.pdf",
+		},
+	}
+
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mw.replaceImageReferences(tt.input, attachmentMap); got != tt.expected {
+				t.Errorf("replaceImageReferences()\n実際: %q\n期待: %q", got, tt.expected)
 			}
 		})
 	}
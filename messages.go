@@ -0,0 +1,142 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+
+	"github.com/BurntSushi/toml"
+)
+
+// jaMessagesText は日本語の文言
+//
+//go:embed messages/ja.toml
+var jaMessagesText string
+
+// enMessagesText は英語の文言
+//
+//go:embed messages/en.toml
+var enMessagesText string
+
+// Messages はページに出力する見出し・ラベル・値の文言（メッセージID → 文言）
+// 文言にはfmtの書式（%s、%d）を含めることができる
+type Messages map[string]string
+
+// builtinMessages は組み込みの文言（display.languageで選択する）
+var builtinMessages = map[string]Messages{
+	"ja": mustParseMessages("ja.toml", jaMessagesText),
+	"en": mustParseMessages("en.toml", enMessagesText),
+}
+
+// defaultMessages は既定の文言（日本語）
+var defaultMessages = builtinMessages["ja"]
+
+// messageVerbPattern は文言に含まれるfmtの書式にマッチする
+var messageVerbPattern = regexp.MustCompile(`%[-+# 0-9.]*[a-zA-Z%]`)
+
+// parseMessages はTOML形式（メッセージID = "文言"）の文言を解析する
+func parseMessages(text string) (Messages, error) {
+	messages := make(Messages)
+	if _, err := toml.Decode(text, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// mustParseMessages は組み込みの文言を解析する（解析に失敗した場合はpanic）
+func mustParseMessages(name, text string) Messages {
+	messages, err := parseMessages(text)
+	if err != nil {
+		panic(fmt.Sprintf("組み込みの文言 %s の解析に失敗しました: %v", name, err))
+	}
+	return messages
+}
+
+// loadMessages は表示言語の文言を返す（pathを指定した場合はファイルの文言で置き換える）
+// 組み込みに無い言語は文言ファイルの指定が必要で、ファイルに無い文言は英語で出力する
+func loadMessages(language, path string) (Messages, error) {
+	if language == "" {
+		language = "ja"
+	}
+	base, builtin := builtinMessages[language]
+	if path == "" {
+		if !builtin {
+			return nil, fmt.Errorf("組み込みの文言が無い言語です（文言ファイルを指定してください）: %s", language)
+		}
+		return base, nil
+	}
+	if !builtin {
+		base = builtinMessages["en"]
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("文言ファイルの読み込みに失敗しました: %w", err)
+	}
+	custom, err := parseMessages(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("文言ファイルの解析に失敗しました: %w", err)
+	}
+
+	ids := make([]string, 0, len(custom))
+	for id := range custom {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	messages := make(Messages, len(base))
+	for id, text := range base {
+		messages[id] = text
+	}
+	for _, id := range ids {
+		original, exists := base[id]
+		if !exists {
+			return nil, fmt.Errorf("文言ファイルに未定義のメッセージIDがあります: %s", id)
+		}
+		// 値の数と種類が異なると整形できないため、書式は組み込みの文言と同じにする
+		if !slices.Equal(messageVerbPattern.FindAllString(custom[id], -1), messageVerbPattern.FindAllString(original, -1)) {
+			return nil, fmt.Errorf("文言ファイルの %s の書式（%%s、%%d）が組み込みの文言 %q と異なります: %q", id, original, custom[id])
+		}
+		messages[id] = custom[id]
+	}
+	return messages, nil
+}
+
+// messagesFromConfig は設定（display.language、display.messages）の文言を返す
+// 設定の読み込み時に検証済みのため、読み込みに失敗した場合は警告を出して既定の文言を使用する
+func messagesFromConfig(config *Config) Messages {
+	if config == nil {
+		return defaultMessages
+	}
+	messages, err := loadMessages(config.Display.Language, config.Display.Messages)
+	if err != nil {
+		slog.Warn("文言を読み込めないため既定の文言を使用します", "language", config.Display.Language, "messages", config.Display.Messages, "error", err)
+		fmt.Printf("警告: 文言を読み込めないため既定の文言を使用します: %v\n", err)
+		return defaultMessages
+	}
+	return messages
+}
+
+// Text はメッセージIDの文言を返す（argsを指定した場合は文言の書式で整形する）
+// 文言が無い場合は既定の文言、既定の文言にも無い場合はメッセージIDを返す
+func (m Messages) Text(id string, args ...interface{}) string {
+	text, exists := m[id]
+	if !exists {
+		if text, exists = defaultMessages[id]; !exists {
+			return id
+		}
+	}
+	if len(args) == 0 {
+		return text
+	}
+	return fmt.Sprintf(text, args...)
+}
+
+// msg はMarkdownWriterの表示言語の文言を返す
+func (mw *MarkdownWriter) msg(id string, args ...interface{}) string {
+	return mw.messages.Text(id, args...)
+}
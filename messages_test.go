@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// writeTestMessages はテスト用の文言ファイルを作成する
+func writeTestMessages(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "messages.toml")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("文言ファイルの作成に失敗しました: %v", err)
+	}
+	return path
+}
+
+// TestBuiltinMessages は組み込みの文言が同じメッセージIDと書式を持つことを確認する
+func TestBuiltinMessages(t *testing.T) {
+	ja := builtinMessages["ja"]
+	for language, messages := range builtinMessages {
+		if len(messages) != len(ja) {
+			t.Errorf("%s の文言の数が日本語と異なります: %d, 日本語: %d", language, len(messages), len(ja))
+		}
+		for id, text := range ja {
+			translated, exists := messages[id]
+			if !exists {
+				t.Errorf("%s にメッセージID %s がありません", language, id)
+				continue
+			}
+			if !slices.Equal(messageVerbPattern.FindAllString(translated, -1), messageVerbPattern.FindAllString(text, -1)) {
+				t.Errorf("%s の %s の書式が日本語と異なります: %q, 日本語: %q", language, id, translated, text)
+			}
+		}
+	}
+}
+
+// TestLoadMessages は表示言語の選択と文言ファイルによる置き換えを確認する
+func TestLoadMessages(t *testing.T) {
+	messages, err := loadMessages("en", "")
+	if err != nil {
+		t.Fatalf("loadMessages(en) error = %v", err)
+	}
+	if got := messages.Text("count", "Issues", 3); got != "Issues (3)" {
+		t.Errorf("Text(count) = %q", got)
+	}
+
+	// 文言ファイルで一部の文言を置き換え、残りは組み込みの文言を使用する
+	path := writeTestMessages(t, "section_comments = \"Kommentare\"\ncount = \"%s: %d\"\n")
+	messages, err = loadMessages("de", path)
+	if err != nil {
+		t.Fatalf("loadMessages(de) error = %v", err)
+	}
+	if got := messages.Text("section_comments"); got != "Kommentare" {
+		t.Errorf("Text(section_comments) = %q", got)
+	}
+	if got := messages.Text("count", "Vorgänge", 2); got != "Vorgänge: 2" {
+		t.Errorf("Text(count) = %q", got)
+	}
+	if got := messages.Text("assignee"); got != "Assignee" {
+		t.Errorf("Text(assignee) = %q, 英語の文言を期待", got)
+	}
+
+	tests := []struct {
+		name        string
+		language    string
+		content     string
+		errContains string
+	}{
+		{"組み込みに無い言語で文言ファイルなし", "fr", "", "組み込みの文言が無い言語"},
+		{"未定義のメッセージID", "ja", "unknown_label = \"?\"\n", "未定義のメッセージID"},
+		{"書式が異なる", "ja", "count = \"%s件\"\n", "書式"},
+		{"TOMLの構文エラー", "ja", "count = \n", "文言ファイルの解析に失敗しました"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := ""
+			if tt.content != "" {
+				path = writeTestMessages(t, tt.content)
+			}
+			_, err := loadMessages(tt.language, path)
+			if err == nil || !strings.Contains(err.Error(), tt.errContains) {
+				t.Errorf("loadMessages() error = %v, %q を含むエラーを期待", err, tt.errContains)
+			}
+		})
+	}
+}
+
+// TestGenerateMarkdown_English はdisplay.language = "en" で見出し・ラベル・値を英語で出力することを確認する
+func TestGenerateMarkdown_English(t *testing.T) {
+	config := createTestConfig()
+	config.Display.Language = "en"
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+
+	issue := &cloud.Issue{
+		Key: "TEST-1",
+		Fields: &cloud.IssueFields{
+			Summary:     "English page",
+			Description: "説明",
+			Type:        cloud.IssueType{Name: "Task"},
+			Status:      &cloud.Status{Name: "Done"},
+			Project:     cloud.Project{Key: "TEST"},
+			Unknowns: map[string]interface{}{
+				"customfield_10050": true,
+			},
+		},
+	}
+	worklogs := []cloud.WorklogRecord{
+		{Author: &cloud.User{DisplayName: "Alice"}, TimeSpentSeconds: 3600},
+	}
+
+	got, err := mw.generateMarkdown(&IssueData{Issue: issue, Worklogs: worklogs}, nil, FieldNameCache{})
+	if err != nil {
+		t.Fatalf("generateMarkdown() error = %v", err)
+	}
+	for _, expected := range []string{
+		"assignee = \"None\"\n",
+		"## Details\n",
+		"- **Key**: TEST-1\n",
+		"- **Assignee**: None\n",
+		"- **Priority**: None\n",
+		"- **Custom field 10050**: Yes\n",
+		"## Description\n",
+		"## Work log\n\n| Author | Started | Time spent | Comment |\n|--------|---------|------------|---------|\n",
+		"### Total by author\n",
+		"| **Total** | **1.00h** |\n",
+	} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("出力に %q が含まれていません\n%s", expected, got)
+		}
+	}
+	if strings.Contains(got, "基本情報") || strings.Contains(got, "未設定") {
+		t.Errorf("英語の出力に日本語の文言が含まれています\n%s", got)
+	}
+
+	// ADFのファイル名の無い添付ファイル
+	media := adfDoc(t, `{"type":"mediaSingle","content":[{"type":"media","attrs":{"id":"uuid-1","type":"file"}}]}`)
+	if got := mw.convertADFToMarkdown(media, nil); got != "📎 Attachment" {
+		t.Errorf("convertADFToMarkdown() = %q, want %q", got, "📎 Attachment")
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// 削除・移動された課題の整理（--prune）
+//
+// search/project/syncコマンドの出力後に、JQLの対象プロジェクトの出力済みの課題（Markdown・JSON）を
+// JQLの検索結果と突き合わせる。検索結果に含まれない課題は1件ずつJiraに問い合わせ、
+//   - 存在しない課題（削除された、または参照権限がない）は、Markdown・JSON・添付ファイルを整理する
+//   - 別のキーが返った課題（別プロジェクトへ移動された課題）は、古いキーのファイルを整理する
+//     （移動先のページは変更履歴から古いURLをaliasesに出力する）
+//   - 同じキーが返った課題（JQLの条件から外れただけの課題）はそのまま残す
+// 整理は prune.action に従い、アーカイブ先へ移動するか削除する。
+
+// prunePageSize は出力済みの課題と突き合わせる検索で1回のリクエストで取得する件数
+const prunePageSize = 100
+
+// MovedIssue は別プロジェクトへ移動された課題の古いキーと新しいキー
+type MovedIssue struct {
+	OldKey   string
+	NewKey   string
+	Exported bool // 移動先の課題がJQLの検索結果に含まれる（今回の実行で出力済み）
+}
+
+// PruneReport は削除・移動された課題の整理結果
+type PruneReport struct {
+	Action  string       // "archive" または "delete"
+	Deleted []string     // Jiraに存在しない課題キー
+	Moved   []MovedIssue // 別プロジェクトへ移動された課題
+	Files   []string     // アーカイブ・削除したファイル
+	Errors  []string     // 確認・整理に失敗した課題（ファイルは残す）
+}
+
+// Pruner は出力済みの課題とJiraの課題を突き合わせ、削除・移動された課題のファイルを整理する
+type Pruner struct {
+	config     *Config
+	jiraClient *JIRAClient
+}
+
+// NewPruner は新しいPrunerを作成する
+func NewPruner(config *Config, jiraClient *JIRAClient) *Pruner {
+	return &Pruner{config: config, jiraClient: jiraClient}
+}
+
+// Prune はJQLの検索結果に含まれない出力済みの課題を確認し、削除・移動された課題のファイルを整理する
+// 対象は検索結果の課題のプロジェクトとprojectKeysのプロジェクトの出力済みの課題
+// ctxがキャンセルされた場合はその時点までの結果を返す
+func (p *Pruner) Prune(ctx context.Context, jql string, projectKeys []string) (*PruneReport, error) {
+	issues, err := p.jiraClient.SearchIssueRelations(jql, prunePageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	currentKeys := make(map[string]bool, len(issues))
+	projects := make(map[string]bool)
+	for _, projectKey := range projectKeys {
+		projects[projectKey] = true
+	}
+	for _, issue := range issues {
+		currentKeys[issue.Key] = true
+		if issue.Fields != nil && issue.Fields.Project.Key != "" {
+			projects[issue.Fields.Project.Key] = true
+		}
+	}
+
+	sortedProjects := make([]string, 0, len(projects))
+	for projectKey := range projects {
+		sortedProjects = append(sortedProjects, projectKey)
+	}
+	sort.Strings(sortedProjects)
+
+	return p.reconcile(ctx, sortedProjects, currentKeys)
+}
+
+// reconcile はプロジェクトの出力済みの課題のうちcurrentKeysに含まれないものをJiraに問い合わせて整理する
+func (p *Pruner) reconcile(ctx context.Context, projects []string, currentKeys map[string]bool) (*PruneReport, error) {
+	report := &PruneReport{Action: p.config.Prune.Action}
+
+	for _, projectKey := range projects {
+		exportedKeys, err := p.exportedIssueKeys(projectKey)
+		if err != nil {
+			return report, err
+		}
+		for _, issueKey := range exportedKeys {
+			if currentKeys[issueKey] {
+				continue
+			}
+			if err := ctx.Err(); err != nil {
+				return report, err
+			}
+
+			newKey, err := p.jiraClient.ResolveIssueKey(issueKey)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", issueKey, err))
+				continue
+			}
+			if newKey == issueKey {
+				continue
+			}
+
+			files, err := p.removeIssueFiles(projectKey, issueKey)
+			report.Files = append(report.Files, files...)
+			if err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", issueKey, err))
+				continue
+			}
+			if newKey == "" {
+				report.Deleted = append(report.Deleted, issueKey)
+			} else {
+				report.Moved = append(report.Moved, MovedIssue{OldKey: issueKey, NewKey: newKey, Exported: currentKeys[newKey]})
+			}
+		}
+	}
+	return report, nil
+}
+
+// exportedIssueKeys はプロジェクトの出力済みの課題キー（MarkdownまたはJSONがある課題）をキー順に返す
+func (p *Pruner) exportedIssueKeys(projectKey string) ([]string, error) {
+	keys := make(map[string]bool)
+	collect := func(dir, ext string) error {
+		entries, err := os.ReadDir(filepath.Join(dir, projectKey))
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return fmt.Errorf("出力済みの課題の読み込みに失敗しました: %w", err)
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() || filepath.Ext(name) != ext || strings.HasPrefix(name, "_") {
+				continue
+			}
+			keys[strings.TrimSuffix(name, ext)] = true
+		}
+		return nil
+	}
+
+	if err := collect(p.config.Output.MarkdownDir, ".md"); err != nil {
+		return nil, err
+	}
+	if p.config.Output.JSONDir != "" {
+		if err := collect(p.config.Output.JSONDir, ".json"); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]string, 0, len(keys))
+	for key := range keys {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// removeIssueFiles は課題のMarkdown・JSON・添付ファイルをアーカイブ先へ移動するか削除し、対象のファイルを返す
+// アーカイブ先はarchive_dir配下に markdown/<プロジェクト>、json/<プロジェクト>、attachments の構成で置く
+func (p *Pruner) removeIssueFiles(projectKey, issueKey string) ([]string, error) {
+	type target struct {
+		path       string
+		archiveDir string
+	}
+	var targets []target
+
+	addIfExists := func(path, archiveDir string) {
+		if _, err := os.Stat(path); err == nil {
+			targets = append(targets, target{path: path, archiveDir: archiveDir})
+		}
+	}
+	addIfExists(filepath.Join(p.config.Output.MarkdownDir, projectKey, issueKey+".md"),
+		filepath.Join(p.config.Prune.ArchiveDir, "markdown", projectKey))
+	if p.config.Output.JSONDir != "" {
+		addIfExists(filepath.Join(p.config.Output.JSONDir, projectKey, issueKey+".json"),
+			filepath.Join(p.config.Prune.ArchiveDir, "json", projectKey))
+	}
+
+	// 添付ファイルは「<課題キー>_<ファイル名>」で保存されている
+	attachments, err := filepath.Glob(filepath.Join(p.config.Output.AttachmentsDir, issueKey+"_*"))
+	if err != nil {
+		return nil, fmt.Errorf("添付ファイルの検索に失敗しました: %w", err)
+	}
+	for _, path := range attachments {
+		targets = append(targets, target{path: path, archiveDir: filepath.Join(p.config.Prune.ArchiveDir, "attachments")})
+	}
+
+	var files []string
+	for _, t := range targets {
+		if p.config.Prune.Action == "delete" {
+			if err := os.Remove(t.path); err != nil {
+				return files, fmt.Errorf("ファイルの削除に失敗しました: %w", err)
+			}
+		} else {
+			if err := os.MkdirAll(t.archiveDir, 0755); err != nil {
+				return files, fmt.Errorf("アーカイブ先のディレクトリ作成に失敗しました: %w", err)
+			}
+			if err := os.Rename(t.path, filepath.Join(t.archiveDir, filepath.Base(t.path))); err != nil {
+				return files, fmt.Errorf("ファイルのアーカイブに失敗しました: %w", err)
+			}
+		}
+		files = append(files, t.path)
+	}
+	return files, nil
+}
+
+// UnexportedMovedKeys は今回の実行で出力していない移動先の課題キーを返す
+// （移動先の課題を出力し、aliasesに移動前のURLを反映するために使用する）
+func (r *PruneReport) UnexportedMovedKeys() []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, moved := range r.Moved {
+		if !moved.Exported && !seen[moved.NewKey] {
+			seen[moved.NewKey] = true
+			keys = append(keys, moved.NewKey)
+		}
+	}
+	return keys
+}
+
+// Print は整理結果を表示する
+func (r *PruneReport) Print(archiveDir string) {
+	fmt.Printf("\n削除・移動された課題の整理\n")
+	if len(r.Deleted) == 0 && len(r.Moved) == 0 && len(r.Errors) == 0 {
+		fmt.Printf("- 削除・移動された課題はありません\n")
+		return
+	}
+	if len(r.Deleted) > 0 {
+		fmt.Printf("- 削除された課題: %d 件 (%s)\n", len(r.Deleted), strings.Join(r.Deleted, ", "))
+	}
+	if len(r.Moved) > 0 {
+		moved := make([]string, len(r.Moved))
+		for i, m := range r.Moved {
+			moved[i] = fmt.Sprintf("%s → %s", m.OldKey, m.NewKey)
+		}
+		fmt.Printf("- 移動された課題: %d 件 (%s)\n", len(r.Moved), strings.Join(moved, ", "))
+	}
+	if len(r.Files) > 0 {
+		if r.Action == "delete" {
+			fmt.Printf("- 削除したファイル: %d 件\n", len(r.Files))
+		} else {
+			fmt.Printf("- アーカイブしたファイル: %d 件（%s）\n", len(r.Files), archiveDir)
+		}
+		for _, path := range r.Files {
+			fmt.Printf("    %s\n", path)
+		}
+	}
+	if len(r.Errors) > 0 {
+		fmt.Printf("- 確認できなかった課題: %d 件（ファイルは残しています）\n", len(r.Errors))
+		for _, msg := range r.Errors {
+			fmt.Printf("    %s\n", msg)
+		}
+	}
+}
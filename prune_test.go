@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// TestPruner は削除・移動された課題の検出とファイルの整理のテスト
+func TestPruner(t *testing.T) {
+	// Jira上の課題: TEST-1（JQLに一致）、TEST-2（JQLの条件から外れた）、TEST-3はOTHER-7へ移動、TEST-4は削除
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/rest/api/3/search/jql":
+			json.NewEncoder(w).Encode(JQLSearchResponse{
+				IsLast: true,
+				Issues: []cloud.Issue{{Key: "TEST-1", Fields: &cloud.IssueFields{Project: cloud.Project{Key: "TEST"}}}},
+			})
+		case strings.HasPrefix(r.URL.Path, "/rest/api/3/issue/"):
+			switch strings.TrimPrefix(r.URL.Path, "/rest/api/3/issue/") {
+			case "TEST-2":
+				json.NewEncoder(w).Encode(map[string]string{"key": "TEST-2"})
+			case "TEST-3":
+				json.NewEncoder(w).Encode(map[string]string{"key": "OTHER-7"})
+			default:
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"errorMessages":["課題が存在しないか、表示する権限がありません。"]}`))
+			}
+		default:
+			t.Errorf("想定外のリクエスト: %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	jiraClient := &JIRAClient{
+		ctx:        context.Background(),
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		email:      "test@example.com",
+		apiToken:   "test-token",
+	}
+
+	setup := func(t *testing.T, action string) *Config {
+		dir := t.TempDir()
+		config := &Config{
+			Output: OutputConfig{
+				MarkdownDir:    filepath.Join(dir, "markdown"),
+				AttachmentsDir: filepath.Join(dir, "attachments"),
+				JSONDir:        filepath.Join(dir, "json"),
+			},
+			Prune: PruneConfig{Action: action, ArchiveDir: filepath.Join(dir, "archive")},
+		}
+		files := []string{
+			filepath.Join(config.Output.MarkdownDir, "TEST", "_index.md"),
+			filepath.Join(config.Output.MarkdownDir, "TEST", "TEST-1.md"),
+			filepath.Join(config.Output.MarkdownDir, "TEST", "TEST-2.md"),
+			filepath.Join(config.Output.MarkdownDir, "TEST", "TEST-3.md"),
+			filepath.Join(config.Output.JSONDir, "TEST", "TEST-3.json"),
+			filepath.Join(config.Output.JSONDir, "TEST", "TEST-4.json"),
+			filepath.Join(config.Output.AttachmentsDir, "TEST-4_image.png"),
+			filepath.Join(config.Output.AttachmentsDir, "TEST-40_image.png"),
+		}
+		for _, path := range files {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path, []byte("test"), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return config
+	}
+
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	t.Run("アーカイブ", func(t *testing.T) {
+		config := setup(t, "archive")
+		report, err := NewPruner(config, jiraClient).Prune(context.Background(), "project = TEST", nil)
+		if err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+
+		if !reflect.DeepEqual(report.Deleted, []string{"TEST-4"}) {
+			t.Errorf("削除された課題 = %v, want [TEST-4]", report.Deleted)
+		}
+		if !reflect.DeepEqual(report.Moved, []MovedIssue{{OldKey: "TEST-3", NewKey: "OTHER-7"}}) {
+			t.Errorf("移動された課題 = %+v", report.Moved)
+		}
+		if !reflect.DeepEqual(report.UnexportedMovedKeys(), []string{"OTHER-7"}) {
+			t.Errorf("出力する移動先の課題 = %v", report.UnexportedMovedKeys())
+		}
+		if len(report.Files) != 4 || len(report.Errors) != 0 {
+			t.Errorf("整理したファイル = %v, エラー = %v", report.Files, report.Errors)
+		}
+
+		// 残るファイル
+		for _, path := range []string{
+			filepath.Join(config.Output.MarkdownDir, "TEST", "_index.md"),
+			filepath.Join(config.Output.MarkdownDir, "TEST", "TEST-1.md"),
+			filepath.Join(config.Output.MarkdownDir, "TEST", "TEST-2.md"),
+			filepath.Join(config.Output.AttachmentsDir, "TEST-40_image.png"),
+		} {
+			if !exists(path) {
+				t.Errorf("ファイルが残っていません: %s", path)
+			}
+		}
+		// アーカイブされたファイル
+		for _, path := range []string{
+			filepath.Join(config.Prune.ArchiveDir, "markdown", "TEST", "TEST-3.md"),
+			filepath.Join(config.Prune.ArchiveDir, "json", "TEST", "TEST-3.json"),
+			filepath.Join(config.Prune.ArchiveDir, "json", "TEST", "TEST-4.json"),
+			filepath.Join(config.Prune.ArchiveDir, "attachments", "TEST-4_image.png"),
+		} {
+			if !exists(path) {
+				t.Errorf("アーカイブされていません: %s", path)
+			}
+		}
+		if exists(filepath.Join(config.Output.MarkdownDir, "TEST", "TEST-3.md")) {
+			t.Errorf("移動された課題のページが残っています")
+		}
+	})
+
+	t.Run("削除", func(t *testing.T) {
+		config := setup(t, "delete")
+		report, err := NewPruner(config, jiraClient).Prune(context.Background(), "project = TEST", nil)
+		if err != nil {
+			t.Fatalf("Prune() error = %v", err)
+		}
+		if len(report.Files) != 4 {
+			t.Errorf("削除したファイル = %v", report.Files)
+		}
+		for _, path := range report.Files {
+			if exists(path) {
+				t.Errorf("ファイルが削除されていません: %s", path)
+			}
+		}
+		if exists(config.Prune.ArchiveDir) {
+			t.Errorf("deleteの場合はアーカイブ先を作成しません")
+		}
+	})
+}
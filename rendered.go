@@ -0,0 +1,854 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// renderedFields（JiraがWiki記法をレンダリングしたHTML）→ Markdown変換
+//
+// display.source = "rendered" または "auto" の場合に、説明・コメント本文をWiki記法ではなく
+// レンダリング済みのHTMLから変換する。HTMLは構文木に解析してからMarkdownを出力するため、
+// 出力するHTMLタグはパネル・色指定・メンション等の既存のクラスを付けたものに限られる。
+// スクリプト等の要素は中身ごと除き、javascript: 等のURLのリンク・画像は出力しない。
+// 添付ファイルのURL（/secure/attachment/...）は保存した添付ファイルへのパスに置き換える。
+
+// htmlNode はHTMLの構文木のノード（Tagが空の場合はテキストノード）
+type htmlNode struct {
+	Tag      string            // 要素名（小文字）
+	Text     string            // テキストノードの文字列（文字参照は展開済み）
+	Attrs    map[string]string // 属性（名前は小文字、値は文字参照を展開済み）
+	Children []*htmlNode
+	parent   *htmlNode
+}
+
+// htmlVoidElements は終了タグのない要素
+var htmlVoidElements = map[string]bool{
+	"br": true, "hr": true, "img": true, "input": true, "meta": true,
+	"link": true, "col": true, "wbr": true, "area": true, "base": true, "source": true,
+}
+
+// htmlDroppedElements は中身ごと出力しない要素（サニタイズ）
+var htmlDroppedElements = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "embed": true,
+	"noscript": true, "template": true, "textarea": true, "select": true, "button": true,
+	"form": true, "svg": true, "math": true, "head": true, "title": true,
+}
+
+// htmlBlockElements はブロックとして扱う要素（段落を区切る）
+var htmlBlockElements = map[string]bool{
+	"p": true, "div": true, "ul": true, "ol": true, "li": true, "blockquote": true,
+	"pre": true, "table": true, "hr": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "dl": true, "dt": true, "dd": true,
+}
+
+// parseHTML はHTMLを構文木に解析する
+// 閉じられていない要素（<p>、<li>、<td> 等）は次の同種の要素・親要素の終了タグで閉じる
+func parseHTML(s string) *htmlNode {
+	root := &htmlNode{Tag: "#root"}
+	cur := root
+	for i := 0; i < len(s); {
+		if s[i] != '<' {
+			end := strings.IndexByte(s[i:], '<')
+			if end < 0 {
+				end = len(s) - i
+			}
+			cur.appendText(html.UnescapeString(s[i : i+end]))
+			i += end
+			continue
+		}
+
+		rest := s[i:]
+		switch {
+		case strings.HasPrefix(rest, "<!--"):
+			end := strings.Index(rest[4:], "-->")
+			if end < 0 {
+				return root
+			}
+			i += 4 + end + 3
+			continue
+		case strings.HasPrefix(rest, "<!"), strings.HasPrefix(rest, "<?"):
+			end := strings.IndexByte(rest, '>')
+			if end < 0 {
+				return root
+			}
+			i += end + 1
+			continue
+		case strings.HasPrefix(rest, "</"):
+			name, _, _, n, ok := parseHTMLTag(rest[1:])
+			if !ok {
+				cur.appendText("<")
+				i++
+				continue
+			}
+			i += n + 1
+			// 対応する開始タグまで閉じる（見つからない終了タグは無視する）
+			for node := cur; node != root; node = node.parent {
+				if node.Tag == name {
+					cur = node.parent
+					break
+				}
+			}
+			continue
+		}
+
+		name, attrs, selfClosing, n, ok := parseHTMLTag(rest)
+		if !ok {
+			cur.appendText("<")
+			i++
+			continue
+		}
+		i += n
+		if htmlDroppedElements[name] {
+			if !selfClosing {
+				if end := indexFold(s[i:], "</"+name); end >= 0 {
+					i += end
+				} else {
+					i = len(s)
+				}
+			}
+			continue
+		}
+
+		cur = closeImplicitHTMLElements(cur, name)
+		node := &htmlNode{Tag: name, Attrs: attrs, parent: cur}
+		cur.Children = append(cur.Children, node)
+		if !selfClosing && !htmlVoidElements[name] {
+			cur = node
+		}
+	}
+	return root
+}
+
+// appendText はテキストノードを追加する（直前がテキストノードの場合は連結する）
+func (n *htmlNode) appendText(text string) {
+	if text == "" {
+		return
+	}
+	if last := len(n.Children) - 1; last >= 0 && n.Children[last].Tag == "" {
+		n.Children[last].Text += text
+		return
+	}
+	n.Children = append(n.Children, &htmlNode{Text: text, parent: n})
+}
+
+// closeImplicitHTMLElements は開始タグnameの前に暗黙に閉じられる要素を閉じ、新しい親要素を返す
+func closeImplicitHTMLElements(cur *htmlNode, name string) *htmlNode {
+	switch name {
+	case "li":
+		if cur.Tag == "li" {
+			return cur.parent
+		}
+	case "td", "th":
+		if cur.Tag == "td" || cur.Tag == "th" {
+			return cur.parent
+		}
+	case "tr":
+		for cur.Tag == "td" || cur.Tag == "th" || cur.Tag == "tr" {
+			cur = cur.parent
+		}
+		return cur
+	}
+	if htmlBlockElements[name] && cur.Tag == "p" {
+		return cur.parent
+	}
+	return cur
+}
+
+// parseHTMLTag は開始タグ（<name attr="value">）を解析し、要素名・属性・自己終了タグか・タグの長さを返す
+func parseHTMLTag(s string) (string, map[string]string, bool, int, bool) {
+	i := 1
+	for i < len(s) && (isASCIIAlnum(s[i]) || s[i] == '-' || s[i] == ':') {
+		i++
+	}
+	if i == 1 || !(s[1] >= 'a' && s[1] <= 'z' || s[1] >= 'A' && s[1] <= 'Z') {
+		return "", nil, false, 0, false
+	}
+	name := strings.ToLower(s[1:i])
+	attrs := make(map[string]string)
+
+	for i < len(s) {
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		if i >= len(s) {
+			break
+		}
+		switch {
+		case s[i] == '>':
+			return name, attrs, false, i + 1, true
+		case strings.HasPrefix(s[i:], "/>"):
+			return name, attrs, true, i + 2, true
+		case s[i] == '/':
+			i++
+			continue
+		}
+
+		start := i
+		for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '=' && s[i] != '>' && !strings.HasPrefix(s[i:], "/>") {
+			i++
+		}
+		key := strings.ToLower(s[start:i])
+		for i < len(s) && isHTMLSpace(s[i]) {
+			i++
+		}
+		value := ""
+		if i < len(s) && s[i] == '=' {
+			i++
+			for i < len(s) && isHTMLSpace(s[i]) {
+				i++
+			}
+			if i < len(s) && (s[i] == '"' || s[i] == '\'') {
+				end := strings.IndexByte(s[i+1:], s[i])
+				if end < 0 {
+					break
+				}
+				value = s[i+1 : i+1+end]
+				i += end + 2
+			} else {
+				start := i
+				for i < len(s) && !isHTMLSpace(s[i]) && s[i] != '>' {
+					i++
+				}
+				value = s[start:i]
+			}
+		}
+		if key != "" {
+			attrs[key] = html.UnescapeString(value)
+		}
+	}
+	return "", nil, false, 0, false
+}
+
+func isHTMLSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f'
+}
+
+// hasClass は要素のclass属性にclassNameが含まれるかを判定する
+func (n *htmlNode) hasClass(className string) bool {
+	for _, c := range strings.Fields(n.Attrs["class"]) {
+		if c == className {
+			return true
+		}
+	}
+	return false
+}
+
+// textContent は要素内のテキストを連結する（<br>は改行にする）
+func (n *htmlNode) textContent() string {
+	if n.Tag == "" {
+		return n.Text
+	}
+	if n.Tag == "br" {
+		return "\n"
+	}
+	var sb strings.Builder
+	for _, child := range n.Children {
+		sb.WriteString(child.textContent())
+	}
+	return sb.String()
+}
+
+// findHTML は子孫要素から条件に合う最初の要素を探す
+func (n *htmlNode) findHTML(match func(*htmlNode) bool) *htmlNode {
+	for _, child := range n.Children {
+		if child.Tag == "" {
+			continue
+		}
+		if match(child) {
+			return child
+		}
+		if found := child.findHTML(match); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// htmlStyleProperty はstyle属性から指定したプロパティの値を取得する
+func htmlStyleProperty(style, property string) string {
+	for _, decl := range strings.Split(style, ";") {
+		key, value, ok := strings.Cut(decl, ":")
+		if ok && strings.EqualFold(strings.TrimSpace(key), property) {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+// isSafeURL はリンク・画像として出力してよいURL（http、https、ftp、mailto、相対URL、ページ内リンク）かを判定する
+func isSafeURL(rawURL string) bool {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "ftp", "mailto":
+		return true
+	}
+	return false
+}
+
+// htmlRenderer はrenderedFieldsのHTMLの構文木をMarkdownに変換する
+type htmlRenderer struct {
+	mw              *MarkdownWriter
+	attachmentMap   map[string]string // 元のファイル名 → 保存されたファイル名
+	attachmentNames map[string]string // 添付ファイルID → 元のファイル名
+}
+
+// convertRenderedHTML はrenderedFieldsのHTMLをMarkdownに変換する
+// 添付ファイルのURLは課題の添付ファイルIDから元のファイル名を求め、attachmentMapで保存されたファイルへのパスにする
+func (mw *MarkdownWriter) convertRenderedHTML(text string, issue *cloud.Issue, attachmentMap map[string]string) string {
+	r := &htmlRenderer{mw: mw, attachmentMap: attachmentMap, attachmentNames: make(map[string]string)}
+	if issue != nil && issue.Fields != nil {
+		for _, attachment := range issue.Fields.Attachments {
+			if attachment != nil {
+				r.attachmentNames[attachment.ID] = attachment.Filename
+			}
+		}
+	}
+	return strings.TrimSpace(r.blocks(parseHTML(text).Children))
+}
+
+// blocks はノードをブロックごとに変換して空行で連結する（連続するインラインのノードは1つの段落にする）
+func (r *htmlRenderer) blocks(nodes []*htmlNode) string {
+	var parts []string
+	var inline []*htmlNode
+	flush := func() {
+		if text := strings.TrimSpace(r.inline(inline)); text != "" {
+			parts = append(parts, text)
+		}
+		inline = nil
+	}
+	for _, node := range nodes {
+		if !htmlBlockElements[node.Tag] {
+			inline = append(inline, node)
+			continue
+		}
+		flush()
+		if text := strings.Trim(r.block(node), "\n"); text != "" {
+			parts = append(parts, text)
+		}
+	}
+	flush()
+	return strings.Join(parts, "\n\n")
+}
+
+// block はブロックの要素を変換する
+func (r *htmlRenderer) block(node *htmlNode) string {
+	switch node.Tag {
+	case "p", "dt", "dd":
+		return strings.TrimSpace(r.inline(node.Children))
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		level := int(node.Tag[1] - '0')
+		return strings.Repeat("#", level) + " " + strings.TrimSpace(strings.ReplaceAll(r.inline(node.Children), "  \n", " "))
+	case "ul", "ol":
+		return r.list(node, 0)
+	case "blockquote":
+		return quoteLines(r.blocks(node.Children))
+	case "pre":
+		return r.codeBlock(node, "")
+	case "table":
+		return r.table(node)
+	case "hr":
+		return "***"
+	case "div":
+		return r.div(node)
+	default:
+		return r.blocks(node.Children)
+	}
+}
+
+// div はJiraのマクロ（コード・パネル・メッセージ）のdivを変換する
+func (r *htmlRenderer) div(node *htmlNode) string {
+	// {code}・{noformat}: <div class="code panel"><div class="codeHeader"><b>タイトル</b></div><div class="codeContent"><pre>
+	if node.hasClass("code") || node.hasClass("preformatted") {
+		if pre := node.findHTML(func(n *htmlNode) bool { return n.Tag == "pre" }); pre != nil {
+			title := ""
+			if header := node.findHTML(func(n *htmlNode) bool { return n.hasClass("codeHeader") }); header != nil {
+				title = strings.TrimSpace(header.textContent())
+			}
+			return r.codeBlock(pre, title)
+		}
+	}
+
+	// {panel}: <div class="panel" style="background-color: #deebff"><div class="panelHeader">タイトル</div><div class="panelContent">
+	if node.hasClass("panel") {
+		panelClass := "panel-info"
+		if color := htmlStyleProperty(node.Attrs["style"], "background-color"); color != "" {
+			panelClass = getPanelClass(color)
+		}
+		title := ""
+		var body []*htmlNode
+		for _, child := range node.Children {
+			switch {
+			case child.hasClass("panelHeader"):
+				title = strings.TrimSpace(child.textContent())
+			case child.hasClass("panelContent"):
+				body = append(body, child.Children...)
+			}
+		}
+		if body == nil {
+			body = node.Children
+		}
+		return formatPanel(panelClass, title, r.blocks(body))
+	}
+
+	// {info}・{note}・{warning}・{tip}: <div class="aui-message info shadowed information-macro">
+	if node.hasClass("information-macro") || node.hasClass("aui-message") {
+		var admonition string
+		for _, class := range htmlAdmonitionClasses {
+			if node.hasClass(class[0]) {
+				admonition = class[1]
+				break
+			}
+		}
+		title := ""
+		var body []*htmlNode
+		for _, child := range node.Children {
+			switch {
+			case child.hasClass("title"):
+				title = strings.TrimSpace(child.textContent())
+			case child.hasClass("aui-icon"):
+				// アイコンは出力しない
+			case child.hasClass("message-content"):
+				body = append(body, child.Children...)
+			default:
+				body = append(body, child)
+			}
+		}
+		return formatPanel(getAdmonitionClass(admonition), title, r.blocks(body))
+	}
+
+	return r.blocks(node.Children)
+}
+
+// htmlAdmonitionClasses はJiraのメッセージ（information-macro）のクラスとWiki記法のマクロ名
+// {note}は warning、{warning}は error のクラスで出力される
+var htmlAdmonitionClasses = [][2]string{
+	{"info", "info"},
+	{"warning", "note"},
+	{"error", "warning"},
+	{"success", "tip"},
+	{"hint", "tip"},
+	{"note", "note"},
+	{"tip", "tip"},
+}
+
+// codeBlock はpre要素をコードブロックに変換する（言語はpreのclass属性 code-java 等から取得する）
+func (r *htmlRenderer) codeBlock(pre *htmlNode, title string) string {
+	language := ""
+	for _, c := range strings.Fields(pre.Attrs["class"]) {
+		if lang, ok := strings.CutPrefix(c, "code-"); ok {
+			language = lang
+		}
+	}
+	code := fmt.Sprintf("```%s\n%s\n```", language, strings.Trim(pre.textContent(), "\n"))
+	if title != "" {
+		return fmt.Sprintf(`<div class="code-title">%s</div>`, html.EscapeString(title)) + "\n" + code
+	}
+	return code
+}
+
+// list は箇条書き・番号付きリストを変換する（ネストは4スペースで字下げする）
+func (r *htmlRenderer) list(node *htmlNode, depth int) string {
+	marker := "- "
+	if node.Tag == "ol" {
+		marker = "1. "
+	}
+	indent := strings.Repeat("    ", depth)
+
+	var lines []string
+	for _, item := range node.Children {
+		if item.Tag != "li" {
+			continue
+		}
+		var inline []*htmlNode
+		var nested []string
+		for _, child := range item.Children {
+			if child.Tag == "ul" || child.Tag == "ol" {
+				nested = append(nested, r.list(child, depth+1))
+				continue
+			}
+			inline = append(inline, child)
+		}
+		text := strings.TrimSpace(r.inline(inline))
+		text = strings.ReplaceAll(text, "\n", "\n"+indent+strings.Repeat(" ", len(marker)))
+		lines = append(lines, indent+marker+text)
+		lines = append(lines, nested...)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// table はテーブルをMarkdownのテーブルに変換する（先頭行のセルがすべて<th>の場合は見出し行にする）
+func (r *htmlRenderer) table(node *htmlNode) string {
+	var rows [][]*htmlNode
+	var collect func(n *htmlNode)
+	collect = func(n *htmlNode) {
+		for _, child := range n.Children {
+			switch child.Tag {
+			case "tr":
+				var cells []*htmlNode
+				for _, cell := range child.Children {
+					if cell.Tag == "td" || cell.Tag == "th" {
+						cells = append(cells, cell)
+					}
+				}
+				rows = append(rows, cells)
+			case "thead", "tbody", "tfoot":
+				collect(child)
+			}
+		}
+	}
+	collect(node)
+
+	var header []string
+	if len(rows) > 0 && len(rows[0]) > 0 {
+		isHeader := true
+		for _, cell := range rows[0] {
+			isHeader = isHeader && cell.Tag == "th"
+		}
+		if isHeader {
+			for _, cell := range rows[0] {
+				header = append(header, r.tableCell(cell))
+			}
+			rows = rows[1:]
+		}
+	}
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		for _, cell := range row {
+			cells[i] = append(cells[i], r.tableCell(cell))
+		}
+	}
+	return formatMarkdownTable(header, cells)
+}
+
+// tableCell はセルの中身を1行に変換する（改行・段落の区切りは<br>にする）
+func (r *htmlRenderer) tableCell(cell *htmlNode) string {
+	text := strings.TrimSpace(r.blocks(cell.Children))
+	text = strings.ReplaceAll(text, "  \n", "\n")
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\n\n", "<br>"), "\n", "<br>")
+}
+
+// inline はインラインのノードを変換する（改行の直後の空白は出力しない）
+func (r *htmlRenderer) inline(nodes []*htmlNode) string {
+	var sb strings.Builder
+	for _, node := range nodes {
+		text := r.inlineNode(node)
+		if strings.HasSuffix(sb.String(), "\n") {
+			text = strings.TrimLeft(text, " ")
+		}
+		sb.WriteString(text)
+	}
+	return sb.String()
+}
+
+// inlineNode はインラインのノードを変換する
+func (r *htmlRenderer) inlineNode(node *htmlNode) string {
+	switch node.Tag {
+	case "":
+		return escapeMarkdownText(collapseHTMLSpace(node.Text))
+	case "br":
+		return "  \n"
+	case "b", "strong":
+		return wrapMarkdown("**", r.inline(node.Children))
+	case "i", "em":
+		return wrapMarkdown("*", r.inline(node.Children))
+	case "del", "s", "strike":
+		return wrapMarkdown("~~", r.inline(node.Children))
+	case "ins", "u":
+		return wrapHTML("u", r.inline(node.Children))
+	case "sup", "sub", "cite":
+		return wrapHTML(node.Tag, r.inline(node.Children))
+	case "tt", "code", "kbd", "samp":
+		return inlineCode(node.textContent())
+	case "font":
+		return r.colored(node.Attrs["color"], node)
+	case "span":
+		if node.hasClass("aui-lozenge") || node.hasClass("jira-issue-status-lozenge") {
+			return fmt.Sprintf(`<span class="status">%s</span>`, html.EscapeString(strings.TrimSpace(node.textContent())))
+		}
+		return r.colored(htmlStyleProperty(node.Attrs["style"], "color"), node)
+	case "a":
+		return r.link(node)
+	case "img":
+		return r.image(node, false)
+	default:
+		if htmlBlockElements[node.Tag] {
+			// 段落の中のブロック（<li>の中の<p>等）は改行で区切る
+			return "\n" + r.blocks([]*htmlNode{node}) + "\n"
+		}
+		return r.inline(node.Children)
+	}
+}
+
+// isSafeColor はstyle属性に出力してよい色（空でなく、属性やタグを閉じる文字・宣言の区切りを含まない）かを判定する
+func isSafeColor(color string) bool {
+	return color != "" && !strings.ContainsAny(color, "\"'<>;&\\")
+}
+
+// colored は色指定の要素を{color}と同じspanタグに変換する（色がない場合・不正な値の場合は中身のみ）
+func (r *htmlRenderer) colored(color string, node *htmlNode) string {
+	content := r.inline(node.Children)
+	if !isSafeColor(color) {
+		return content
+	}
+	return fmt.Sprintf(`<span style="color:%s">%s</span>`, color, content)
+}
+
+// link はリンクを変換する（メンション・添付ファイル・サムネイル画像のリンクを含む）
+func (r *htmlRenderer) link(node *htmlNode) string {
+	href := node.Attrs["href"]
+
+	// メンション: <a class="user-hover" data-account-id="..." href=".../ViewProfile.jspa?accountId=...">
+	if accountID := htmlMentionAccountID(node); accountID != "" || node.hasClass("user-hover") {
+		name := strings.TrimPrefix(strings.TrimSpace(node.textContent()), "@")
+		if userName, exists := r.mw.userMapping[accountID]; exists && userName != "" {
+			name = userName
+		}
+		if name == "" {
+			name = accountID
+		}
+		return `<span class="mention">@` + html.EscapeString(name) + `</span>`
+	}
+
+	// サムネイル画像のリンクは画像のみ出力する
+	if img := node.findHTML(func(n *htmlNode) bool { return n.Tag == "img" && !n.hasClass("rendericon") }); img != nil {
+		if _, _, ok := htmlAttachmentRef(href); ok || href == "" {
+			return r.image(img, true)
+		}
+	}
+
+	// 添付ファイルへのリンク
+	if id, name, ok := htmlAttachmentRef(href); ok {
+		text := strings.TrimSpace(r.inline(node.Children))
+		if relPath, name, ok := r.attachmentPath(id, name); ok {
+			if text == "" {
+				text = escapeMarkdownText(name)
+			}
+			return fmt.Sprintf("[%s](%s)", text, relPath)
+		}
+		if text == "" {
+			text = escapeMarkdownText(name)
+		}
+		return "📎 " + text
+	}
+
+	text := r.inline(node.Children)
+	// アンカー（<a name="...">）やURLが不正なリンクはテキストのみ出力する
+	if href == "" || !isSafeURL(href) {
+		return text
+	}
+	if strings.TrimSpace(text) == "" {
+		text = escapeMarkdownText(href)
+	}
+	return fmt.Sprintf("[%s](%s)", text, markdownURL(href))
+}
+
+// htmlMentionAccountID はメンションのリンクからアカウントIDを取得する
+func htmlMentionAccountID(node *htmlNode) string {
+	if accountID := node.Attrs["data-account-id"]; accountID != "" {
+		return accountID
+	}
+	if u, err := url.Parse(node.Attrs["href"]); err == nil && strings.HasSuffix(u.Path, "/ViewProfile.jspa") {
+		return u.Query().Get("accountId")
+	}
+	return ""
+}
+
+// image は画像を変換する
+// 絵文字はWiki記法と同じ絵文字、添付ファイルは保存したファイルへのパスにする（サムネイルはthumbnailクラスのimgタグ）
+func (r *htmlRenderer) image(node *htmlNode, thumbnail bool) string {
+	src, alt := node.Attrs["src"], node.Attrs["alt"]
+	// リンクの種類を表すアイコンは出力しない
+	if node.hasClass("rendericon") {
+		return ""
+	}
+	if node.hasClass("emoticon") {
+		if emoji, ok := wikiEmoticons[alt]; ok {
+			return emoji
+		}
+		return escapeMarkdownText(alt)
+	}
+
+	if id, name, ok := htmlAttachmentRef(src); ok {
+		thumbnail = thumbnail || strings.Contains(src, "/thumbnail/")
+		relPath, name, found := r.attachmentPath(id, name)
+		if !found {
+			if name == "" {
+				name = alt
+			}
+			return "📎 " + escapeMarkdownText(name)
+		}
+		attrs := map[string]string{}
+		if thumbnail {
+			attrs["thumbnail"] = ""
+		}
+		for _, key := range []string{"width", "height", "title"} {
+			if value := node.Attrs[key]; value != "" {
+				attrs[key] = value
+			}
+		}
+		if tag, ok := imageTag(name, relPath, attrs); ok {
+			return tag
+		}
+		return fmt.Sprintf("![%s](%s)", escapeMarkdownText(name), relPath)
+	}
+
+	if src == "" || !isSafeURL(src) {
+		return escapeMarkdownText(alt)
+	}
+	return fmt.Sprintf("![%s](%s)", escapeMarkdownText(alt), markdownURL(src))
+}
+
+// htmlAttachmentRef は添付ファイル・サムネイルのURLから添付ファイルIDとファイル名を取得する
+// 対応するURL: /secure/attachment/{id}/{name}、/secure/thumbnail/{id}/{name}、/rest/api/{n}/attachment/content/{id}
+func htmlAttachmentRef(rawURL string) (string, string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", false
+	}
+	for _, prefix := range []string{"/secure/attachment/", "/secure/thumbnail/", "/attachment/content/", "/attachment/thumbnail/"} {
+		idx := strings.Index(u.Path, prefix)
+		if idx < 0 {
+			continue
+		}
+		id, name, _ := strings.Cut(u.Path[idx+len(prefix):], "/")
+		if id == "" {
+			return "", "", false
+		}
+		// サムネイルのファイル名（_thumb_10001.png）は元のファイル名ではない
+		if strings.HasPrefix(name, "_thumb_") {
+			name = ""
+		}
+		return id, name, true
+	}
+	return "", "", false
+}
+
+// attachmentPath は添付ファイルIDまたはファイル名から保存された添付ファイルのパスと元のファイル名を返す
+func (r *htmlRenderer) attachmentPath(id, name string) (string, string, bool) {
+	if original, ok := r.attachmentNames[id]; ok {
+		name = original
+	}
+	relPath, ok := attachmentPath(name, r.attachmentMap)
+	return relPath, name, ok
+}
+
+// wrapMarkdown は前後の空白を装飾の外に出して記号で囲む（** text ** のような閉じられない強調を防ぐ）
+func wrapMarkdown(mark, s string) string {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return s
+	}
+	start := strings.Index(s, trimmed)
+	return s[:start] + mark + trimmed + mark + s[start+len(trimmed):]
+}
+
+// wrapHTML はタグで囲む（中身が空の場合は出力しない）
+func wrapHTML(tag, s string) string {
+	if strings.TrimSpace(s) == "" {
+		return s
+	}
+	return "<" + tag + ">" + s + "</" + tag + ">"
+}
+
+// collapseHTMLSpace はHTMLの連続する空白・改行を1つの空白にする
+func collapseHTMLSpace(s string) string {
+	var sb strings.Builder
+	space := false
+	for i := 0; i < len(s); i++ {
+		if isHTMLSpace(s[i]) {
+			if !space {
+				sb.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		sb.WriteByte(s[i])
+		space = false
+	}
+	return sb.String()
+}
+
+// markdownEscaper はHTMLのテキストをMarkdownの記法・HTMLタグとして解釈されないようにエスケープする
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "`", "\\`", "[", `\[`, "]", `\]`, "~", `\~`, "|", `\|`,
+	"&", "&amp;", "<", "&lt;", ">", "&gt;",
+)
+
+// escapeMarkdownText はHTMLのテキストをMarkdownの本文としてエスケープする
+func escapeMarkdownText(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// markdownURL はMarkdownのリンク先として使えるようにURLの空白・括弧をエスケープする
+func markdownURL(u string) string {
+	return strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29").Replace(strings.TrimSpace(u))
+}
+
+// useRenderedHTML は課題の説明・コメントをrenderedFieldsのHTMLから変換するかを判定する（display.source）
+// "auto" の場合は、Wiki記法の変換でマクロ等の記法が残る説明・コメントが1つでもある課題のみHTMLを使用する
+func (mw *MarkdownWriter) useRenderedHTML(data *IssueData) bool {
+	source := "wiki"
+	if mw.config != nil && mw.config.Display.Source != "" {
+		source = mw.config.Display.Source
+	}
+	switch source {
+	case "rendered":
+		return true
+	case "auto":
+		texts := []string{}
+		if data.Issue.Fields != nil {
+			texts = append(texts, data.Issue.Fields.Description)
+		}
+		comments := data.Comments
+		if comments == nil {
+			comments = issueComments(data.Issue)
+		}
+		for _, comment := range comments {
+			texts = append(texts, comment.Body)
+		}
+		for _, text := range texts {
+			if _, isADF := parseADF(text); !isADF && wikiHasUnconvertedMarkup(text) {
+				slog.Info("Wiki記法で変換できない記法があるため、renderedFieldsのHTMLから変換します", "issueKey", data.Issue.Key)
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// convertIssueText は説明・コメント本文をMarkdownに変換する
+// useRenderedの場合はrenderedFieldsのHTMLから変換する（HTMLがない場合・本文がADFの場合はconvertTextで変換する）
+func (mw *MarkdownWriter) convertIssueText(text, renderedHTML string, useRendered bool, issue *cloud.Issue, attachmentMap map[string]string) string {
+	if useRendered && strings.TrimSpace(renderedHTML) != "" {
+		if _, isADF := parseADF(text); !isADF {
+			return mw.convertRenderedHTML(renderedHTML, issue, attachmentMap)
+		}
+	}
+	return mw.convertText(text, attachmentMap)
+}
+
+// renderedCommentBody はコメント本文のHTMLを取得する
+// コメントの取得時のrenderedBody、なければ課題のrenderedFieldsのコメントを使用する
+func renderedCommentBody(issue *cloud.Issue, comment IssueComment) string {
+	if comment.RenderedBody != "" {
+		return comment.RenderedBody
+	}
+	if issue.RenderedFields == nil || issue.RenderedFields.Comments == nil {
+		return ""
+	}
+	for _, rendered := range issue.RenderedFields.Comments.Comments {
+		if rendered != nil && rendered.ID == comment.ID {
+			return rendered.Body
+		}
+	}
+	return ""
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// TestParseHTML はHTMLの構文木の解析（暗黙に閉じる要素・除外する要素・文字参照）をテスト
+func TestParseHTML(t *testing.T) {
+	// outline は構文木を要素名とテキストの入れ子で表す
+	var outline func(n *htmlNode) string
+	outline = func(n *htmlNode) string {
+		if n.Tag == "" {
+			return n.Text
+		}
+		var parts []string
+		for _, child := range n.Children {
+			parts = append(parts, outline(child))
+		}
+		return n.Tag + "(" + strings.Join(parts, ",") + ")"
+	}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"入れ子の要素", "<p>a<b>b</b>c</p>", "#root(p(a,b(b),c))"},
+		{"文字参照", "<p>&lt;tag&gt; &amp; &quot;x&quot;</p>", "#root(p(<tag> & \"x\"))"},
+		{"閉じられていないli", "<ul><li>1<li>2</ul>", "#root(ul(li(1),li(2)))"},
+		{"閉じられていないtd・tr", "<table><tr><td>a<td>b<tr><td>c</table>", "#root(table(tr(td(a),td(b)),tr(td(c))))"},
+		{"ブロックの前で段落を閉じる", "<p>a<div>b</div>", "#root(p(a),div(b))"},
+		{"空要素と自己終了タグ", "a<br>b<br/>c<img src=x>", "#root(a,br(),b,br(),c,img())"},
+		{"スクリプトは中身ごと除外", "a<script>alert('<b>x</b>')</script>b<style>p{}</style>", "#root(ab)"},
+		{"コメントは除外", "a<!-- <b>x</b> -->b", "#root(ab)"},
+		{"対応しない終了タグは無視", "<p>a</span>b</p>", "#root(p(ab))"},
+		{"タグでない<", "a < b <3", "#root(a < b <3)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := outline(parseHTML(tt.input)); got != tt.expected {
+				t.Errorf("parseHTML() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	attrs := parseHTML(`<a HREF="/x?a=1&amp;b=2" class='user-hover' data-account-id=abc disabled>`).Children[0].Attrs
+	if attrs["href"] != "/x?a=1&b=2" || attrs["class"] != "user-hover" || attrs["data-account-id"] != "abc" {
+		t.Errorf("属性 = %v", attrs)
+	}
+	if _, ok := attrs["disabled"]; !ok {
+		t.Errorf("値のない属性がありません: %v", attrs)
+	}
+}
+
+// TestConvertRenderedHTML はrenderedFieldsのHTMLからMarkdownへの変換をテスト
+func TestConvertRenderedHTML(t *testing.T) {
+	issue := &cloud.Issue{
+		Key: "TEST-1",
+		Fields: &cloud.IssueFields{
+			Attachments: []*cloud.Attachment{
+				{ID: "10001", Filename: "screen shot.png"},
+				{ID: "10002", Filename: "spec.pdf"},
+			},
+		},
+	}
+	attachmentMap := map[string]string{
+		"screen shot.png": "TEST-1_screen shot.png",
+		"spec.pdf":        "TEST-1_spec.pdf",
+	}
+	userMapping := map[string]string{"557058:abc": "山田太郎"}
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "段落と改行",
+			input:    "<p>1行目<br/>\n2行目</p>\n\n<p>次の段落</p>",
+			expected: "1行目  \n2行目\n\n次の段落",
+		},
+		{
+			name:     "見出し（アンカーは出力しない）",
+			input:    `<h2><a name="準備"></a>準備</h2>`,
+			expected: "## 準備",
+		},
+		{
+			name:     "装飾",
+			input:    "<p><b>太字</b> <em>斜体</em> <del>取消</del> <ins>下線</ins> x<sup>2</sup> <cite>出典</cite> <tt>code</tt></p>",
+			expected: "**太字** *斜体* ~~取消~~ <u>下線</u> x<sup>2</sup> <cite>出典</cite> `code`",
+		},
+		{
+			name:     "ネストしたリスト",
+			input:    "<ul>\n<li>項目1\n<ol>\n<li>手順</li>\n</ol>\n</li>\n<li>項目2</li>\n</ul>",
+			expected: "- 項目1\n    1. 手順\n- 項目2",
+		},
+		{
+			name:     "コードブロック（タイトル・言語付き）",
+			input:    `<div class="code panel" style="border-width: 1px;"><div class="codeHeader panelHeader" style="border-bottom-width: 1px;"><b>Main.java</b></div><div class="codeContent panelContent"><pre class="code-java"><span class="code-keyword">if</span> (a &lt; b) {}</pre></div></div>`,
+			expected: "<div class=\"code-title\">Main.java</div>\n```java\nif (a < b) {}\n```",
+		},
+		{
+			name:     "整形済みテキスト",
+			input:    `<div class="preformatted panel"><div class="preformattedContent panelContent"><pre>*not bold*</pre></div></div>`,
+			expected: "```\n*not bold*\n```",
+		},
+		{
+			name:     "パネル",
+			input:    `<div class="panel" style="background-color: #ffebe6;border-width: 1px;"><div class="panelHeader"><b>注意</b></div><div class="panelContent"><p>本文</p></div></div>`,
+			expected: "<div class=\"panel panel-error\"><div class=\"panel-title\">注意</div><div class=\"panel-body\">本文</div></div>",
+		},
+		{
+			name:     "メッセージ（{note}）",
+			input:    `<div class="aui-message warning shadowed information-macro"><span class="aui-icon icon-warning">Icon</span><div class="message-content"><p>メモ</p></div></div>`,
+			expected: "<div class=\"panel panel-note\"><div class=\"panel-body\">メモ</div></div>",
+		},
+		{
+			name:     "引用",
+			input:    "<blockquote><p>引用1</p><p>引用2</p></blockquote>",
+			expected: "> 引用1\n>\n> 引用2",
+		},
+		{
+			name:     "テーブル",
+			input:    "<div class='table-wrap'><table class='confluenceTable'><tbody><tr><th class='confluenceTh'>項目</th><th class='confluenceTh'>値</th></tr><tr><td class='confluenceTd'>a|b</td><td class='confluenceTd'>1<br/>2</td></tr></tbody></table></div>",
+			expected: "| 項目 | 値 |\n| ------ | ------ |\n| a\\|b | 1<br>2 |",
+		},
+		{
+			name:     "リンクとメンション",
+			input:    `<p><a href="https://example.com/a (1)" class="external-link" rel="nofollow">リンク</a> <a href="https://test.atlassian.net/secure/ViewProfile.jspa?accountId=557058%3Aabc" class="user-hover" data-account-id="557058:abc">Taro Yamada</a> <a class="user-hover" data-account-id="unknown">不明</a></p>`,
+			expected: `[リンク](https://example.com/a%20%281%29) <span class="mention">@山田太郎</span> <span class="mention">@不明</span>`,
+		},
+		{
+			name:     "添付ファイルの画像とサムネイル",
+			input:    `<p><span class="image-wrap" style=""><img src="/secure/attachment/10001/10001_screen+shot.png" style="border: 0px solid black" /></span> <a id="10001_thumb" href="https://test.atlassian.net/secure/attachment/10001/screen%20shot.png" title="screen shot.png"><img src="https://test.atlassian.net/secure/thumbnail/10001/_thumb_10001.png" style="border: 0px solid black" /></a></p>`,
+			expected: `![screen shot.png](/attachments/TEST-1_screen%20shot.png) <img src="/attachments/TEST-1_screen%20shot.png" alt="screen shot.png" class="thumbnail">`,
+		},
+		{
+			name:     "添付ファイルへのリンク",
+			input:    `<p><span class="nobr"><a href="/secure/attachment/10002/10002_spec.pdf" title="spec.pdf attached to TEST-1">spec.pdf<sup><img class="rendericon" src="/images/icons/link_attachment_7.gif" height="7" width="7" align="absmiddle" alt="" border="0"/></sup></a></span> <a href="/secure/attachment/99999/old.pdf">old.pdf</a></p>`,
+			expected: "[spec.pdf](/attachments/TEST-1_spec.pdf) 📎 old.pdf",
+		},
+		{
+			name:     "絵文字と色",
+			input:    `<p><img class="emoticon" src="/images/icons/emoticons/check.png" height="16" width="16" align="absmiddle" alt="(/)" border="0"/> <font color="#ff5630">赤</font> <span style="color: red">赤</span></p>`,
+			expected: `✅ <span style="color:#ff5630">赤</span> <span style="color:red">赤</span>`,
+		},
+		{
+			name:     "水平線",
+			input:    "<p>上</p>\n<hr />\n<p>下</p>",
+			expected: "上\n\n***\n\n下",
+		},
+		{
+			name:     "サニタイズ",
+			input:    `<p onclick="x()">a<script>alert(1)</script> <a href="javascript:alert(1)">b</a> <img src="javascript:alert(1)" alt="c"> <iframe src="https://evil.example.com"></iframe></p>`,
+			expected: "a b c",
+		},
+		{
+			name:     "Markdownの記法・HTMLタグとして解釈される文字はエスケープ",
+			input:    "<p>a*b*c _d_ [e] &lt;script&gt; `f`</p>",
+			expected: "a\\*b\\*c \\_d\\_ \\[e\\] &lt;script&gt; \\`f\\`",
+		},
+	}
+
+	mw := NewMarkdownWriter(context.Background(), "", "", userMapping, createTestConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mw.convertRenderedHTML(tt.input, issue, attachmentMap); got != tt.expected {
+				t.Errorf("convertRenderedHTML()\n実際: %q\n期待: %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestWikiHasUnconvertedMarkup はWiki記法の変換で残るマクロの判定をテスト
+func TestWikiHasUnconvertedMarkup(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{"*太字* と {color:red}赤{color}", false},
+		{"{code}\n{unknown}\n{code}", false},
+		{"{jira-chart:type=pie}", true},
+		{"{toc}\nh1. 見出し", true},
+		{"{panel}終了タグなし", true},
+		{"* {unknown:param=1}", true},
+		{"JSONの例 {\"a\": 1}", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			if got := wikiHasUnconvertedMarkup(tt.input); got != tt.expected {
+				t.Errorf("wikiHasUnconvertedMarkup(%q) = %v, want %v", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestGenerateMarkdown_RenderedSource はdisplay.sourceによる説明・コメントの変換元の切り替えをテスト
+func TestGenerateMarkdown_RenderedSource(t *testing.T) {
+	newData := func(description string) *IssueData {
+		return &IssueData{
+			Issue: &cloud.Issue{
+				Key: "TEST-1",
+				Fields: &cloud.IssueFields{
+					Description: description,
+				},
+				RenderedFields: &cloud.IssueRenderedFields{
+					Description: "<p>HTMLの<b>説明</b></p>",
+				},
+			},
+			Comments: []IssueComment{
+				{Comment: cloud.Comment{ID: "1", Body: "Wikiの*コメント*"}, RenderedBody: "<p>HTMLの<b>コメント</b></p>"},
+				{Comment: cloud.Comment{ID: "2", Body: "HTMLなし"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name        string
+		source      string
+		description string
+		expected    []string
+		notExpected []string
+	}{
+		{
+			name:        "wiki: Wiki記法から変換",
+			source:      "wiki",
+			description: "Wikiの*説明* {toc}",
+			expected:    []string{"Wikiの**説明** {toc}", "Wikiの**コメント**"},
+			notExpected: []string{"HTMLの"},
+		},
+		{
+			name:        "rendered: HTMLから変換（HTMLがないコメントはWiki記法から変換）",
+			source:      "rendered",
+			description: "Wikiの*説明*",
+			expected:    []string{"HTMLの**説明**", "HTMLの**コメント**", "HTMLなし"},
+			notExpected: []string{"Wikiの"},
+		},
+		{
+			name:        "auto: 変換できない記法がない課題はWiki記法から変換",
+			source:      "auto",
+			description: "Wikiの*説明*",
+			expected:    []string{"Wikiの**説明**", "Wikiの**コメント**"},
+			notExpected: []string{"HTMLの"},
+		},
+		{
+			name:        "auto: 変換できない記法がある課題はHTMLから変換",
+			source:      "auto",
+			description: "Wikiの*説明* {toc}",
+			expected:    []string{"HTMLの**説明**", "HTMLの**コメント**"},
+			notExpected: []string{"Wikiの", "{toc}"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createTestConfig()
+			config.Display.Source = tt.source
+			mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+			data := newData(tt.description)
+
+			useRendered := mw.useRenderedHTML(data)
+			var sb strings.Builder
+			mw.generateDescription(&sb, data, map[string]string{}, useRendered)
+			mw.generateComments(&sb, data, map[string]string{}, useRendered)
+			result := sb.String()
+
+			for _, expected := range tt.expected {
+				if !strings.Contains(result, expected) {
+					t.Errorf("期待される文字列が含まれていません\n期待: %q\n実際の出力:\n%s", expected, result)
+				}
+			}
+			for _, notExpected := range tt.notExpected {
+				if strings.Contains(result, notExpected) {
+					t.Errorf("出力されるべきでない文字列が含まれています\n含まれてはいけない: %q\n実際の出力:\n%s", notExpected, result)
+				}
+			}
+		})
+	}
+}
+
+// TestGenerateMarkdown_RenderedSourceAutoOnce はdisplay.sourceが"auto"の判定を課題ごとに1回だけ行うことをテスト
+func TestGenerateMarkdown_RenderedSourceAutoOnce(t *testing.T) {
+	var logs bytes.Buffer
+	defaultLogger := slog.Default()
+	slog.SetDefault(slog.New(slog.NewTextHandler(&logs, nil)))
+	defer slog.SetDefault(defaultLogger)
+
+	config := createTestConfig()
+	config.Display.Source = "auto"
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+	data := &IssueData{
+		Issue: &cloud.Issue{
+			Key: "TEST-1",
+			Fields: &cloud.IssueFields{
+				Type:        cloud.IssueType{Name: "タスク"},
+				Status:      &cloud.Status{Name: "未着手"},
+				Summary:     "テスト",
+				Description: "Wikiの*説明* {toc}",
+				Project:     cloud.Project{Key: "TEST", Name: "テストプロジェクト"},
+			},
+			RenderedFields: &cloud.IssueRenderedFields{
+				Description: "<p>HTMLの<b>説明</b></p>",
+			},
+		},
+		Comments: []IssueComment{
+			{Comment: cloud.Comment{ID: "1", Body: "Wikiの*コメント*", Author: &cloud.User{DisplayName: "佐藤"}}, RenderedBody: "<p>HTMLの<b>コメント</b></p>"},
+		},
+	}
+
+	got, err := mw.generateMarkdown(data, nil, FieldNameCache{})
+	if err != nil {
+		t.Fatalf("generateMarkdown() error = %v", err)
+	}
+	for _, expected := range []string{"HTMLの**説明**", "HTMLの**コメント**"} {
+		if !strings.Contains(got, expected) {
+			t.Errorf("期待される文字列が含まれていません\n期待: %q\n実際の出力:\n%s", expected, got)
+		}
+	}
+	if count := strings.Count(logs.String(), "renderedFieldsのHTMLから変換します"); count != 1 {
+		t.Errorf("display.sourceの判定のログが%d回出力されました（期待: 1回）\n%s", count, logs.String())
+	}
+}
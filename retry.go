@@ -0,0 +1,192 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryTransport はレート制限（429）や一時的なエラー（502/503/504）の際に
+// リクエストを再送するhttp.RoundTripper
+// Retry-After / X-RateLimit-Reset ヘッダーがあればその時間だけ待機し、
+// なければジッター付きの指数バックオフで待機する
+type RetryTransport struct {
+	// Transport は実際にリクエストを送信するトランスポート（nilの場合はhttp.DefaultTransport）
+	Transport http.RoundTripper
+
+	MaxRetries     int           // 最大リトライ回数（0以下の場合はリトライしない）
+	InitialBackoff time.Duration // 初回のバックオフ時間
+	MaxBackoff     time.Duration // バックオフ時間の上限（Retry-Afterの指定には適用しない）
+
+	// now は現在時刻を返す（テスト用に差し替え可能）
+	now func() time.Time
+}
+
+// NewRetryTransport は設定からRetryTransportを作成する
+func NewRetryTransport(base http.RoundTripper, config RetryConfig) *RetryTransport {
+	return &RetryTransport{
+		Transport:      base,
+		MaxRetries:     config.MaxRetries,
+		InitialBackoff: time.Duration(config.InitialBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(config.MaxBackoffMs) * time.Millisecond,
+		now:            time.Now,
+	}
+}
+
+// RoundTrip はリクエストを送信し、リトライ対象のレスポンスであれば待機して再送する
+// リトライ回数を使い切った場合は最後のレスポンスをそのまま返す
+func (t *RetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		// 2回目以降はリクエストボディを巻き戻す
+		if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+
+		resp, err := t.transport().RoundTrip(req)
+
+		// リトライ可否の判定
+		if attempt >= t.MaxRetries || !t.shouldRetry(req, resp, err) {
+			return resp, err
+		}
+
+		wait := t.retryDelay(resp, attempt)
+		if resp != nil {
+			slog.Warn("一時的なエラーのためリトライします",
+				"method", req.Method,
+				"url", req.URL.String(),
+				"status", resp.StatusCode,
+				"attempt", attempt+1,
+				"maxRetries", t.MaxRetries,
+				"wait", wait.String(),
+				"rateLimitRemaining", resp.Header.Get("X-RateLimit-Remaining"))
+			// 次のリクエストのためにボディを読み捨ててコネクションを再利用可能にする
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		} else {
+			slog.Warn("通信エラーのためリトライします",
+				"method", req.Method,
+				"url", req.URL.String(),
+				"attempt", attempt+1,
+				"maxRetries", t.MaxRetries,
+				"wait", wait.String(),
+				"error", err)
+		}
+
+		// 待機（コンテキストがキャンセルされた場合は中断）
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// shouldRetry はレスポンスまたはエラーがリトライ対象かどうかを判定する
+func (t *RetryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	// ボディを巻き戻せないリクエストはリトライしない
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+
+	if err != nil {
+		// コンテキストのキャンセル・タイムアウトはリトライしない
+		return req.Context().Err() == nil
+	}
+
+	return isRetryableStatus(resp.StatusCode)
+}
+
+// isRetryableStatus はリトライ対象のステータスコードかどうかを判定する
+func isRetryableStatus(statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay は次のリトライまでの待機時間を決定する
+// 優先順位: Retry-After → X-RateLimit-Reset → ジッター付き指数バックオフ
+func (t *RetryTransport) retryDelay(resp *http.Response, attempt int) time.Duration {
+	if resp != nil {
+		now := t.now()
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), now); ok {
+			return d
+		}
+		if d, ok := parseRateLimitReset(resp.Header.Get("X-RateLimit-Reset"), now); ok {
+			return d
+		}
+	}
+	return t.backoff(attempt)
+}
+
+// backoff はジッター付きの指数バックオフ時間を返す（上限の半分〜上限の範囲でランダム）
+func (t *RetryTransport) backoff(attempt int) time.Duration {
+	if t.InitialBackoff <= 0 {
+		return 0
+	}
+
+	d := t.InitialBackoff
+	for i := 0; i < attempt; i++ {
+		d *= 2
+		if t.MaxBackoff > 0 && d >= t.MaxBackoff {
+			d = t.MaxBackoff
+			break
+		}
+	}
+	if t.MaxBackoff > 0 && d > t.MaxBackoff {
+		d = t.MaxBackoff
+	}
+
+	half := d / 2
+	return half + time.Duration(rand.Int64N(int64(half)+1))
+}
+
+func (t *RetryTransport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
+
+// parseRetryAfter はRetry-Afterヘッダー（秒数またはHTTP日付）を待機時間に変換する
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// parseRateLimitReset はX-RateLimit-Resetヘッダー（ISO 8601形式の日時）を待機時間に変換する
+func parseRateLimitReset(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, false
+	}
+	return max(t.Sub(now), 0), true
+}
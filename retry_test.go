@@ -0,0 +1,253 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestRetryTransport はテスト用に待機時間を短くしたRetryTransportを作成する
+func newTestRetryTransport(maxRetries int) *RetryTransport {
+	return NewRetryTransport(http.DefaultTransport, RetryConfig{
+		MaxRetries:       maxRetries,
+		InitialBackoffMs: 1,
+		MaxBackoffMs:     5,
+	})
+}
+
+// TestRetryTransport はリトライ対象のステータスコードで再送されることのテスト
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		maxRetries   int
+		wantStatus   int
+		wantRequests int
+	}{
+		{
+			name:         "正常系: 429の後に成功",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			maxRetries:   3,
+			wantStatus:   http.StatusOK,
+			wantRequests: 2,
+		},
+		{
+			name:         "正常系: 503が続いた後に成功",
+			statuses:     []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			maxRetries:   3,
+			wantStatus:   http.StatusOK,
+			wantRequests: 3,
+		},
+		{
+			name:         "異常系: リトライ回数を使い切った場合は最後のレスポンスを返す",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusTooManyRequests, http.StatusTooManyRequests},
+			maxRetries:   2,
+			wantStatus:   http.StatusTooManyRequests,
+			wantRequests: 3,
+		},
+		{
+			name:         "異常系: 404はリトライしない",
+			statuses:     []int{http.StatusNotFound, http.StatusOK},
+			maxRetries:   3,
+			wantStatus:   http.StatusNotFound,
+			wantRequests: 1,
+		},
+		{
+			name:         "異常系: リトライ無効（-1）",
+			statuses:     []int{http.StatusTooManyRequests, http.StatusOK},
+			maxRetries:   -1,
+			wantStatus:   http.StatusTooManyRequests,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestCount := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[min(requestCount, len(tt.statuses)-1)]
+				requestCount++
+				if status == http.StatusTooManyRequests {
+					w.Header().Set("Retry-After", "0")
+				}
+				w.WriteHeader(status)
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: newTestRetryTransport(tt.maxRetries)}
+			resp, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("予期しないエラー: %v", err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("StatusCode = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if requestCount != tt.wantRequests {
+				t.Errorf("リクエスト回数 = %d, want %d", requestCount, tt.wantRequests)
+			}
+		})
+	}
+}
+
+// TestRetryTransport_RequestBody はリトライ時にリクエストボディが再送されることのテスト
+func TestRetryTransport_RequestBody(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: newTestRetryTransport(3)}
+	resp, err := client.Post(server.URL, "application/json", bytes.NewReader([]byte(`{"query":"test"}`)))
+	if err != nil {
+		t.Fatalf("予期しないエラー: %v", err)
+	}
+	resp.Body.Close()
+
+	if len(bodies) != 2 {
+		t.Fatalf("リクエスト回数 = %d, want 2", len(bodies))
+	}
+	for i, body := range bodies {
+		if body != `{"query":"test"}` {
+			t.Errorf("bodies[%d] = %q, want %q", i, body, `{"query":"test"}`)
+		}
+	}
+}
+
+// TestRetryTransport_ContextCancel は待機中にコンテキストがキャンセルされた場合に中断することのテスト
+func TestRetryTransport_ContextCancel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, "GET", server.URL, nil)
+	client := &http.Client{Transport: newTestRetryTransport(3)}
+
+	start := time.Now()
+	_, err := client.Do(req)
+	if err == nil {
+		t.Fatal("エラーが期待されましたが、nilが返されました")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("キャンセル後も待機が続きました: %v", elapsed)
+	}
+}
+
+// TestRetryTransport_RetryDelay はRetry-After / X-RateLimit-Resetヘッダーによる待機時間のテスト
+func TestRetryTransport_RetryDelay(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	transport := newTestRetryTransport(3)
+	transport.InitialBackoff = time.Second
+	transport.MaxBackoff = 8 * time.Second
+	transport.now = func() time.Time { return now }
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		attempt int
+		wantMin time.Duration
+		wantMax time.Duration
+	}{
+		{
+			name:    "Retry-After（秒数）",
+			headers: map[string]string{"Retry-After": "30"},
+			wantMin: 30 * time.Second,
+			wantMax: 30 * time.Second,
+		},
+		{
+			name:    "Retry-After（HTTP日付）",
+			headers: map[string]string{"Retry-After": now.Add(10 * time.Second).Format(http.TimeFormat)},
+			wantMin: 10 * time.Second,
+			wantMax: 10 * time.Second,
+		},
+		{
+			name:    "X-RateLimit-Reset（ISO 8601）",
+			headers: map[string]string{"X-RateLimit-Reset": now.Add(5 * time.Second).Format(time.RFC3339)},
+			wantMin: 5 * time.Second,
+			wantMax: 5 * time.Second,
+		},
+		{
+			name:    "ヘッダーなし（初回）",
+			headers: map[string]string{},
+			attempt: 0,
+			wantMin: 500 * time.Millisecond,
+			wantMax: time.Second,
+		},
+		{
+			name:    "ヘッダーなし（3回目）",
+			headers: map[string]string{},
+			attempt: 2,
+			wantMin: 2 * time.Second,
+			wantMax: 4 * time.Second,
+		},
+		{
+			name:    "ヘッダーなし（上限到達）",
+			headers: map[string]string{},
+			attempt: 10,
+			wantMin: 4 * time.Second,
+			wantMax: 8 * time.Second,
+		},
+		{
+			name:    "不正なRetry-Afterはバックオフにフォールバック",
+			headers: map[string]string{"Retry-After": "invalid"},
+			attempt: 0,
+			wantMin: 500 * time.Millisecond,
+			wantMax: time.Second,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: http.Header{}}
+			for k, v := range tt.headers {
+				resp.Header.Set(k, v)
+			}
+
+			got := transport.retryDelay(resp, tt.attempt)
+			if got < tt.wantMin || got > tt.wantMax {
+				t.Errorf("retryDelay() = %v, want %v〜%v", got, tt.wantMin, tt.wantMax)
+			}
+		})
+	}
+}
+
+// TestIsRetryableStatus はリトライ対象ステータスコードの判定テスト
+func TestIsRetryableStatus(t *testing.T) {
+	tests := []struct {
+		statusCode int
+		want       bool
+	}{
+		{http.StatusOK, false},
+		{http.StatusBadRequest, false},
+		{http.StatusUnauthorized, false},
+		{http.StatusNotFound, false},
+		{http.StatusTooManyRequests, true},
+		{http.StatusInternalServerError, false},
+		{http.StatusBadGateway, true},
+		{http.StatusServiceUnavailable, true},
+		{http.StatusGatewayTimeout, true},
+	}
+
+	for _, tt := range tests {
+		if got := isRetryableStatus(tt.statusCode); got != tt.want {
+			t.Errorf("isRetryableStatus(%d) = %v, want %v", tt.statusCode, got, tt.want)
+		}
+	}
+}
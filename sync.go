@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// 差分同期（syncコマンド）
+//
+// JQL（またはプロジェクト）ごとに前回の同期が成功した時刻を状態ファイルに記録し、
+// 次回は `updated >= -<経過分数>m` の条件で更新された課題だけを再出力する。
+// 親課題の子課題一覧・子課題の親課題情報も変わるため、更新された課題の親課題と子課題もあわせて再出力する。
+// 更新日時の条件はJiraのユーザーのタイムゾーンに依存しないよう、相対時間（分）で指定する。
+
+// syncOverlap は前回の同期時刻から遡って再取得する余裕時間
+// （前回の検索と課題の更新がほぼ同時だった場合の取りこぼしを防ぐ）
+const syncOverlap = 5 * time.Minute
+
+// syncParentBatchSize は子課題を検索する際に1回のJQLにまとめる親課題の数
+const syncParentBatchSize = 50
+
+// syncPageSize は差分同期の検索で1回のリクエストで取得する件数
+const syncPageSize = 100
+
+// orderByPattern はJQLのORDER BY句を検出する
+var orderByPattern = regexp.MustCompile(`(?i)\s+order\s+by\s+`)
+
+// SyncState は差分同期の状態（JQLごとの前回の同期時刻）
+type SyncState struct {
+	Queries map[string]SyncQueryState `json:"queries"`
+}
+
+// SyncQueryState はJQL1件分の同期状態
+type SyncQueryState struct {
+	LastRun     time.Time `json:"last_run"`    // 前回の同期を開始した時刻
+	IssueCount  int       `json:"issue_count"` // 前回の同期で出力した課題数
+	Description string    `json:"description"` // 同期対象の説明（例: project PROJ）
+}
+
+// LoadSyncState は状態ファイルを読み込む（ファイルが存在しない場合は空の状態を返す）
+func LoadSyncState(path string) (*SyncState, error) {
+	state := &SyncState{Queries: make(map[string]SyncQueryState)}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, fmt.Errorf("同期状態ファイルの読み込みに失敗しました: %w", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("同期状態ファイルのパースに失敗しました: %w", err)
+	}
+	if state.Queries == nil {
+		state.Queries = make(map[string]SyncQueryState)
+	}
+	return state, nil
+}
+
+// Save は状態ファイルを保存する
+func (s *SyncState) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("同期状態ファイルのディレクトリ作成に失敗しました: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("同期状態のJSON変換に失敗しました: %w", err)
+	}
+	if err := writeFileAtomic(path, data, 0644); err != nil {
+		return fmt.Errorf("同期状態ファイルの書き込みに失敗しました: %w", err)
+	}
+	return nil
+}
+
+// buildSyncJQL は前回の同期時刻以降に更新された課題を検索するJQLを作成する
+// lastRunがゼロ値の場合（初回）は条件を付けない。ORDER BY句は更新日時の条件の後ろに付け直す
+func buildSyncJQL(baseJQL string, lastRun time.Time, now time.Time) string {
+	condition, orderBy := baseJQL, ""
+	if loc := orderByPattern.FindStringIndex(baseJQL); loc != nil {
+		condition, orderBy = baseJQL[:loc[0]], baseJQL[loc[1]:]
+	}
+	condition = strings.TrimSpace(condition)
+
+	jql := condition
+	if !lastRun.IsZero() {
+		minutes := int(math.Ceil(now.Sub(lastRun.Add(-syncOverlap)).Minutes()))
+		updated := fmt.Sprintf(`updated >= "-%dm"`, minutes)
+		if condition == "" {
+			jql = updated
+		} else {
+			jql = fmt.Sprintf("(%s) AND %s", condition, updated)
+		}
+	}
+	if orderBy != "" {
+		jql += " ORDER BY " + strings.TrimSpace(orderBy)
+	}
+	return jql
+}
+
+// collectSyncTargets は更新された課題とその親課題・子課題のキーを、重複を除いて返す
+// 更新された課題の順に並べ、続けて親課題、子課題の順に追加する
+// withRelativesがfalseの場合（初回の全件出力）は親課題・子課題を追加しない
+func collectSyncTargets(jiraClient *JIRAClient, jql string, withRelatives bool) ([]string, int, error) {
+	changed, err := jiraClient.SearchIssueRelations(jql, syncPageSize)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	seen := make(map[string]bool)
+	var keys []string
+	add := func(key string) {
+		if key != "" && !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+
+	var changedKeys []string
+	for _, issue := range changed {
+		add(issue.Key)
+		changedKeys = append(changedKeys, issue.Key)
+	}
+	if !withRelatives {
+		return keys, len(changed), nil
+	}
+
+	// 親課題（子課題一覧の要約・ステータスが変わるため）
+	for _, issue := range changed {
+		if issue.Fields != nil && issue.Fields.Parent != nil {
+			add(issue.Fields.Parent.Key)
+		}
+	}
+
+	// 子課題（親課題の情報が変わるため）
+	for start := 0; start < len(changedKeys); start += syncParentBatchSize {
+		end := min(start+syncParentBatchSize, len(changedKeys))
+		quoted := make([]string, 0, end-start)
+		for _, key := range changedKeys[start:end] {
+			quoted = append(quoted, fmt.Sprintf(`"%s"`, key))
+		}
+		children, err := jiraClient.SearchIssueRelations(fmt.Sprintf("parent in (%s)", strings.Join(quoted, ", ")), syncPageSize)
+		if err != nil {
+			return nil, 0, fmt.Errorf("子課題の取得に失敗しました: %w", err)
+		}
+		for _, child := range children {
+			add(child.Key)
+		}
+	}
+
+	return keys, len(changed), nil
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// TestBuildSyncJQL は差分同期のJQL生成のテスト
+func TestBuildSyncJQL(t *testing.T) {
+	now := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	lastRun := now.Add(-2 * time.Hour)
+
+	tests := []struct {
+		name    string
+		baseJQL string
+		lastRun time.Time
+		want    string
+	}{
+		{
+			name:    "初回は条件を付けない",
+			baseJQL: `project = "PROJ" ORDER BY key ASC`,
+			want:    `project = "PROJ" ORDER BY key ASC`,
+		},
+		{
+			name:    "ORDER BYの前に更新日時の条件を付ける（余裕時間5分を含む）",
+			baseJQL: `project = "PROJ" ORDER BY key ASC`,
+			lastRun: lastRun,
+			want:    `(project = "PROJ") AND updated >= "-125m" ORDER BY key ASC`,
+		},
+		{
+			name:    "ORDER BYなし・小文字",
+			baseJQL: `project = PROJ OR labels = x order by created`,
+			lastRun: lastRun,
+			want:    `(project = PROJ OR labels = x) AND updated >= "-125m" ORDER BY created`,
+		},
+		{
+			name:    "ORDER BYなし",
+			baseJQL: `assignee = currentUser()`,
+			lastRun: now.Add(-30 * time.Second),
+			want:    `(assignee = currentUser()) AND updated >= "-6m"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildSyncJQL(tt.baseJQL, tt.lastRun, now); got != tt.want {
+				t.Errorf("buildSyncJQL() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestSyncState は同期状態ファイルの保存と読み込みのテスト
+func TestSyncState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "output", "sync_state.json")
+
+	state, err := LoadSyncState(path)
+	if err != nil {
+		t.Fatalf("LoadSyncState() error = %v", err)
+	}
+	if len(state.Queries) != 0 {
+		t.Errorf("初回の状態が空ではありません: %+v", state)
+	}
+
+	lastRun := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+	state.Queries["project = PROJ"] = SyncQueryState{LastRun: lastRun, IssueCount: 3, Description: "project PROJ"}
+	if err := state.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	loaded, err := LoadSyncState(path)
+	if err != nil {
+		t.Fatalf("LoadSyncState() error = %v", err)
+	}
+	got := loaded.Queries["project = PROJ"]
+	if !got.LastRun.Equal(lastRun) || got.IssueCount != 3 {
+		t.Errorf("読み込んだ状態が期待と異なります: %+v", got)
+	}
+}
+
+// TestCollectSyncTargets は更新された課題に親課題・子課題を加えた出力対象のテスト
+func TestCollectSyncTargets(t *testing.T) {
+	issue := func(key, parent string) cloud.Issue {
+		i := cloud.Issue{Key: key, Fields: &cloud.IssueFields{Project: cloud.Project{Key: "TEST"}}}
+		if parent != "" {
+			i.Fields.Parent = &cloud.Parent{Key: parent}
+		}
+		return i
+	}
+
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		jql := r.URL.Query().Get("jql")
+		queries = append(queries, jql)
+		resp := JQLSearchResponse{IsLast: true}
+		switch {
+		case strings.HasPrefix(jql, "parent in "):
+			// TEST-1の子課題はTEST-2とTEST-4
+			if strings.Contains(jql, `"TEST-1"`) {
+				resp.Issues = []cloud.Issue{issue("TEST-2", "TEST-1"), issue("TEST-4", "TEST-1")}
+			}
+		default:
+			// 更新された課題: エピックTEST-1と、エピックTEST-9の子課題TEST-3
+			resp.Issues = []cloud.Issue{issue("TEST-1", ""), issue("TEST-3", "TEST-9")}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	jiraClient := &JIRAClient{
+		ctx:        context.Background(),
+		httpClient: server.Client(),
+		baseURL:    server.URL,
+		email:      "test@example.com",
+		apiToken:   "test-token",
+	}
+
+	keys, changed, err := collectSyncTargets(jiraClient, `project = TEST AND updated >= "-10m"`, true)
+	if err != nil {
+		t.Fatalf("collectSyncTargets() error = %v", err)
+	}
+	if changed != 2 {
+		t.Errorf("更新された課題数 = %d, want 2", changed)
+	}
+	want := []string{"TEST-1", "TEST-3", "TEST-9", "TEST-2", "TEST-4"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("出力対象 = %v, want %v", keys, want)
+	}
+	if len(queries) != 2 || queries[1] != `parent in ("TEST-1", "TEST-3")` {
+		t.Errorf("検索したJQL = %v", queries)
+	}
+
+	// 初回（全件出力）は親子課題を追加しない
+	queries = nil
+	keys, _, err = collectSyncTargets(jiraClient, "project = TEST", false)
+	if err != nil {
+		t.Fatalf("collectSyncTargets() error = %v", err)
+	}
+	if !reflect.DeepEqual(keys, []string{"TEST-1", "TEST-3"}) || len(queries) != 1 {
+		t.Errorf("初回の出力対象 = %v（検索 %d 回）", keys, len(queries))
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	_ "embed"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// defaultIssueTemplateText は課題ページのデフォルトテンプレート
+//
+//go:embed templates/issue.md.tmpl
+var defaultIssueTemplateText string
+
+// defaultIssueTemplate はパース済みのデフォルトテンプレート（既定の文言）
+var defaultIssueTemplate = parseDefaultIssueTemplate(nil)
+
+// issueTemplateFuncs はテンプレートで使用できる関数（formatFieldはmessagesの文言、nilの場合は既定の文言を使用する）
+func issueTemplateFuncs(messages Messages) template.FuncMap {
+	formatField := func(value interface{}) string {
+		return formatCustomFieldValue(value, messages)
+	}
+	return template.FuncMap{
+		"issueTypeIcon": getIssueTypeIcon,  // 課題タイプの組み込みのアイコン（[display.icons] は .IssueTypeIcon で参照する）
+		"tomlString":    escapeTOMLString,  // TOMLの文字列（ダブルクォートの内側）のエスケープ
+		"formatField":   formatField,       // カスタムフィールドの値の文字列表現
+		"join":          strings.Join,      // 文字列のリストの連結
+		"trim":          strings.TrimSpace, // 前後の空白の除去
+	}
+}
+
+// newIssueTemplate はテンプレート関数を登録した課題ページのテンプレートを作成する
+func newIssueTemplate(name string, messages Messages) *template.Template {
+	return template.New(name).Funcs(issueTemplateFuncs(messages))
+}
+
+// parseDefaultIssueTemplate はmessagesの文言でテンプレート関数を登録したデフォルトテンプレートを返す
+func parseDefaultIssueTemplate(messages Messages) *template.Template {
+	return template.Must(newIssueTemplate("issue.md.tmpl", messages).Parse(defaultIssueTemplateText))
+}
+
+// loadIssueTemplate は課題ページのテンプレートファイルを読み込む（テンプレート関数はmessagesの文言を使用する）
+func loadIssueTemplate(path string, messages Messages) (*template.Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("テンプレートファイルの読み込みに失敗しました: %w", err)
+	}
+	tmpl, err := newIssueTemplate(filepath.Base(path), messages).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("テンプレートの解析に失敗しました: %w", err)
+	}
+	return tmpl, nil
+}
+
+// issueTemplateFromConfig は設定（output.template）のテンプレートを返す（未設定の場合はデフォルトテンプレート）
+// テンプレート関数（formatField）はmessages（display.language）の文言を使用する
+// 設定の読み込み時に検証済みのため、読み込みに失敗した場合は警告を出してデフォルトテンプレートを使用する
+func issueTemplateFromConfig(config *Config, messages Messages) *template.Template {
+	if config == nil || config.Output.Template == "" {
+		return parseDefaultIssueTemplate(messages)
+	}
+	tmpl, err := loadIssueTemplate(config.Output.Template, messages)
+	if err != nil {
+		slog.Warn("テンプレートを読み込めないためデフォルトテンプレートを使用します", "template", config.Output.Template, "error", err)
+		fmt.Printf("警告: テンプレート %s を読み込めないためデフォルトテンプレートを使用します: %v\n", config.Output.Template, err)
+		return parseDefaultIssueTemplate(messages)
+	}
+	return tmpl
+}
+
+// issueTemplate は課題ページの出力に使用するテンプレートを返す
+func (mw *MarkdownWriter) issueTemplate() *template.Template {
+	if mw.template == nil {
+		return defaultIssueTemplate
+	}
+	return mw.template
+}
+
+// IssueTemplateData は課題ページのテンプレートに渡すデータ
+type IssueTemplateData struct {
+	Issue        *cloud.Issue           // 課題（Fields・Changelog・RenderedFieldsを含む）
+	DevStatus    *DevStatusDetail       // 開発情報（development.enabled = true の場合のみ）
+	Parent       *ParentIssueInfo       // 親課題（無い場合はnil）
+	Children     []ChildIssueInfo       // 子課題
+	RemoteLinks  []cloud.RemoteLink     // リモートリンク（Confluenceページ等）
+	Comments     []IssueComment         // コメント（公開範囲が制限されたものを含む）
+	Worklogs     []cloud.WorklogRecord  // 作業ログ
+	Sprints      []SprintInfo           // 課題が所属したスプリントの履歴
+	Attachments  []string               // 保存した添付ファイルのファイル名
+	Fields       FieldNameCache         // フィールドIDとフィールド名の対応
+	CustomFields map[string]interface{} // カスタムフィールドの値（フィールドID → 値）
+	Sections     *IssueSections         // 既定の形式で変換済みの各セクションの本文
+	mw           *MarkdownWriter
+	fieldSchemas FieldSchemaCache
+}
+
+// IssueSections はテンプレートから参照するセクションの本文（見出しを除くMarkdown）を生成する
+// 本文は末尾が改行で終わり、セクションに出力する内容が無い場合は空文字列になる
+type IssueSections struct {
+	mw              *MarkdownWriter
+	data            *IssueData
+	attachmentFiles []string
+	fieldNameCache  FieldNameCache
+	fieldSchemas    FieldSchemaCache
+	attachmentMap   map[string]string
+	useRendered     bool // 説明・コメントをrenderedFieldsのHTMLから変換する（display.source）
+}
+
+// newIssueTemplateData は課題データからテンプレートに渡すデータを作成する
+func (mw *MarkdownWriter) newIssueTemplateData(data *IssueData, attachmentFiles []string, fieldNameCache FieldNameCache) *IssueTemplateData {
+	issue := data.Issue
+	comments := data.Comments
+	if comments == nil {
+		comments = issueComments(issue)
+	}
+	fieldSchemas := BuildFieldSchemaCache(data.Fields)
+	return &IssueTemplateData{
+		Issue:        issue,
+		DevStatus:    data.DevStatus,
+		Parent:       data.ParentInfo,
+		Children:     data.ChildIssues,
+		RemoteLinks:  data.RemoteLinks,
+		Comments:     comments,
+		Worklogs:     data.Worklogs,
+		Sprints:      mw.issueSprints(issue),
+		Attachments:  attachmentFiles,
+		Fields:       fieldNameCache,
+		CustomFields: GetAllCustomFields(issue),
+		Sections: &IssueSections{
+			mw:              mw,
+			data:            data,
+			attachmentFiles: attachmentFiles,
+			fieldNameCache:  fieldNameCache,
+			fieldSchemas:    fieldSchemas,
+			// 添付ファイルのマッピングを作成（元のファイル名 → 保存されたファイル名）
+			attachmentMap: mw.buildAttachmentMap(issue, attachmentFiles),
+			// "auto" の判定は説明・コメントの全文を解析するため課題ごとに1回だけ行う
+			useRendered: mw.useRenderedHTML(data),
+		},
+		mw:           mw,
+		fieldSchemas: fieldSchemas,
+	}
+}
+
+// User はユーザーの表示名を返す（削除済みユーザーはdeletedUsersの設定で置き換える）
+func (d *IssueTemplateData) User(user *cloud.User) string {
+	return d.mw.getUser(user)
+}
+
+// FormatTime は日時を「YYYY-MM-DD hh:mm:ss」形式で返す
+func (d *IssueTemplateData) FormatTime(t cloud.Time) string {
+	return d.mw.formatTime(t)
+}
+
+// Text はメッセージIDの見出し・ラベルの文言（display.language）を返す（argsを指定した場合は文言の書式で整形する）
+func (d *IssueTemplateData) Text(id string, args ...interface{}) string {
+	return d.mw.msg(id, args...)
+}
+
+// IssueTypeIcon は課題タイプのアイコン（[display.icons.issue_types]）をMarkdownで返す
+func (d *IssueTemplateData) IssueTypeIcon(name string) string {
+	return d.mw.icon(iconKindIssueType, name, issuePageAttachments)
+}
+
+// PriorityIcon は優先度のアイコン（[display.icons.priorities]）をMarkdownで返す（アイコンが無い場合は空文字列）
+func (d *IssueTemplateData) PriorityIcon(name string) string {
+	return d.mw.icon(iconKindPriority, name, issuePageAttachments)
+}
+
+// StatusIcon はステータスのアイコン（[display.icons.statuses]）をMarkdownで返す（アイコンが無い場合は空文字列）
+func (d *IssueTemplateData) StatusIcon(name string) string {
+	return d.mw.icon(iconKindStatus, name, issuePageAttachments)
+}
+
+// FieldName はフィールドIDのフィールド名を返す
+func (d *IssueTemplateData) FieldName(fieldID string) string {
+	return d.Fields.fieldName(fieldID, d.mw.messages)
+}
+
+// CustomField はカスタムフィールドの値をフィールドの種類に応じた形式の文字列で返す（値が無い場合は空文字列）
+func (d *IssueTemplateData) CustomField(fieldID string) string {
+	value, exists := d.CustomFields[fieldID]
+	if !exists || IsCustomFieldEmpty(value) {
+		return ""
+	}
+	return d.mw.formatFieldValue(fieldID, value, d.fieldSchemas, d.Fields)
+}
+
+// render はセクションを生成する関数の出力を文字列で返す
+func (s *IssueSections) render(generate func(sb *strings.Builder)) string {
+	var sb strings.Builder
+	generate(&sb)
+	return sb.String()
+}
+
+// FrontMatter はフロントマター（output.front_matterの形式、区切りを含む）を返す
+func (s *IssueSections) FrontMatter() (string, error) {
+	var sb strings.Builder
+	if err := s.mw.generateFrontMatter(&sb, s.data, s.fieldNameCache); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// Breadcrumb はパンくずリスト（プロジェクト / 親課題 / 課題、改行なし）を返す
+func (s *IssueSections) Breadcrumb() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateBreadcrumb(sb, s.data.Issue, s.data.ParentInfo) })
+}
+
+// BasicInfo は基本情報（課題キー・ステータス・担当者・カスタムフィールド等）を返す
+func (s *IssueSections) BasicInfo() string {
+	return s.render(func(sb *strings.Builder) {
+		s.mw.generateBasicInfo(sb, s.data.Issue, s.fieldNameCache, s.fieldSchemas, s.data.DevStatus)
+	})
+}
+
+// DevelopmentInfo は開発情報（ブランチ・プルリクエスト）を返す
+func (s *IssueSections) DevelopmentInfo() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateDevelopmentInfo(sb, s.data.DevStatus) })
+}
+
+// Description は説明を返す
+func (s *IssueSections) Description() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateDescription(sb, s.data, s.attachmentMap, s.useRendered) })
+}
+
+// ChildIssues は子作業項目を返す
+func (s *IssueSections) ChildIssues() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateChildIssues(sb, s.data.ChildIssues) })
+}
+
+// ConfluenceLinks はConfluenceコンテンツへのリンクを返す
+func (s *IssueSections) ConfluenceLinks() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateConfluenceLinks(sb, s.data.RemoteLinks) })
+}
+
+// Sprints はスプリントの履歴を返す
+func (s *IssueSections) Sprints() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateSprints(sb, s.mw.issueSprints(s.data.Issue)) })
+}
+
+// Comments はコメントを返す
+func (s *IssueSections) Comments() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateComments(sb, s.data, s.attachmentMap, s.useRendered) })
+}
+
+// Subtasks はサブタスクを返す
+func (s *IssueSections) Subtasks() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateSubtasks(sb, s.data.Issue) })
+}
+
+// IssueLinks は関連リンクを返す
+func (s *IssueSections) IssueLinks() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateIssueLinks(sb, s.data.Issue) })
+}
+
+// Attachments は添付ファイルへのリンクを返す
+func (s *IssueSections) Attachments() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateAttachments(sb, s.attachmentFiles) })
+}
+
+// Worklogs は作業ログと作業者別の合計を返す
+func (s *IssueSections) Worklogs() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateWorklogs(sb, s.data.Worklogs) })
+}
+
+// ChangeHistory は変更履歴を返す
+func (s *IssueSections) ChangeHistory() string {
+	return s.render(func(sb *strings.Builder) { s.mw.generateChangeHistory(sb, s.data.Issue) })
+}
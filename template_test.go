@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/andygrunwald/go-jira/v2/cloud"
+)
+
+// writeTestTemplate はテスト用のテンプレートファイルを作成する
+func writeTestTemplate(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "issue.md.tmpl")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("テンプレートファイルの作成に失敗しました: %v", err)
+	}
+	return path
+}
+
+// TestGenerateMarkdown_CustomTemplate はoutput.templateで指定したテンプレートで課題ページを出力することを確認する
+func TestGenerateMarkdown_CustomTemplate(t *testing.T) {
+	tmpl := `---
+title: "{{tomlString .Issue.Fields.Summary}}"
+---
+{{issueTypeIcon .Issue.Fields.Type.Name}} {{.Issue.Key}}{{with .Parent}} (親: {{.Key}}){{end}}
+
+## Description
+
+{{.Sections.Description}}
+## Details
+
+- {{.FieldName "customfield_10030"}}: {{.CustomField "customfield_10030"}}
+- Assignee: {{.User .Issue.Fields.Assignee}}
+- Created: {{.FormatTime .Issue.Fields.Created}}
+{{range .Children}}- child {{.Key}} {{.Status}}
+{{end}}{{with .Sections.Comments}}
+## Comments
+
+{{.}}{{end}}`
+
+	config := createTestConfig()
+	config.Output.Template = writeTestTemplate(t, tmpl)
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+
+	issue := &cloud.Issue{
+		Key: "TEST-1",
+		Fields: &cloud.IssueFields{
+			Summary:     `"引用符"を含む課題`,
+			Description: "*太字*",
+			Type:        cloud.IssueType{Name: "Bug"},
+			Status:      &cloud.Status{Name: "未着手"},
+			Project:     cloud.Project{Key: "TEST"},
+			Assignee:    &cloud.User{DisplayName: "担当者"},
+			Unknowns:    map[string]interface{}{"customfield_10030": map[string]interface{}{"value": "高"}},
+		},
+	}
+	data := &IssueData{
+		Issue:       issue,
+		ParentInfo:  &ParentIssueInfo{Key: "TEST-0", Type: "Epic"},
+		ChildIssues: []ChildIssueInfo{{Key: "TEST-2", Status: "完了"}},
+	}
+
+	got, err := mw.generateMarkdown(data, nil, FieldNameCache{"customfield_10030": "重要度"})
+	if err != nil {
+		t.Fatalf("generateMarkdown() error = %v", err)
+	}
+	want := `---
+title: "\"引用符\"を含む課題"
+---
+🐞 TEST-1 (親: TEST-0)
+
+## Description
+
+**太字**
+
+## Details
+
+- 重要度: 高
+- Assignee: 担当者
+- Created: 0001-01-01 00:00:00
+- child TEST-2 完了
+`
+	if got != want {
+		t.Errorf("generateMarkdown()の出力が期待と異なります\n実際:\n%s\n期待:\n%s", got, want)
+	}
+}
+
+// TestGenerateMarkdown_TemplateFormatField はテンプレート関数formatFieldがdisplay.languageの文言を使用することを確認する
+func TestGenerateMarkdown_TemplateFormatField(t *testing.T) {
+	config := createTestConfig()
+	config.Display.Language = "en"
+	config.Output.Template = writeTestTemplate(t, `{{formatField (index .CustomFields "customfield_10050")}} {{formatField (index .CustomFields "customfield_10051")}}`)
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, config)
+
+	issue := &cloud.Issue{
+		Key: "TEST-1",
+		Fields: &cloud.IssueFields{
+			Unknowns: map[string]interface{}{"customfield_10050": true},
+		},
+	}
+	got, err := mw.generateMarkdown(&IssueData{Issue: issue}, nil, FieldNameCache{})
+	if err != nil {
+		t.Fatalf("generateMarkdown() error = %v", err)
+	}
+	if want := "Yes None"; got != want {
+		t.Errorf("generateMarkdown() = %q, want %q", got, want)
+	}
+}
+
+// TestGenerateMarkdown_TemplateError はテンプレートの実行に失敗した場合にエラーを返すことを確認する
+func TestGenerateMarkdown_TemplateError(t *testing.T) {
+	config := createTestConfig()
+	config.Output.Template = writeTestTemplate(t, "{{.Parent.Key}}")
+	mw := NewMarkdownWriter(context.Background(), t.TempDir(), "", nil, config)
+
+	issue := &cloud.Issue{
+		Key: "TEST-1",
+		Fields: &cloud.IssueFields{
+			Type:    cloud.IssueType{Name: "Task"},
+			Status:  &cloud.Status{Name: "未着手"},
+			Project: cloud.Project{Key: "TEST"},
+		},
+	}
+	err := mw.WriteIssue(&IssueData{Issue: issue}, nil, FieldNameCache{})
+	if err == nil || !strings.Contains(err.Error(), "テンプレートの実行に失敗しました") {
+		t.Errorf("WriteIssue() error = %v, テンプレートの実行エラーを期待", err)
+	}
+}
+
+// TestLoadIssueTemplate はテンプレートファイルの読み込みと構文エラーの検出を確認する
+func TestLoadIssueTemplate(t *testing.T) {
+	if _, err := loadIssueTemplate(writeTestTemplate(t, "{{.Issue.Key}}"), nil); err != nil {
+		t.Errorf("loadIssueTemplate() error = %v", err)
+	}
+	if _, err := loadIssueTemplate(writeTestTemplate(t, "{{if .Issue}}"), nil); err == nil || !strings.Contains(err.Error(), "テンプレートの解析に失敗しました") {
+		t.Errorf("loadIssueTemplate() error = %v, 構文エラーを期待", err)
+	}
+	if _, err := loadIssueTemplate(writeTestTemplate(t, "{{unknownFunc .Issue}}"), nil); err == nil {
+		t.Error("loadIssueTemplate() 未定義の関数でエラーになりません")
+	}
+	if _, err := loadIssueTemplate(filepath.Join(t.TempDir(), "missing.tmpl"), nil); err == nil || !strings.Contains(err.Error(), "テンプレートファイルの読み込みに失敗しました") {
+		t.Errorf("loadIssueTemplate() error = %v, 読み込みエラーを期待", err)
+	}
+}
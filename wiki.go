@@ -0,0 +1,1100 @@
+package main
+
+import (
+	"fmt"
+	"html"
+	"net/url"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Jira Wiki記法 → Markdown変換
+//
+// Wiki記法のテキストをブロック（段落・見出し・リスト・テーブル・マクロ）と
+// インライン（装飾・リンク・メンション・色等）の構文木に分解してからMarkdownを出力する。
+// マクロ（{quote}、{panel}等）の中身はブロックとして再帰的に解析するため、
+// パネル内のリスト、引用内のテーブル、テーブルのセル内の装飾も変換できる。
+// コードブロック・インラインコード・画像の中は解析しない。
+
+// wikiNode はWiki記法の構文木のノード
+type wikiNode struct {
+	Kind     string            // ノードの種類（"paragraph", "heading", "strong" 等）
+	Text     string            // テキスト・コード・リンク先・アカウントID等の値
+	Level    int               // 見出し・リストの階層
+	Ordered  bool              // 番号付きリストの項目
+	Header   bool              // テーブルの見出しセル
+	Params   map[string]string // マクロのパラメータ
+	Inline   bool              // 直前のブロックと同じ行に続く（行の途中のマクロとその前後のテキスト）
+	Children []*wikiNode
+}
+
+// wikiBlockMacros はブロックとして扱うマクロ（値は中身をWiki記法として解析しない場合にtrue）
+var wikiBlockMacros = map[string]bool{
+	"code":     true,
+	"noformat": true,
+	"quote":    false,
+	"panel":    false,
+	"note":     false,
+	"info":     false,
+	"warning":  false,
+	"tip":      false,
+	"expand":   false,
+}
+
+// renderWikiMarkup はWiki記法のテキストを解析してMarkdownを出力する（行末の改行処理は行わない）
+func (mw *MarkdownWriter) renderWikiMarkup(text string) string {
+	return mw.renderWikiBlocks(parseWikiMarkup(text))
+}
+
+// parseWikiMarkup はWiki記法のテキストをブロックの構文木に解析する
+// 空行はそのまま空行のブロックとして残す（出力の行構成を元のテキストに合わせるため）
+func parseWikiMarkup(text string) []*wikiNode {
+	p := &wikiBlockParser{text: strings.ReplaceAll(text, "\r\n", "\n")}
+	return p.parse()
+}
+
+// wikiBlockParser はWiki記法のブロックを先頭から1行ずつ解析する
+type wikiBlockParser struct {
+	text      string
+	pos       int
+	blocks    []*wikiNode
+	paragraph []string
+	inline    bool // 次のブロックが直前のブロックと同じ行に続く
+}
+
+func (p *wikiBlockParser) parse() []*wikiNode {
+	for {
+		rest := p.text[p.pos:]
+		line, last := rest, true
+		if end := strings.IndexByte(rest, '\n'); end >= 0 {
+			line, last = rest[:end], false
+		}
+
+		// ブロックマクロは行の途中から始まっていてもブロックとして扱う
+		// 引用・パネル等は前後に同じ行のテキストがある場合は改行せずに続けて出力する
+		// （コードブロックはMarkdownでは行頭から始める必要があるため改行する）
+		if start, macro, end := findWikiBlockMacro(line, rest); macro != nil {
+			inline := !wikiBlockMacros[macro.Kind]
+			before := strings.TrimSpace(line[:start]) != ""
+			if before {
+				p.addLine(line[:start])
+			}
+			p.flushParagraph()
+			p.inline = inline && (p.inline || before)
+			p.addBlock(macro)
+			p.pos += end
+			// 終了タグで行が終わる場合は改行ごと読み飛ばす
+			if p.pos < len(p.text) && p.text[p.pos] == '\n' {
+				p.pos++
+				continue
+			}
+			if p.pos >= len(p.text) {
+				break
+			}
+			p.inline = inline
+			continue
+		}
+
+		if strings.HasPrefix(line, "|") {
+			p.flushParagraph()
+			p.parseTable()
+			if p.pos >= len(p.text) {
+				break
+			}
+			continue
+		}
+
+		p.addLine(line)
+		if last {
+			break
+		}
+		p.pos += len(line) + 1
+	}
+	p.flushParagraph()
+	return p.blocks
+}
+
+// addLine は1行を見出し・リスト・水平線・1行の引用（bq.）・空行・段落の行として追加する
+func (p *wikiBlockParser) addLine(line string) {
+	if strings.TrimSpace(line) == "" {
+		p.flushParagraph()
+		p.addBlock(&wikiNode{Kind: "blank"})
+		return
+	}
+	if strings.TrimSpace(line) == "----" {
+		p.flushParagraph()
+		p.addBlock(&wikiNode{Kind: "rule"})
+		return
+	}
+	if content, ok := strings.CutPrefix(line, "bq. "); ok {
+		p.flushParagraph()
+		p.addBlock(&wikiNode{Kind: "blockquote", Children: parseWikiInline(content)})
+		return
+	}
+	if level, content, ok := parseWikiHeading(line); ok {
+		p.flushParagraph()
+		p.addBlock(&wikiNode{Kind: "heading", Level: level, Children: parseWikiInline(content)})
+		return
+	}
+	if level, ordered, content, ok := parseWikiListItem(line); ok {
+		p.flushParagraph()
+		p.addBlock(&wikiNode{Kind: "listItem", Level: level, Ordered: ordered, Children: parseWikiInline(content)})
+		return
+	}
+	p.paragraph = append(p.paragraph, line)
+}
+
+// addBlock はブロックを追加する（同じ行に続くブロックは直前のブロックと改行せずに出力する）
+func (p *wikiBlockParser) addBlock(node *wikiNode) {
+	node.Inline = p.inline
+	p.inline = false
+	p.blocks = append(p.blocks, node)
+}
+
+// flushParagraph は溜めている段落の行を1つの段落にする（色指定等は段落内で複数行にまたがれる）
+func (p *wikiBlockParser) flushParagraph() {
+	if len(p.paragraph) == 0 {
+		return
+	}
+	p.addBlock(&wikiNode{Kind: "paragraph", Children: parseWikiInline(strings.Join(p.paragraph, "\n"))})
+	p.paragraph = nil
+}
+
+// parseWikiHeading は見出し（h1. 〜 h6.）を解析する
+func parseWikiHeading(line string) (int, string, bool) {
+	if len(line) < 4 || line[0] != 'h' || line[1] < '1' || line[1] > '6' || line[2] != '.' {
+		return 0, "", false
+	}
+	if r, _ := utf8.DecodeRuneInString(line[3:]); !unicode.IsSpace(r) {
+		return 0, "", false
+	}
+	content := strings.TrimLeftFunc(line[3:], unicode.IsSpace)
+	if content == "" {
+		return 0, "", false
+	}
+	return int(line[1] - '0'), content, true
+}
+
+// parseWikiListItem はリストの項目（* 項目、# 項目、**、## 等の階層）を解析する
+// 古いJIRAでは先頭にスペースが入ることがあるため、先頭の空白は許容する
+// 階層は記号の数、リストの種類は最後の記号で決める（#* は番号付きリストの中の箇条書き）
+func parseWikiListItem(line string) (int, bool, string, bool) {
+	trimmed := strings.TrimLeftFunc(line, unicode.IsSpace)
+	level := 0
+	for level < len(trimmed) && (trimmed[level] == '*' || trimmed[level] == '#') {
+		level++
+	}
+	if level == 0 || level == len(trimmed) {
+		return 0, false, "", false
+	}
+	if r, _ := utf8.DecodeRuneInString(trimmed[level:]); !unicode.IsSpace(r) {
+		return 0, false, "", false
+	}
+	content := strings.TrimLeftFunc(trimmed[level:], unicode.IsSpace)
+	if content == "" {
+		return 0, false, "", false
+	}
+	return level, trimmed[level-1] == '#', content, true
+}
+
+// findWikiBlockMacro は行の中のブロックマクロの開始タグを探し、終了タグまでを解析する
+// 開始タグの行内の位置、マクロのノード、restの先頭から終了タグの末尾までの長さを返す
+// 終了タグが見つからないマクロは通常のテキストとして扱う
+func findWikiBlockMacro(line, rest string) (int, *wikiNode, int) {
+	for start := 0; start < len(line); start++ {
+		if line[start] != '{' {
+			continue
+		}
+		name, params, tagLen, ok := parseWikiMacroTag(line[start:])
+		if !ok {
+			continue
+		}
+		raw, isMacro := wikiBlockMacros[name]
+		if !isMacro {
+			continue
+		}
+		contentStart := start + tagLen
+		closeAt := indexFold(rest[contentStart:], "{"+name+"}")
+		if closeAt < 0 {
+			continue
+		}
+		content := rest[contentStart : contentStart+closeAt]
+		node := &wikiNode{Kind: name, Params: parsePanelParams(params)}
+		if raw {
+			node.Text = content
+			if name == "code" {
+				node.Params["language"] = wikiMacroParam(params, "language")
+			}
+		} else {
+			node.Children = parseWikiMarkup(content)
+			if name == "expand" {
+				node.Params["title"] = wikiMacroParam(params, "title")
+			}
+		}
+		return start, node, contentStart + closeAt + len(name) + 2
+	}
+	return 0, nil, 0
+}
+
+// parseWikiMacroTag はマクロのタグ（{name} または {name:params}）を解析する
+// マクロ名は英字で始まり英数字と - からなる（jira-chart 等）。マクロ名は小文字にして返す
+func parseWikiMacroTag(s string) (string, string, int, bool) {
+	end := strings.IndexAny(s, "}\n")
+	if end < 0 || s[end] != '}' {
+		return "", "", 0, false
+	}
+	name, params, _ := strings.Cut(s[1:end], ":")
+	if name == "" {
+		return "", "", 0, false
+	}
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || i > 0 && (c >= '0' && c <= '9' || c == '-')) {
+			return "", "", 0, false
+		}
+	}
+	return strings.ToLower(name), params, end + 1, true
+}
+
+// wikiMacroParam はマクロの既定のパラメータ（{code}の言語、{expand}のタイトル）を取得する
+// {code:java} のように最初のパラメータが名前のみの場合、または key=value で指定された場合に値とみなす
+func wikiMacroParam(params, key string) string {
+	first, _, _ := strings.Cut(params, "|")
+	if first = strings.TrimSpace(first); first != "" && !strings.Contains(first, "=") {
+		return first
+	}
+	return parsePanelParams(params)[key]
+}
+
+// indexFold は大文字小文字を区別せずにASCIIの文字列substr（英字以外の記号で始まるタグ）を探す
+func indexFold(s, substr string) int {
+	for i := 0; i+len(substr) <= len(s); i++ {
+		next := strings.IndexByte(s[i:], substr[0])
+		if next < 0 {
+			return -1
+		}
+		i += next
+		if i+len(substr) > len(s) {
+			return -1
+		}
+		j := 0
+		for j < len(substr) && asciiLower(s[i+j]) == asciiLower(substr[j]) {
+			j++
+		}
+		if j == len(substr) {
+			return i
+		}
+	}
+	return -1
+}
+
+// asciiLower はASCIIの英大文字を小文字にする
+func asciiLower(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+	return c
+}
+
+// parseTable はテーブル（|で始まる行の連続）を解析する
+// 見出しのみの行（||見出し||）が途中にある場合は新しいテーブルとする
+func (p *wikiBlockParser) parseTable() {
+	var table *wikiNode
+	for p.pos < len(p.text) && p.text[p.pos] == '|' {
+		row, end := readWikiTableRow(p.text[p.pos:])
+		p.pos += end
+		cells := parseWikiTableCells(row)
+		if table == nil || (len(table.Children) > 0 && isWikiHeaderRow(cells)) {
+			table = &wikiNode{Kind: "table"}
+			p.addBlock(table)
+		}
+		table.Children = append(table.Children, &wikiNode{Kind: "tableRow", Children: cells})
+
+		if p.pos < len(p.text) && p.text[p.pos] == '\n' {
+			p.pos++
+			// テキストの末尾の改行は空行として残す
+			if p.pos == len(p.text) {
+				p.addBlock(&wikiNode{Kind: "blank"})
+			}
+		}
+	}
+}
+
+// readWikiTableRow はテーブルの1行を読み込み、行のテキストと末尾（改行の手前）までの長さを返す
+// 行が | で終わらない場合は、次の行が空行・| で始まる行でない限りセル内の改行として続ける
+func readWikiTableRow(s string) (string, int) {
+	end := 0
+	for {
+		lineEnd := strings.IndexByte(s[end:], '\n')
+		if lineEnd < 0 {
+			return s, len(s)
+		}
+		end += lineEnd
+		if strings.HasSuffix(strings.TrimRightFunc(s[:end], unicode.IsSpace), "|") {
+			return s[:end], end
+		}
+		next := s[end+1:]
+		if nl := strings.IndexByte(next, '\n'); nl >= 0 {
+			next = next[:nl]
+		}
+		if strings.TrimSpace(next) == "" || strings.HasPrefix(next, "|") {
+			return s[:end], end
+		}
+		end++
+	}
+}
+
+// parseWikiTableCells はテーブルの1行をセルに分割する
+// || で始まるセルは見出しセル、| で始まるセルはデータセル
+// リンク（[text|url]）・マクロ（{status:...|...}）・画像（!file|thumbnail!）の中の | では分割しない
+func parseWikiTableCells(row string) []*wikiNode {
+	var cells []*wikiNode
+	i := 0
+	for i < len(row) {
+		pipes := 0
+		for i < len(row) && row[i] == '|' {
+			pipes++
+			i++
+		}
+		if i >= len(row) || strings.TrimSpace(row[i:]) == "" {
+			break
+		}
+		start := i
+		depth := 0
+		for i < len(row) {
+			c := row[i]
+			if c == '|' && depth == 0 {
+				break
+			}
+			switch c {
+			case '[', '{':
+				depth++
+			case ']', '}':
+				if depth > 0 {
+					depth--
+				}
+			case '!':
+				if _, n := parseWikiImage(row, i); n > 0 {
+					i += n
+					continue
+				}
+			}
+			i++
+		}
+		cells = append(cells, &wikiNode{
+			Kind:     "tableCell",
+			Header:   pipes >= 2,
+			Children: parseWikiInline(strings.TrimSpace(row[start:i])),
+		})
+	}
+	return cells
+}
+
+// isWikiHeaderRow はすべてのセルが見出しセルの行かどうかを判定する
+func isWikiHeaderRow(cells []*wikiNode) bool {
+	if len(cells) == 0 {
+		return false
+	}
+	for _, cell := range cells {
+		if !cell.Header {
+			return false
+		}
+	}
+	return true
+}
+
+// parseWikiInline はインラインのWiki記法を解析する
+func parseWikiInline(s string) []*wikiNode {
+	var nodes []*wikiNode
+	var text strings.Builder
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, &wikiNode{Kind: "text", Text: text.String()})
+			text.Reset()
+		}
+	}
+
+	for i := 0; i < len(s); {
+		var node *wikiNode
+		n := 0
+		switch s[i] {
+		case '{':
+			node, n = parseWikiBraceInline(s, i)
+		case '[':
+			node, n = parseWikiBracket(s, i)
+		case '!':
+			node, n = parseWikiImage(s, i)
+		case '*', '_', '-', '+', '^', '~':
+			node, n = parseWikiMark(s, i)
+		case '?':
+			node, n = parseWikiCitation(s, i)
+		case '(', ':', ';':
+			node, n = parseWikiEmoticon(s, i)
+		case '\\':
+			// \\ は強制改行
+			if strings.HasPrefix(s[i:], `\\`) {
+				node, n = &wikiNode{Kind: "lineBreak"}, 2
+			}
+		case 'h', 'f':
+			// URLの中の記号は装飾として扱わない
+			if n = wikiURLLength(s, i); n > 0 {
+				text.WriteString(s[i : i+n])
+				i += n
+				continue
+			}
+		}
+		if n > 0 {
+			flush()
+			if node != nil {
+				nodes = append(nodes, node)
+			}
+			i += n
+			continue
+		}
+		text.WriteByte(s[i])
+		i++
+	}
+	flush()
+	return nodes
+}
+
+// wikiMarkKinds は装飾の記号とノードの種類
+var wikiMarkKinds = map[byte]string{
+	'*': "strong",
+	'_': "emphasis",
+	'-': "strike",
+	'+': "underline",
+	'^': "superscript",
+	'~': "subscript",
+}
+
+// parseWikiMark は装飾（*太字*、_斜体_、-取り消し線-、+下線+、^上付き^、~下付き~）を解析する
+// 開始記号の直後・終了記号の直前が空白の場合、記号が連続する場合（**、__）は装飾としない
+// 取り消し線と下線は日付（2025-01-14）やハイフンを含む単語（foo-bar-baz）、式（1+2+3）を
+// 誤変換しないよう、記号の外側が英数字・記号（-/:）の場合も装飾としない
+// 装飾は行をまたがない
+func parseWikiMark(s string, i int) (*wikiNode, int) {
+	c := s[i]
+	bounded := c == '-' || c == '+'
+	if i > 0 && (s[i-1] == c || bounded && isWikiWordRune(lastRune(s[:i]))) {
+		return nil, 0
+	}
+	if i+1 >= len(s) || s[i+1] == c || unicode.IsSpace(firstRune(s[i+1:])) {
+		return nil, 0
+	}
+
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\n':
+			return nil, 0
+		case '{':
+			// インラインコードの中の記号は終了記号として扱わない
+			if code, n := parseWikiInlineCode(s, j); code != nil {
+				j += n - 1
+			}
+		case c:
+			if unicode.IsSpace(lastRune(s[:j])) || j+1 < len(s) && s[j+1] == c {
+				return nil, 0
+			}
+			if bounded && j+1 < len(s) && isWikiWordRune(firstRune(s[j+1:])) {
+				return nil, 0
+			}
+			return &wikiNode{Kind: wikiMarkKinds[c], Children: parseWikiInline(s[i+1 : j])}, j + 1 - i
+		}
+	}
+	return nil, 0
+}
+
+// isWikiWordRune は取り消し線・下線の記号の外側にあると装飾としない文字（ASCII英数字と -/:）かどうかを判定する
+// 日本語などのマルチバイト文字の場合は装飾とする
+func isWikiWordRune(r rune) bool {
+	return r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r == '-' || r == '/' || r == ':'
+}
+
+func firstRune(s string) rune {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r
+}
+
+func lastRune(s string) rune {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r
+}
+
+// parseWikiCitation は引用元（??citation??）を解析する
+// 開始記号の直後・終了記号の直前が空白の場合は引用元としない（行をまたがない）
+func parseWikiCitation(s string, i int) (*wikiNode, int) {
+	if !strings.HasPrefix(s[i:], "??") || i+2 >= len(s) || unicode.IsSpace(firstRune(s[i+2:])) || s[i+2] == '?' {
+		return nil, 0
+	}
+	end := strings.Index(s[i+2:], "??")
+	if end <= 0 || strings.IndexByte(s[i+2:i+2+end], '\n') >= 0 || unicode.IsSpace(lastRune(s[:i+2+end])) {
+		return nil, 0
+	}
+	return &wikiNode{Kind: "citation", Children: parseWikiInline(s[i+2 : i+2+end])}, end + 4
+}
+
+// wikiEmoticons はJiraの絵文字記法と対応する絵文字
+var wikiEmoticons = map[string]string{
+	":)":        "🙂",
+	":(":        "🙁",
+	":P":        "😛",
+	":p":        "😛",
+	":D":        "😀",
+	";)":        "😉",
+	"(y)":       "👍",
+	"(n)":       "👎",
+	"(i)":       "ℹ️",
+	"(/)":       "✅",
+	"(x)":       "❌",
+	"(!)":       "⚠️",
+	"(+)":       "➕",
+	"(-)":       "➖",
+	"(?)":       "❓",
+	"(on)":      "💡",
+	"(off)":     "💡",
+	"(*)":       "⭐",
+	"(*r)":      "⭐",
+	"(*g)":      "⭐",
+	"(*b)":      "⭐",
+	"(*y)":      "⭐",
+	"(flag)":    "🚩",
+	"(flagoff)": "🏳️",
+}
+
+// parseWikiEmoticon は絵文字記法（(/)、(x)、:) 等）を解析する
+// 関数呼び出し（f(x)）やURL等を誤変換しないよう、直前が英数字の場合は絵文字としない
+func parseWikiEmoticon(s string, i int) (*wikiNode, int) {
+	if i > 0 && isASCIIAlnum(s[i-1]) {
+		return nil, 0
+	}
+	var code string
+	if s[i] == '(' {
+		end := strings.IndexByte(s[i:], ')')
+		if end < 0 || end > len("(flagoff)") {
+			return nil, 0
+		}
+		code = s[i : i+end+1]
+	} else {
+		if i+2 > len(s) || i+2 < len(s) && isASCIIAlnum(s[i+2]) {
+			return nil, 0
+		}
+		code = s[i : i+2]
+	}
+	emoji, ok := wikiEmoticons[code]
+	if !ok {
+		return nil, 0
+	}
+	return &wikiNode{Kind: "emoticon", Text: emoji}, len(code)
+}
+
+// isASCIIAlnum はASCIIの英数字かどうかを判定する
+func isASCIIAlnum(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// wikiURLLength はiから始まるURL（http://, https://, ftp://）の長さを返す（URLでない場合は0）
+func wikiURLLength(s string, i int) int {
+	if i > 0 {
+		if r := lastRune(s[:i]); unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return 0
+		}
+	}
+	rest := s[i:]
+	if !strings.HasPrefix(rest, "http://") && !strings.HasPrefix(rest, "https://") && !strings.HasPrefix(rest, "ftp://") {
+		return 0
+	}
+	if end := strings.IndexFunc(rest, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune("|[]<>\"", r)
+	}); end >= 0 {
+		return end
+	}
+	return len(rest)
+}
+
+// parseWikiInlineCode はインラインコード（{{code}}）を解析する
+func parseWikiInlineCode(s string, i int) (*wikiNode, int) {
+	if !strings.HasPrefix(s[i:], "{{") {
+		return nil, 0
+	}
+	end := strings.IndexByte(s[i+2:], '}')
+	if end <= 0 || i+2+end+1 >= len(s) || s[i+2+end+1] != '}' {
+		return nil, 0
+	}
+	return &wikiNode{Kind: "inlineCode", Text: s[i+2 : i+2+end]}, end + 4
+}
+
+// parseWikiBraceInline はインラインのマクロ（{{code}}、{color}、{status}、{anchor}）を解析する
+// 対応する終了タグがない{color}タグは出力しない
+func parseWikiBraceInline(s string, i int) (*wikiNode, int) {
+	if node, n := parseWikiInlineCode(s, i); node != nil {
+		return node, n
+	}
+	name, params, tagLen, ok := parseWikiMacroTag(s[i:])
+	if !ok {
+		return nil, 0
+	}
+	rest := s[i+tagLen:]
+
+	switch name {
+	case "color":
+		closeAt := indexFold(rest, "{color}")
+		if params == "" || closeAt < 0 {
+			return nil, tagLen
+		}
+		content := rest[:closeAt]
+		n := tagLen + closeAt + len("{color}")
+		// カスタムステータスラベル: {color:#XXXXXX}*[ text ]*{color}
+		if label, ok := wikiStatusLabelText(content); ok && isHexColor(params) {
+			return &wikiNode{Kind: "statusLabel", Text: label, Params: map[string]string{"color": strings.ToLower(params)}}, n
+		}
+		return &wikiNode{Kind: "color", Params: map[string]string{"color": params}, Children: parseWikiInline(content)}, n
+
+	case "anchor":
+		if params = strings.TrimSpace(params); params == "" {
+			return nil, 0
+		}
+		return &wikiNode{Kind: "anchor", Text: params}, tagLen
+
+	case "status":
+		node := &wikiNode{Kind: "status", Params: map[string]string{}}
+		for key, value := range parsePanelParams(params) {
+			node.Params[strings.ToLower(key)] = value
+		}
+		if closeAt := indexFold(rest, "{status}"); closeAt >= 0 && !strings.Contains(rest[:closeAt], "{") {
+			node.Text = rest[:closeAt]
+			return node, tagLen + closeAt + len("{status}")
+		}
+		// {status:colour=Green|title=完了} の形式（終了タグなし）
+		if title := node.Params["title"]; title != "" {
+			node.Text = title
+			return node, tagLen
+		}
+	}
+	return nil, 0
+}
+
+// wikiStatusLabelText は *[ text ]* 形式のステータスラベルの文字列を取得する
+func wikiStatusLabelText(content string) (string, bool) {
+	if !strings.HasPrefix(content, "*[") || !strings.HasSuffix(content, "]*") || len(content) < 4 {
+		return "", false
+	}
+	label := strings.TrimSpace(content[2 : len(content)-2])
+	if label == "" || strings.ContainsAny(label, "]\n") {
+		return "", false
+	}
+	return label, true
+}
+
+// isHexColor は #XXXXXX 形式のカラーコードかどうかを判定する
+func isHexColor(s string) bool {
+	if len(s) != 7 || s[0] != '#' {
+		return false
+	}
+	for i := 1; i < len(s); i++ {
+		c := asciiLower(s[i])
+		if !(c >= '0' && c <= '9' || c >= 'a' && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseWikiBracket はメンション（[~accountid:xxx]）とリンク（[text|url]、[url]、[#anchor]、[^file]）を解析する
+// 添付ファイルへのリンク（[^file]、[text|^file]）は画像と同様にreplaceImageReferencesで置き換える
+func parseWikiBracket(s string, i int) (*wikiNode, int) {
+	end := strings.IndexAny(s[i+1:], "]\n")
+	if end < 0 || s[i+1+end] != ']' {
+		return nil, 0
+	}
+	content := s[i+1 : i+1+end]
+	n := end + 2
+
+	if accountID, ok := strings.CutPrefix(content, "~accountid:"); ok && accountID != "" {
+		return &wikiNode{Kind: "mention", Text: accountID}, n
+	}
+	text, target, ok := strings.Cut(content, "|")
+	if !ok {
+		// [url] の形式はリンク先をそのまま表示する
+		text, target = "", content
+		if !isWikiLinkTarget(target) {
+			return nil, 0
+		}
+	} else if text == "" {
+		return nil, 0
+	}
+	// [text|url|tooltip] のツールチップは出力しない
+	target, _, _ = strings.Cut(target, "|")
+	if target = strings.TrimSpace(target); target == "" {
+		return nil, 0
+	}
+
+	var children []*wikiNode
+	if text != "" {
+		children = parseWikiInline(text)
+	}
+	if file, ok := strings.CutPrefix(target, "^"); ok {
+		return &wikiNode{Kind: "attachment", Text: file, Children: children}, n
+	}
+	if children == nil {
+		children = []*wikiNode{{Kind: "text", Text: strings.TrimPrefix(strings.TrimPrefix(target, "mailto:"), "#")}}
+	}
+	return &wikiNode{Kind: "link", Text: target, Children: children}, n
+}
+
+// isWikiLinkTarget は表示テキストなしのリンク（[url]）のリンク先として扱う文字列かどうかを判定する
+func isWikiLinkTarget(target string) bool {
+	for _, prefix := range []string{"http://", "https://", "ftp://", "mailto:", "#", "^"} {
+		if strings.HasPrefix(target, prefix) && len(target) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseWikiImage は画像・添付ファイルの参照（!file.png!、!file.png|thumbnail!）を解析する
+// 参照はそのまま出力し、添付ファイルへのリンクへの置き換えはreplaceImageReferencesで行う
+func parseWikiImage(s string, i int) (*wikiNode, int) {
+	end := strings.IndexAny(s[i+1:], "!\n")
+	if end <= 0 || s[i+1+end] != '!' {
+		return nil, 0
+	}
+	name, _, _ := strings.Cut(s[i+1:i+1+end], "|")
+	dot := strings.LastIndexByte(name, '.')
+	if dot <= 0 || dot == len(name)-1 || unicode.IsSpace(firstRune(name)) {
+		return nil, 0
+	}
+	for _, r := range name[dot+1:] {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return nil, 0
+		}
+	}
+	return &wikiNode{Kind: "image", Text: s[i : i+end+2]}, end + 2
+}
+
+// renderWikiBlocks はブロックのノードをMarkdownに変換する（ブロックを改行で連結する）
+func (mw *MarkdownWriter) renderWikiBlocks(blocks []*wikiNode) string {
+	var sb strings.Builder
+	for i, block := range blocks {
+		if i > 0 && !block.Inline {
+			sb.WriteByte('\n')
+			// 連続するテーブルは空行で区切る（区切らないと1つのテーブルになる）
+			if block.Kind == "table" && blocks[i-1].Kind == "table" {
+				sb.WriteByte('\n')
+			}
+		}
+		sb.WriteString(mw.renderWikiBlock(block))
+	}
+	return sb.String()
+}
+
+// renderWikiBlock はブロックのノードをMarkdownに変換する
+func (mw *MarkdownWriter) renderWikiBlock(node *wikiNode) string {
+	switch node.Kind {
+	case "blank":
+		return ""
+	case "heading":
+		return strings.Repeat("#", node.Level) + " " + mw.renderWikiInline(node.Children)
+	case "listItem":
+		// ネストは4スペースで字下げする
+		marker := "- "
+		if node.Ordered {
+			marker = "1. "
+		}
+		return strings.Repeat("    ", node.Level-1) + marker + mw.renderWikiInline(node.Children)
+	case "table":
+		return mw.renderWikiTable(node)
+	case "rule":
+		// 直前の行が見出しにならないよう、--- ではなく *** を使う
+		return "***"
+	case "blockquote":
+		return "> " + mw.renderWikiInline(node.Children)
+	case "code":
+		code := fmt.Sprintf("```%s\n%s\n```", node.Params["language"], node.Text)
+		if title := node.Params["title"]; title != "" {
+			return fmt.Sprintf(`<div class="code-title">%s</div>`, html.EscapeString(title)) + "\n" + code
+		}
+		return code
+	case "noformat":
+		return fmt.Sprintf("```\n%s\n```", node.Text)
+	case "quote":
+		return quoteLines(mw.renderWikiBlocks(node.Children))
+	case "panel":
+		title := node.Params["title"]
+		return mw.renderWikiPanel(getPanelClass(node.Params["bgColor"]), title, node.Children)
+	case "note", "info", "warning", "tip":
+		return mw.renderWikiPanel(getAdmonitionClass(node.Kind), node.Params["title"], node.Children)
+	case "expand":
+		// ADFの展開ブロックと同じdetailsタグにする
+		body := strings.Trim(mw.renderWikiBlocks(node.Children), "\n")
+		return fmt.Sprintf("<details>\n<summary>%s</summary>\n\n%s\n\n</details>", html.EscapeString(node.Params["title"]), body)
+	default:
+		return mw.renderWikiInline(node.Children)
+	}
+}
+
+// renderWikiPanel はパネルをHTMLのdivタグに変換する
+func (mw *MarkdownWriter) renderWikiPanel(panelClass, title string, children []*wikiNode) string {
+	return formatPanel(panelClass, title, mw.renderWikiBlocks(children))
+}
+
+// formatPanel はパネルのHTMLのdivタグを出力する（renderedFieldsのHTMLからの変換と共通）
+func formatPanel(panelClass, title, body string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`<div class="panel %s">`, panelClass))
+	if title != "" {
+		sb.WriteString(fmt.Sprintf(`<div class="panel-title">%s</div>`, html.EscapeString(title)))
+	}
+	sb.WriteString(`<div class="panel-body">`)
+	sb.WriteString(body)
+	sb.WriteString(`</div></div>`)
+	return sb.String()
+}
+
+// renderWikiTable はテーブルをMarkdownのテーブルに変換する
+// 先頭行が見出しでない場合は空の見出し行を出力する（Markdownのテーブルには見出し行が必要）
+func (mw *MarkdownWriter) renderWikiTable(node *wikiNode) string {
+	var header []string
+	rows := node.Children
+	if len(rows) > 0 && isWikiHeaderRow(rows[0].Children) {
+		for _, cell := range rows[0].Children {
+			header = append(header, mw.renderWikiTableCell(cell))
+		}
+		rows = rows[1:]
+	}
+	cells := make([][]string, len(rows))
+	for i, row := range rows {
+		for _, cell := range row.Children {
+			cells[i] = append(cells[i], mw.renderWikiTableCell(cell))
+		}
+	}
+	return formatMarkdownTable(header, cells)
+}
+
+// formatMarkdownTable はMarkdownのテーブルを出力する（renderedFieldsのHTMLからの変換と共通）
+// 見出し行がない場合（headerがnil）は空の見出し行を出力する
+func formatMarkdownTable(header []string, rows [][]string) string {
+	columns := len(header)
+	for _, row := range rows {
+		columns = max(columns, len(row))
+	}
+
+	headerCells := make([]string, columns)
+	separators := make([]string, columns)
+	for i := range headerCells {
+		headerCells[i] = " "
+		if i < len(header) {
+			headerCells[i] = header[i]
+		}
+		separators[i] = "------"
+	}
+	lines := []string{"| " + strings.Join(headerCells, " | ") + " |", "| " + strings.Join(separators, " | ") + " |"}
+	for _, row := range rows {
+		lines = append(lines, "| "+strings.Join(row, " | ")+" |")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// renderWikiTableCell はテーブルのセルを変換する（セル内の改行は<br>にする）
+func (mw *MarkdownWriter) renderWikiTableCell(cell *wikiNode) string {
+	return strings.ReplaceAll(mw.renderWikiInline(cell.Children), "\n", "<br>")
+}
+
+// renderWikiInline はインラインのノードをMarkdownに変換する
+func (mw *MarkdownWriter) renderWikiInline(nodes []*wikiNode) string {
+	var sb strings.Builder
+	for _, node := range nodes {
+		switch node.Kind {
+		case "text", "image":
+			sb.WriteString(node.Text)
+		case "strong":
+			sb.WriteString("**" + mw.renderWikiInline(node.Children) + "**")
+		case "emphasis":
+			sb.WriteString("*" + mw.renderWikiInline(node.Children) + "*")
+		case "strike":
+			sb.WriteString("~~" + mw.renderWikiInline(node.Children) + "~~")
+		case "underline":
+			sb.WriteString("<u>" + mw.renderWikiInline(node.Children) + "</u>")
+		case "citation":
+			sb.WriteString("<cite>" + mw.renderWikiInline(node.Children) + "</cite>")
+		case "lineBreak":
+			sb.WriteString("\n")
+		case "emoticon":
+			sb.WriteString(node.Text)
+		case "anchor":
+			sb.WriteString(fmt.Sprintf(`<a id="%s"></a>`, html.EscapeString(node.Text)))
+		case "attachment":
+			// Wiki記法のまま出力し、replaceImageReferencesで添付ファイルへのリンクに置き換える
+			if len(node.Children) > 0 {
+				sb.WriteString("[" + mw.renderWikiInline(node.Children) + "|^" + node.Text + "]")
+			} else {
+				sb.WriteString("[^" + node.Text + "]")
+			}
+		case "superscript":
+			sb.WriteString("<sup>" + mw.renderWikiInline(node.Children) + "</sup>")
+		case "subscript":
+			sb.WriteString("<sub>" + mw.renderWikiInline(node.Children) + "</sub>")
+		case "inlineCode":
+			sb.WriteString(inlineCode(node.Text))
+		case "link":
+			target := node.Text
+			if anchor, ok := strings.CutPrefix(target, "#"); ok {
+				target = "#" + url.PathEscape(anchor)
+			}
+			sb.WriteString("[" + mw.renderWikiInline(node.Children) + "](" + target + ")")
+		case "mention":
+			// account IDからユーザー名を取得（マッピングが見つからない場合はaccount IDを表示）
+			name := node.Text
+			if userName, exists := mw.userMapping[node.Text]; exists && userName != "" {
+				name = userName
+			}
+			sb.WriteString(`<span class="mention">@` + name + `</span>`)
+		case "color":
+			sb.WriteString(fmt.Sprintf(`<span style="color:%s">%s</span>`, node.Params["color"], mw.renderWikiInline(node.Children)))
+		case "statusLabel":
+			if className, ok := statusLabelColorMap[node.Params["color"]]; ok {
+				sb.WriteString(fmt.Sprintf(`<span class="status-label %s">%s</span>`, className, node.Text))
+			} else {
+				// 未知の色はデフォルトクラス
+				sb.WriteString(fmt.Sprintf(`<span class="status-label">%s</span>`, node.Text))
+			}
+		case "status":
+			color := node.Params["colour"]
+			if color == "" {
+				color = node.Params["color"]
+			}
+			if colorClass := mapStatusColor(strings.ToLower(color)); colorClass != "" {
+				sb.WriteString(fmt.Sprintf(`<span class="status %s">%s</span>`, colorClass, node.Text))
+			} else {
+				sb.WriteString(fmt.Sprintf(`<span class="status">%s</span>`, node.Text))
+			}
+		default:
+			sb.WriteString(mw.renderWikiInline(node.Children))
+		}
+	}
+	return sb.String()
+}
+
+// wikiHasUnconvertedMarkup はWiki記法の変換でマクロ（{name}、{name:params}）がそのまま残るかを判定する
+// コードブロック・インラインコードの中は対象外（display.source = "auto" の判定に使用する）
+func wikiHasUnconvertedMarkup(text string) bool {
+	var walk func(nodes []*wikiNode) bool
+	walk = func(nodes []*wikiNode) bool {
+		for _, node := range nodes {
+			if node.Kind == "text" {
+				for i := strings.IndexByte(node.Text, '{'); i >= 0; {
+					if _, _, _, ok := parseWikiMacroTag(node.Text[i:]); ok {
+						return true
+					}
+					next := strings.IndexByte(node.Text[i+1:], '{')
+					if next < 0 {
+						break
+					}
+					i += next + 1
+				}
+			}
+			if walk(node.Children) {
+				return true
+			}
+		}
+		return false
+	}
+	return walk(parseWikiMarkup(text))
+}
+
+// addHardLineBreaks は空行以外の行末に半角スペース2個を付けて改行を保持する
+// 古いチケットと新しいチケットで改行処理が違っていたため、明示的にスペース2個を挿入する方式に統一している
+func addHardLineBreaks(text string) string {
+	var sb strings.Builder
+	sb.Grow(len(text) + strings.Count(text, "\n")*2)
+	lineStart := 0
+	for i := 0; i < len(text); i++ {
+		if text[i] != '\n' {
+			continue
+		}
+		sb.WriteString(text[lineStart:i])
+		if i > lineStart {
+			sb.WriteString("  ")
+		}
+		sb.WriteByte('\n')
+		lineStart = i + 1
+	}
+	sb.WriteString(text[lineStart:])
+	return sb.String()
+}
+
+// mapStatusColor はJIRAの色名をCSSクラス名にマッピング
+func mapStatusColor(color string) string {
+	colorMap := map[string]string{
+		"green":     "status-green",
+		"yellow":    "status-yellow",
+		"red":       "status-red",
+		"blue":      "status-blue",
+		"blue-gray": "status-blue",
+		"grey":      "status-gray",
+		"gray":      "status-gray",
+	}
+	return colorMap[color]
+}
+
+// statusLabelColorMap はカスタムステータスラベルの16進数カラーコードをCSSクラス名にマッピング
+var statusLabelColorMap = map[string]string{
+	"#ff991f": "status-label-warning", // オレンジ/警告
+	"#00b8d9": "status-label-teal",    // ティール/OK
+	"#36b37e": "status-label-success", // 緑/成功
+	"#ff5630": "status-label-danger",  // 赤/危険
+	"#6554c0": "status-label-purple",  // 紫
+	"#97a0af": "status-label-gray",    // グレー
+}
+
+// getPanelClass はbgColorからCSSクラスを判別
+func getPanelClass(bgColor string) string {
+	bgColor = strings.ToLower(strings.TrimSpace(bgColor))
+	if !strings.HasPrefix(bgColor, "#") {
+		bgColor = "#" + bgColor
+	}
+
+	switch bgColor {
+	case "#ffebe6":
+		return "panel-error"
+	case "#e3fcef":
+		return "panel-success"
+	case "#fffae6":
+		return "panel-warning"
+	case "#deebff":
+		return "panel-info"
+	default:
+		return "panel-info"
+	}
+}
+
+// parsePanelParams はマクロのパラメータ文字列（key=value を | で区切ったもの）を解析
+func parsePanelParams(paramStr string) map[string]string {
+	params := make(map[string]string)
+	for _, param := range strings.Split(paramStr, "|") {
+		key, value, ok := strings.Cut(param, "=")
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if ok && key != "" && value != "" {
+			params[key] = value
+		}
+	}
+	return params
+}
+
+// getAdmonitionClass はadmonitionタイプからCSSクラスを取得
+func getAdmonitionClass(admonitionType string) string {
+	switch strings.ToLower(admonitionType) {
+	case "note":
+		return "panel-note"
+	case "info":
+		return "panel-info"
+	case "warning":
+		return "panel-warning"
+	case "tip":
+		return "panel-success"
+	default:
+		return "panel-info"
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// wikiBlockOutline はブロックの種類の一覧（テーブルは行数付き）を返す
+func wikiBlockOutline(blocks []*wikiNode) string {
+	outline := make([]string, len(blocks))
+	for i, block := range blocks {
+		outline[i] = block.Kind
+		if block.Kind == "table" {
+			outline[i] = fmt.Sprintf("table(%d)", len(block.Children))
+		}
+	}
+	return strings.Join(outline, ",")
+}
+
+// TestParseWikiMarkup_Tables はテーブルのブロックの範囲（セル内改行・空行・前後のテキスト）の解析をテスト
+func TestParseWikiMarkup_Tables(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"ヘッダー付きテーブル（基本）", "||Header 1||Header 2||\n|Data 1|Data 2|", "table(2)"},
+		{"ヘッダー無しテーブル（1行）", "|Data 1|Data 2|", "table(1)"},
+		{"ヘッダー無しテーブル（複数行）", "|Data 1|Data 2|\n|Data 3|Data 4|", "table(2)"},
+		{"セル内改行を含むヘッダー付きテーブル", "||Header||\n|Line1\nLine2|", "table(2)"},
+		{"セル内改行を含むヘッダー無しテーブル", "|Line1\nLine2|", "table(1)"},
+		{"ヘッダー付きとヘッダー無しが混在", "||Header||\n|Data 1|\n\n|Data 2|\n|Data 3|", "table(2),blank,table(2)"},
+		{"テーブルが無い場合", "This is normal text", "paragraph"},
+		{"空の入力", "", "blank"},
+		{"テーブルの前後にテキストがある場合", "Text before\n|Data|\nText after", "paragraph,table(1),paragraph"},
+		{"空行で区切られた複数のテーブル", "|Table 1|\n\n|Table 2|", "table(1),blank,table(1)"},
+		{"ヘッダー付きテーブル（複数行）", "||H1||H2||\n|A1|A2|\n|B1|B2|", "table(3)"},
+		{"複数のヘッダー無しテーブル", "|T1 R1|\n|T1 R2|\n\n|T2 R1|\n|T2 R2|", "table(2),blank,table(2)"},
+		{"テーブルとテキストが混在", "Start\n||Header||\n|Data|\nMiddle\n|Row1|\n|Row2|\nEnd", "paragraph,table(2),paragraph,table(2),paragraph"},
+		{"途中の見出し行で新しいテーブル", "||H1||\n|A|\n||H2||\n|B|", "table(2),table(2)"},
+		{"末尾が|でない行", "|A|B\n|C|D|", "table(2)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wikiBlockOutline(parseWikiMarkup(tt.input)); got != tt.expected {
+				t.Errorf("parseWikiMarkup() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestConvertJIRAMarkupToMarkdown_Nesting はブロックの入れ子と、正規表現による変換で崩れていたケースをテスト
+func TestConvertJIRAMarkupToMarkdown_Nesting(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "パネル内のリスト",
+			input:    "{panel:title=手順}\n* 手順1\n** *注意*\n{panel}",
+			expected: "<div class=\"panel panel-info\"><div class=\"panel-title\">手順</div><div class=\"panel-body\">  \n- 手順1  \n    - **注意**  \n</div></div>",
+		},
+		{
+			name:     "引用内のテーブル",
+			input:    "{quote}\n||項目||値||\n|A|1|\n{quote}",
+			expected: ">  \n> | 項目 | 値 |  \n> | ------ | ------ |  \n> | A | 1 |  \n>",
+		},
+		{
+			name:     "テーブルのセル内の装飾とリンク",
+			input:    "||項目||説明||\n|*太字*|[リンク|https://example.com] と {{code}}|",
+			expected: "| 項目 | 説明 |  \n| ------ | ------ |  \n| **太字** | [リンク](https://example.com) と `code` |",
+		},
+		{
+			name:     "パネル内のコードブロック",
+			input:    "{note}\n{code:go}\nx := *p\n{code}\n{note}",
+			expected: "<div class=\"panel panel-note\"><div class=\"panel-body\">  \n```go  \n\nx := *p  \n\n```  \n</div></div>",
+		},
+		{
+			name:     "コードブロック内の記法は変換しない",
+			input:    "{noformat}\n*not bold* _not italic_ [~accountid:x]\n{noformat}",
+			expected: "```  \n\n*not bold* _not italic_ [~accountid:x]  \n\n```",
+		},
+		{
+			name:     "プレースホルダーに似た文字列",
+			input:    "__TABLE_0__ と __CODE_0__ と ___LIST_0___",
+			expected: "__TABLE_0__ と __CODE_0__ と ___LIST_0___",
+		},
+		{
+			name:     "画像のファイル名とURLの中の記号",
+			input:    "!screen_shot_1.png|thumbnail! https://example.com/a_b_c/d-e-f",
+			expected: "!screen_shot_1.png|thumbnail! https://example.com/a_b_c/d-e-f",
+		},
+		{
+			name:     "番号付きリストの中の箇条書き",
+			input:    "# 手順\n#* 補足",
+			expected: "1. 手順  \n    - 補足",
+		},
+		{
+			name:     "CRLFの改行",
+			input:    "h2. 見出し\r\n*太字*\r\n",
+			expected: "## 見出し  \n**太字**  \n",
+		},
+		{
+			name:     "行の途中のコードブロック",
+			input:    "例: {code}ls -la{code} を実行",
+			expected: "例:   \n```  \nls -la  \n```  \n を実行",
+		},
+		{
+			name:     "タイトル付きのコードブロック",
+			input:    "{code:title=Main.java|borderStyle=solid}\nclass Main {}\n{code}",
+			expected: "<div class=\"code-title\">Main.java</div>  \n```  \n\nclass Main {}  \n\n```",
+		},
+		{
+			name:     "終了タグのないマクロ",
+			input:    "{code:java}\n*太字*",
+			expected: "{code:java}  \n**太字**",
+		},
+		{
+			name:     "終了タグのないステータス",
+			input:    "{status:colour=Green|title=完了}",
+			expected: `<span class="status status-green">完了</span>`,
+		},
+		{
+			name:     "色指定の中の装飾",
+			input:    "{color:red}*重要* な変更{color}",
+			expected: `<span style="color:red">**重要** な変更</span>`,
+		},
+		{
+			name:     "空白を含む取り消し線",
+			input:    "これは -古い 説明- です",
+			expected: "これは ~~古い 説明~~ です",
+		},
+		{
+			name:     "上付きと下付き",
+			input:    "x^2^ と H~2~O と ~~そのまま~~",
+			expected: "x<sup>2</sup> と H<sub>2</sub>O と ~~そのまま~~",
+		},
+	}
+
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mw.convertJIRAMarkupToMarkdown(tt.input); got != tt.expected {
+				t.Errorf("convertJIRAMarkupToMarkdown()\n実際: %q\n期待: %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+// TestConvertJIRAMarkupToMarkdown_Constructs は展開・アンカー・水平線・強制改行・下線・引用元・絵文字・リンクの変換をテスト
+func TestConvertJIRAMarkupToMarkdown_Constructs(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "展開（タイトル付き）",
+			input:    "{expand:詳細を表示}\n* 項目\n{expand}",
+			expected: "<details>  \n<summary>詳細を表示</summary>  \n\n- 項目  \n\n</details>",
+		},
+		{
+			name:     "展開（title=で指定）",
+			input:    "{expand:title=<ログ>}本文{expand}",
+			expected: "<details>  \n<summary>&lt;ログ&gt;</summary>  \n\n本文  \n\n</details>",
+		},
+		{
+			name:     "アンカーとアンカーへのリンク",
+			input:    "{anchor:setup}h2. 準備\n[#setup] と [準備|#setup] と [#手順 1]",
+			expected: "<a id=\"setup\"></a>h2. 準備  \n[setup](#setup) と [準備](#setup) と [手順 1](#%E6%89%8B%E9%A0%86%201)",
+		},
+		{
+			name:     "1行の引用",
+			input:    "bq. *引用* した文\n本文",
+			expected: "> **引用** した文  \n本文",
+		},
+		{
+			name:     "水平線",
+			input:    "上\n----\n下",
+			expected: "上  \n***  \n下",
+		},
+		{
+			name:     "強制改行",
+			input:    "1行目\\\\2行目",
+			expected: "1行目  \n2行目",
+		},
+		{
+			name:     "テーブルのセル内の強制改行",
+			input:    "|a\\\\b|c|",
+			expected: "|   |   |  \n| ------ | ------ |  \n| a<br>b | c |",
+		},
+		{
+			name:     "下線",
+			input:    "これは +重要+ です",
+			expected: "これは <u>重要</u> です",
+		},
+		{
+			name:     "式の中の+は下線にしない",
+			input:    "1+2+3 と C++ と a + b + c",
+			expected: "1+2+3 と C++ と a + b + c",
+		},
+		{
+			name:     "引用元",
+			input:    "??Jira 公式ドキュメント?? より",
+			expected: "<cite>Jira 公式ドキュメント</cite> より",
+		},
+		{
+			name:     "疑問符の連続は引用元にしない",
+			input:    "本当?? 本当??",
+			expected: "本当?? 本当??",
+		},
+		{
+			name:     "絵文字",
+			input:    "(/) 完了 (x) 失敗 (!) 注意 (i) 情報 :) ;) (y) (flag)",
+			expected: "✅ 完了 ❌ 失敗 ⚠️ 注意 ℹ️ 情報 🙂 😉 👍 🚩",
+		},
+		{
+			name:     "関数呼び出しやURLは絵文字にしない",
+			input:    "f(x) と key:Data と (xyz) と https://example.com/:D",
+			expected: "f(x) と key:Data と (xyz) と https://example.com/:D",
+		},
+		{
+			name:     "添付ファイルへのリンクはWiki記法のまま残す",
+			input:    "[^仕様書.pdf] と [*仕様*|^spec.pdf]",
+			expected: "[^仕様書.pdf] と [**仕様**|^spec.pdf]",
+		},
+		{
+			name:     "メールアドレスへのリンク",
+			input:    "[mailto:user@example.com] と [問い合わせ|mailto:support@example.com]",
+			expected: "[user@example.com](mailto:user@example.com) と [問い合わせ](mailto:support@example.com)",
+		},
+		{
+			name:     "表示テキストのないURLのリンク",
+			input:    "[https://example.com/a_b]",
+			expected: "[https://example.com/a_b](https://example.com/a_b)",
+		},
+		{
+			name:     "リンク先でない角括弧はそのまま",
+			input:    "[WIP] 対応中",
+			expected: "[WIP] 対応中",
+		},
+	}
+
+	mw := NewMarkdownWriter(context.Background(), "", "", nil, createTestConfig())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mw.convertJIRAMarkupToMarkdown(tt.input); got != tt.expected {
+				t.Errorf("convertJIRAMarkupToMarkdown()\n実際: %q\n期待: %q", got, tt.expected)
+			}
+		})
+	}
+}